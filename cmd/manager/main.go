@@ -13,6 +13,7 @@ import (
 
 	"github.com/acquia/fn-drupal-operator/pkg/apis"
 	"github.com/acquia/fn-drupal-operator/pkg/controller"
+	"github.com/acquia/fn-drupal-operator/pkg/webhook"
 
 	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
@@ -119,6 +120,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup the defaulting and validating webhooks, and the certificate they are served with
+	if err := webhook.AddToManager(mgr); err != nil {
+		log.Error(err, "Failed to set up webhooks")
+		os.Exit(1)
+	}
+
 	// FIXME: commented out to resolve https://github.com/operator-framework/operator-sdk/issues/1858
 	//	if err = serveCRMetrics(cfg); err != nil {
 	//		log.Info("Could not generate and serve custom resource metrics", "error", err.Error())
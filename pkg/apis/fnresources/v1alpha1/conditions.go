@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of a Condition reported in the status of a resource
+type ConditionType string
+
+const (
+	// ConditionReady is True when a resource is fully reconciled and serving
+	ConditionReady ConditionType = "Ready"
+	// ConditionProgressing is True while a change to a resource is still being rolled out
+	ConditionProgressing ConditionType = "Progressing"
+)
+
+// Condition describes one aspect of the observed state of a resource
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"` // +optional
+	Reason             string                 `json:"reason,omitempty"`             // +optional
+	Message            string                 `json:"message,omitempty"`            // +optional
+}
+
+// GetCondition returns the Condition of the given type, or nil if it isn't present.
+func GetCondition(conditions []Condition, t ConditionType) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == t {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue returns true if the Condition of the given type is present and has status True.
+func IsConditionTrue(conditions []Condition, t ConditionType) bool {
+	c := GetCondition(conditions, t)
+	return c != nil && c.Status == corev1.ConditionTrue
+}
+
+// SetCondition adds or updates the Condition of the given type. LastTransitionTime is only moved forward when the
+// status of the Condition changes, so that repeatedly setting the same Condition is a no-op.
+func SetCondition(conditions *[]Condition, t ConditionType, status corev1.ConditionStatus, reason, message string) (changed bool) {
+	existing := GetCondition(*conditions, t)
+	if existing == nil {
+		*conditions = append(*conditions, Condition{
+			Type:               t,
+			Status:             status,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            message,
+		})
+		return true
+	}
+
+	if existing.Status != status {
+		existing.Status = status
+		existing.LastTransitionTime = metav1.Now()
+		changed = true
+	}
+	if existing.Reason != reason || existing.Message != message {
+		existing.Reason = reason
+		existing.Message = message
+		changed = true
+	}
+	return changed
+}
+
+// ConditionStatusFor converts a boolean into the corresponding ConditionStatus
+func ConditionStatusFor(b bool) corev1.ConditionStatus {
+	if b {
+		return corev1.ConditionTrue
+	}
+	return corev1.ConditionFalse
+}
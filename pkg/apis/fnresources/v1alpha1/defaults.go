@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+)
+
+// DefaultTargetCPUUtilizationPercentage is the CPU utilization the Drupal HorizontalPodAutoscaler scales to by default
+const DefaultTargetCPUUtilizationPercentage int32 = 50
+
+// The functions below are called by the generated SetObjectDefaults_* functions, which the mutating admission webhook
+// applies to new and updated resources. Controllers still handle unset fields, for resources created before the
+// webhook was installed.
+
+func SetDefaults_SpecDrupal(obj *SpecDrupal) {
+	if obj.TargetCPUUtilizationPercentage == nil {
+		target := DefaultTargetCPUUtilizationPercentage
+		obj.TargetCPUUtilizationPercentage = &target
+	}
+}
+
+func SetDefaults_SiteSpec(obj *SiteSpec) {
+	if obj.DeletionPolicy == "" {
+		obj.DeletionPolicy = DeletionPolicyDelete
+	}
+}
+
+func SetDefaults_SiteRoute(obj *SiteRoute) {
+	if obj.PathType == "" {
+		obj.PathType = PathTypePrefix
+	}
+}
+
+func SetDefaults_CronSpec(obj *CronSpec) {
+	if obj.ConcurrencyPolicy == "" {
+		obj.ConcurrencyPolicy = batchv1b1.ForbidConcurrent
+	}
+}
+
+func SetDefaults_BackupsSpec(obj *BackupsSpec) {
+	obj.Method = obj.Method.OrDefault()
+}
@@ -5,9 +5,13 @@ package v1alpha1
 // SEE: https://book.kubebuilder.io/reference/generating-crd.html
 
 import (
+	"fmt"
+	"hash/fnv"
+
 	v1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type EnvironmentId string
@@ -29,6 +33,15 @@ type DrupalEnvironmentSpec struct {
 	Apache   SpecApache   `json:"apache"`
 	Phpfpm   SpecPhpFpm   `json:"phpfpm"`
 	ProxySQL SpecProxySQL `json:"proxySQL"`
+
+	// Maintenance puts the Sites of the environment that don't set their own spec.maintenance in maintenance mode
+	Maintenance *MaintenanceSpec `json:"maintenance,omitempty"` // +optional
+
+	// RollbackTo is the revision of an entry of status.releases to redeploy, in place of the tags and git ref of the
+	// spec. Drupal keeps running that release until RollbackTo is cleared.
+	RollbackTo *int64 `json:"rollbackTo,omitempty"` // +optional
+	// ReleaseHistoryLimit is the number of entries kept in status.releases. It defaults to 10.
+	ReleaseHistoryLimit *int32 `json:"releaseHistoryLimit,omitempty"` // +optional
 }
 
 // SpecDrupal represents drupalenvironment.spec.drupal
@@ -41,6 +54,90 @@ type SpecDrupal struct {
 
 	Liveness  HTTPProbe `json:"livenessProbe"`
 	Readiness HTTPProbe `json:"readinessProbe"`
+
+	// RolloutStrategy is the way new Drupal pods replace the old ones. It defaults to a blue/green rollout promoted
+	// automatically after 10 seconds.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"` // +optional
+	// Analysis checks each new release before a blue/green Rollout is promoted, or while a canary Rollout runs. When
+	// the checks fail, the Rollout is aborted and the previous release keeps serving.
+	Analysis *AnalysisSpec `json:"analysis,omitempty"` // +optional
+}
+
+// AnalysisSpec represents drupalenvironment.spec.drupal.analysis. At least one of HTTP and ErrorRate is set.
+// +k8s:openapi-gen=true
+type AnalysisSpec struct {
+	HTTP      *HTTPAnalysis      `json:"http,omitempty"`      // +optional
+	ErrorRate *ErrorRateAnalysis `json:"errorRate,omitempty"` // +optional
+	// IntervalSeconds is the time between measurements. It defaults to 30.
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"` // +optional
+	// Count is the number of measurements. It defaults to 5.
+	Count int32 `json:"count,omitempty"` // +optional
+	// FailureLimit is the number of failed measurements tolerated before the analysis fails
+	FailureLimit int32 `json:"failureLimit,omitempty"` // +optional
+}
+
+// HTTPAnalysis requests a path of each Site of the environment from the new pods, with the Host header set to each of
+// the Site's domains, and expects a status code
+type HTTPAnalysis struct {
+	// Path is relative to the path Drupal serves the Site under. It defaults to "/".
+	Path string `json:"path,omitempty"` // +optional
+	// ExpectedStatus defaults to 200
+	ExpectedStatus int32 `json:"expectedStatus,omitempty"` // +optional
+	// TimeoutSeconds defaults to 10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"` // +optional
+}
+
+// ErrorRateAnalysis queries an error rate from a Prometheus server
+type ErrorRateAnalysis struct {
+	// Address is the URL of the Prometheus server, such as http://prometheus.monitoring:9090
+	Address string `json:"address"`
+	// Query returns the error rate of the environment as a single value between 0 and 1
+	Query string `json:"query"`
+	// MaxErrorRate is the highest error rate that passes, as a decimal such as "0.05"
+	MaxErrorRate string `json:"maxErrorRate"`
+}
+
+// RolloutStrategy represents drupalenvironment.spec.drupal.rolloutStrategy. Exactly one of BlueGreen and Canary is set.
+// +k8s:openapi-gen=true
+type RolloutStrategy struct {
+	BlueGreen *BlueGreenRolloutStrategy `json:"blueGreen,omitempty"` // +optional
+	Canary    *CanaryRolloutStrategy    `json:"canary,omitempty"`    // +optional
+}
+
+// BlueGreenRolloutStrategy brings up the new Drupal pods alongside the old ones, and switches the drupal Service over
+// to them once they are promoted
+type BlueGreenRolloutStrategy struct {
+	// AutoPromotion promotes the new pods once they are ready and AutoPromotionSeconds have passed. Without it, the
+	// Rollout pauses until it is promoted with "kubectl argo rollouts promote". It defaults to true.
+	AutoPromotion *bool `json:"autoPromotion,omitempty"` // +optional
+	// AutoPromotionSeconds is the delay before automatic promotion. It defaults to 10.
+	AutoPromotionSeconds *int32 `json:"autoPromotionSeconds,omitempty"` // +optional
+	// ScaleDownDelaySeconds is how long the old pods are kept after promotion, so that the Service endpoints have
+	// been updated before they go away. It defaults to 30.
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty"` // +optional
+}
+
+// CanaryRolloutStrategy moves traffic to the new Drupal pods in steps. Without a traffic router, the weight of a step is
+// approximated by the share of new pods behind the drupal Service.
+type CanaryRolloutStrategy struct {
+	// Steps are run in order. Once they have all been run, the new pods get all of the traffic.
+	Steps []CanaryStep `json:"steps"`
+	// MaxSurge is the number or percentage of pods that can be created above the desired replicas. It defaults to 25%.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"` // +optional
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during the rollout. It defaults to 25%.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"` // +optional
+}
+
+// CanaryStep either sets the percentage of traffic going to the new pods or pauses the rollout
+type CanaryStep struct {
+	SetWeight *int32       `json:"setWeight,omitempty"` // +optional
+	Pause     *CanaryPause `json:"pause,omitempty"`     // +optional
+}
+
+// CanaryPause pauses a canary rollout for a duration or, without one, until it is promoted with
+// "kubectl argo rollouts promote"
+type CanaryPause struct {
+	DurationSeconds *int32 `json:"durationSeconds,omitempty"` // +optional
 }
 
 // SpecApache represents drupalenvironment.spec.apache
@@ -84,9 +181,136 @@ type HTTPProbe struct {
 	PeriodSeconds    int32  `json:"periodSeconds"`
 }
 
+const (
+	// ConditionProxySQLReady is True when all of the environment's ProxySQL replicas are ready
+	ConditionProxySQLReady ConditionType = "ProxySQLReady"
+	// ConditionStorageBound is True when all of the environment's PersistentVolumeClaims are bound
+	ConditionStorageBound ConditionType = "StorageBound"
+	// ConditionRolloutHealthy is True when the Drupal Rollout has available replicas and hasn't been aborted
+	ConditionRolloutHealthy ConditionType = "RolloutHealthy"
+	// ConditionDeployHooksSucceeded is True when every Site has run its deploy hook for the current release
+	ConditionDeployHooksSucceeded ConditionType = "DeployHooksSucceeded"
+	// ConditionImagesResolved is True when the images of the current release have been resolved to their digests
+	ConditionImagesResolved ConditionType = "ImagesResolved"
+)
+
+// DeployHooksPhase is the progress of the deploy hooks of a release
+type DeployHooksPhase string
+
+const (
+	DeployHooksRunning   DeployHooksPhase = "Running"
+	DeployHooksSucceeded DeployHooksPhase = "Succeeded"
+	DeployHooksFailed    DeployHooksPhase = "Failed"
+)
+
 // DrupalEnvironmentStatus defines the observed state of DrupalEnvironment
 // +k8s:openapi-gen=true
 type DrupalEnvironmentStatus struct {
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"` // +optional
+	Conditions         []Condition  `json:"conditions,omitempty"`         // +optional
+	Drupal             StatusDrupal `json:"drupal,omitempty"`             // +optional
+	// DeployHooks is the progress of the deploy hooks the Sites run for the current release
+	DeployHooks *DeployHooksStatus `json:"deployHooks,omitempty"` // +optional
+	// Releases are the latest changes to the images and git ref of the Drupal pods, most recent first
+	Releases []Release `json:"releases,omitempty"` // +optional
+	// Images are the images of the current release, pinned to the digests their tags had when it was rolled out
+	Images *ResolvedImages `json:"images,omitempty"` // +optional
+}
+
+// ResolvedImages represents drupalenvironment.status.images. The Drupal pods, and the cron and on-demand jobs of the
+// Sites, run these images rather than whatever the tags point to when they are pulled.
+type ResolvedImages struct {
+	// Drupal is the customer image, which also runs the Sites' jobs
+	Drupal ResolvedImage `json:"drupal"`
+	Apache ResolvedImage `json:"apache"`
+	PhpFpm ResolvedImage `json:"phpfpm"`
+}
+
+// ResolvedImage is an image reference with a tag, and the digest the tag was resolved to
+type ResolvedImage struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
+}
+
+// Pinned returns the image reference pinned to its digest
+func (i ResolvedImage) Pinned() string {
+	return i.Image + "@" + i.Digest
+}
+
+// ReleaseOutcome is how the rollout of a release ended
+type ReleaseOutcome string
+
+const (
+	ReleaseProgressing ReleaseOutcome = "Progressing"
+	ReleaseDeployed    ReleaseOutcome = "Deployed"
+	ReleaseAborted     ReleaseOutcome = "Aborted"
+	// ReleaseSuperseded is a release replaced by the next one before its rollout completed
+	ReleaseSuperseded ReleaseOutcome = "Superseded"
+)
+
+// Release represents an entry of drupalenvironment.status.releases, recorded each time the operator changes the
+// images or git ref of the Drupal pods
+type Release struct {
+	Revision  int64  `json:"revision"`
+	DrupalTag string `json:"drupalTag"`
+	ApacheTag string `json:"apacheTag"`
+	PhpFpmTag string `json:"phpfpmTag"`
+	GitRef    string `json:"gitRef"`
+	// Images are the images of the Drupal pod's containers
+	Images  []ReleaseImage `json:"images"`
+	Time    metav1.Time    `json:"time"`
+	Outcome ReleaseOutcome `json:"outcome"`
+	// ChangedBy is the field manager that last set the fields of the spec the release came from
+	ChangedBy string `json:"changedBy,omitempty"` // +optional
+	// RollbackOf is the revision redeployed by spec.rollbackTo
+	RollbackOf *int64 `json:"rollbackOf,omitempty"` // +optional
+}
+
+// ReleaseImage is the image a container of a release runs. Digest is set once the pods of the release have pulled
+// the image.
+type ReleaseImage struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Digest    string `json:"digest,omitempty"` // +optional
+}
+
+// DeployHooksStatus represents drupalenvironment.status.deployHooks. When spec.drupal.tag or spec.gitRef changes, each
+// Site of the environment runs drush updatedb, config:import and cache:rebuild with the new code.
+type DeployHooksStatus struct {
+	// Release identifies the tag and git ref the hooks are run for
+	Release string           `json:"release"`
+	Tag     string           `json:"tag"`
+	GitRef  string           `json:"gitRef"`
+	Phase   DeployHooksPhase `json:"phase"`
+	// Sites is the number of Sites that run the hook, of which SucceededSites have succeeded
+	Sites          int32 `json:"sites"`
+	SucceededSites int32 `json:"succeededSites"`
+	// FailedSites are the Sites whose hook failed
+	FailedSites []string `json:"failedSites,omitempty"` // +optional
+}
+
+// StatusDrupal represents drupalenvironment.status.drupal
+type StatusDrupal struct {
+	Tag               string `json:"tag,omitempty"` // +optional
+	Replicas          int32  `json:"replicas"`
+	UpdatedReplicas   int32  `json:"updatedReplicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+	DesiredReplicas   int32  `json:"desiredReplicas"`
+	// Rollback is the last release whose rollout was aborted
+	Rollback *RollbackStatus `json:"rollback,omitempty"` // +optional
+}
+
+// RollbackStatus represents drupalenvironment.status.drupal.rollback, a release whose rollout was aborted and the
+// release that kept serving in its place
+type RollbackStatus struct {
+	// Reason is AnalysisFailed, DeployHooksFailed or Aborted
+	Reason          string      `json:"reason"`
+	AbortedRevision string      `json:"abortedRevision"`
+	AbortedTag      string      `json:"abortedTag"`
+	StableRevision  string      `json:"stableRevision,omitempty"` // +optional
+	StableTag       string      `json:"stableTag,omitempty"`      // +optional
+	Time            metav1.Time `json:"time"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -94,6 +318,12 @@ type DrupalEnvironmentStatus struct {
 // DrupalEnvironment is the Schema for the drupalenvironments API
 // +kubebuilder:resource:shortName=drenv;drenvs
 // +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Tag",type="string",JSONPath=".status.drupal.tag"
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".status.drupal.desiredReplicas"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.drupal.availableReplicas"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type DrupalEnvironment struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -126,6 +356,13 @@ func (e *DrupalEnvironment) SetId(value string) {
 	e.ObjectMeta.Labels[EnvironmentIdLabel] = value
 }
 
+// Release returns an identifier of the code the environment runs, which changes with spec.drupal.tag and spec.gitRef
+func (e DrupalEnvironment) Release() string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Spec.Drupal.Tag + "\x00" + e.Spec.GitRef))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
 func (e DrupalEnvironment) ChildLabels() map[string]string {
 	envLabels := e.GetLabels()
 	if envLabels == nil {
@@ -0,0 +1,131 @@
+package v1alpha1
+
+// IMPORTANT: Run "operator-sdk generate k8s && operator-sdk generate openapi"
+// to regenerate code after modifying this file.
+// SEE: https://book.kubebuilder.io/reference/generating-crd.html
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the DrupalOperatorConfig read by the operator. Others are ignored.
+const OperatorConfigName = "default"
+
+// ConditionValid is True when a DrupalOperatorConfig has been validated and is in effect
+const ConditionValid ConditionType = "Valid"
+
+// RoutingAPI is the API of the objects routing Site domains to their environment
+type RoutingAPI string
+
+const (
+	// RoutingAPIIngress routes through networking.k8s.io/v1 Ingresses
+	RoutingAPIIngress RoutingAPI = "Ingress"
+	// RoutingAPILegacyIngress routes through extensions/v1beta1 Ingresses, which clusters before Kubernetes 1.14 serve
+	RoutingAPILegacyIngress RoutingAPI = "LegacyIngress"
+	// RoutingAPIHTTPRoute routes through Gateway API HTTPRoutes attached to the configured Gateway
+	RoutingAPIHTTPRoute RoutingAPI = "HTTPRoute"
+)
+
+// IngressDefaults are used for Sites that don't set spec.ingressClass or spec.certIssuer. RoutingAPI is discovered
+// from the API server when it isn't set: networking.k8s.io/v1 Ingresses if they are served, and extensions/v1beta1
+// Ingresses otherwise. The HTTPRoute API requires a Gateway.
+type IngressDefaults struct {
+	Class      string            `json:"class,omitempty"`      // +optional
+	CertIssuer string            `json:"certIssuer,omitempty"` // +optional
+	RoutingAPI RoutingAPI        `json:"routingAPI,omitempty"` // +optional
+	Gateway    *GatewayReference `json:"gateway,omitempty"`    // +optional
+}
+
+// GatewayReference names the Gateway, and optionally the listener of it, that HTTPRoutes attach to
+type GatewayReference struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	SectionName string `json:"sectionName,omitempty"` // +optional
+}
+
+// StorageDefaults configure the volumes created for DrupalEnvironments
+type StorageDefaults struct {
+	// FilesStorageClass is the storage class of the shared files volume. It only applies to volumes created after it is
+	// changed.
+	FilesStorageClass string `json:"filesStorageClass,omitempty"` // +optional
+}
+
+// SchedulingDefaults configure where the pods created by the operator run
+type SchedulingDefaults struct {
+	// NodeSelector replaces the default node selector when set. An empty map schedules pods on any node.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"` // +optional
+}
+
+// SecretReference names a Secret in a given namespace
+type SecretReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// DatabaseDefaults configure the connection to the cluster DB. The admin Secret must have the keys "username",
+// "password", "host" and "port"; Host and Port override the values in it.
+type DatabaseDefaults struct {
+	AdminSecret *SecretReference `json:"adminSecret,omitempty"` // +optional
+	Host        string           `json:"host,omitempty"`        // +optional
+	Port        string           `json:"port,omitempty"`        // +optional
+}
+
+// RegistryDefaults configure the images run by the operator. Images other than S3Image and MaintenanceImage are
+// repositories, without a tag.
+type RegistryDefaults struct {
+	CustomerImageRoot string   `json:"customerImageRoot,omitempty"` // +optional
+	ApacheImage       string   `json:"apacheImage,omitempty"`       // +optional
+	PhpFpmImage       string   `json:"phpFpmImage,omitempty"`       // +optional
+	ProxySQLImage     string   `json:"proxySQLImage,omitempty"`     // +optional
+	S3Image           string   `json:"s3Image,omitempty"`           // +optional
+	MaintenanceImage  string   `json:"maintenanceImage,omitempty"`  // +optional
+	ImagePullSecrets  []string `json:"imagePullSecrets,omitempty"`  // +optional
+}
+
+// DrupalOperatorConfigSpec defines the desired state of DrupalOperatorConfig. Fields that aren't set keep the
+// operator's built-in defaults.
+// +k8s:openapi-gen=true
+type DrupalOperatorConfigSpec struct {
+	Ingress    IngressDefaults    `json:"ingress,omitempty"`    // +optional
+	Storage    StorageDefaults    `json:"storage,omitempty"`    // +optional
+	Scheduling SchedulingDefaults `json:"scheduling,omitempty"` // +optional
+	Database   DatabaseDefaults   `json:"database,omitempty"`   // +optional
+	Registry   RegistryDefaults   `json:"registry,omitempty"`   // +optional
+}
+
+// DrupalOperatorConfigStatus defines the observed state of DrupalOperatorConfig
+// +k8s:openapi-gen=true
+type DrupalOperatorConfigStatus struct {
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"` // +optional
+	Conditions         []Condition `json:"conditions,omitempty"`         // +optional
+	// Errors lists the problems found when the spec was last validated
+	Errors []string `json:"errors,omitempty"` // +optional
+}
+
+// DrupalOperatorConfig is the Schema for the drupaloperatorconfigs API
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=drconfig
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"Valid\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type DrupalOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DrupalOperatorConfigSpec   `json:"spec,omitempty"`
+	Status DrupalOperatorConfigStatus `json:"status,omitempty"` // +optional
+}
+
+// DrupalOperatorConfigList contains a list of DrupalOperatorConfig
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type DrupalOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DrupalOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DrupalOperatorConfig{}, &DrupalOperatorConfigList{})
+}
@@ -10,15 +10,23 @@ import (
 	batchv1b1 "k8s.io/api/batch/v1beta1"
 	extv1b1 "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// DomainMap maps the hosts of a Site, followed by the path Drupal serves the Site under unless it is /, to its database
 type DomainMap map[string]string
 type SiteId string
 
+// The Service of a DrupalEnvironment serving Drupal, which the routes of its Sites target by default
+const (
+	DrupalServiceName = "drupal"
+	DrupalServicePort = 80
+)
+
 var siteChildLabels = []string{
 	ApplicationIdLabel,
 	EnvironmentIdLabel,
@@ -30,13 +38,86 @@ var siteChildLabels = []string{
 type SiteSpec struct {
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
 	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
-	Domains      []string    `json:"domains"`
-	Environment  string      `json:"environment"`
-	Install      InstallSpec `json:"install,omitempty"`      // +optional
-	Crons        []CronSpec  `json:"crons,omitempty"`        // +optional
-	Tls          bool        `json:"tls,omitempty"`          // +optional
-	IngressClass string      `json:"ingressClass,omitempty"` // +optional
-	CertIssuer   string      `json:"certIssuer,omitempty"`   // +optional
+	Domains        []string         `json:"domains"`
+	Environment    string           `json:"environment"`
+	Install        InstallSpec      `json:"install,omitempty"`        // +optional
+	Crons          []CronSpec       `json:"crons,omitempty"`          // +optional
+	Backups        *BackupsSpec     `json:"backups,omitempty"`        // +optional
+	DeletionPolicy DeletionPolicy   `json:"deletionPolicy,omitempty"` // +optional
+	Tls            bool             `json:"tls,omitempty"`            // +optional
+	DomainTLS      []DomainTLS      `json:"domainTLS,omitempty"`      // +optional
+	HTTPSRedirect  bool             `json:"httpsRedirect,omitempty"`  // +optional
+	HSTS           *HSTSSpec        `json:"hsts,omitempty"`           // +optional
+	Routes         []SiteRoute      `json:"routes,omitempty"`         // +optional
+	Redirects      []SiteRedirect   `json:"redirects,omitempty"`      // +optional
+	Maintenance    *MaintenanceSpec `json:"maintenance,omitempty"`    // +optional
+	IngressClass   string           `json:"ingressClass,omitempty"`   // +optional
+	CertIssuer     string           `json:"certIssuer,omitempty"`     // +optional
+}
+
+// TLS settings of one of the site's domains, overriding spec.tls: the domain is served over TLS unless it is disabled. A
+// domain with a secretName is served with the certificate in that Secret, a kubernetes.io/tls Secret in the site's
+// namespace, rather than one issued by cert-manager.
+// +k8s:openapi-gen=true
+type DomainTLS struct {
+	Domain     string `json:"domain"`
+	Disabled   bool   `json:"disabled,omitempty"`   // +optional
+	SecretName string `json:"secretName,omitempty"` // +optional
+}
+
+// Strict-Transport-Security header sent for the site's domains. MaxAge is in seconds.
+// +k8s:openapi-gen=true
+type HSTSSpec struct {
+	MaxAge            int64 `json:"maxAge"`
+	IncludeSubdomains bool  `json:"includeSubdomains,omitempty"` // +optional
+	Preload           bool  `json:"preload,omitempty"`           // +optional
+}
+
+// RoutePathType is how the path of a route is matched
+type RoutePathType string
+
+const (
+	// PathTypePrefix matches the path and the paths under it, by path segment
+	PathTypePrefix RoutePathType = "Prefix"
+	// PathTypeExact only matches the path itself
+	PathTypeExact RoutePathType = "Exact"
+)
+
+// A path of the site's domains and the backend serving it. When spec.routes is given, the site's domains only route
+// these paths, rather than / to Drupal, and Drupal serves the site under the paths of the routes targeting it.
+// +k8s:openapi-gen=true
+type SiteRoute struct {
+	Path     string        `json:"path"`
+	PathType RoutePathType `json:"pathType,omitempty"` // +optional
+	Target   RouteTarget   `json:"target,omitempty"`   // +optional
+}
+
+// The Service in the site's namespace that a route sends requests to, such as a static file server. An empty target
+// is the Drupal Service of the site's environment.
+// +k8s:openapi-gen=true
+type RouteTarget struct {
+	ServiceName string             `json:"serviceName,omitempty"` // +optional
+	ServicePort intstr.IntOrString `json:"servicePort,omitempty"` // +optional
+}
+
+// A host that redirects to a URL, such as a vanity domain redirecting to the site's canonical domain. Redirect hosts
+// are answered by the ingress controller or Gateway, so Drupal never sees them. Code is 301 (the default) or 302. With
+// preservePath, the path and query of the request are appended to the path of the URL.
+// +k8s:openapi-gen=true
+type SiteRedirect struct {
+	FromHost     string `json:"fromHost"`
+	ToURL        string `json:"toURL"`
+	Code         int32  `json:"code,omitempty"`         // +optional
+	PreservePath bool   `json:"preservePath,omitempty"` // +optional
+}
+
+// Maintenance mode of a site or environment. Drupal is put in maintenance mode, and requests the site's routes would send
+// to Drupal are answered with the message and a 503 status by a maintenance responder, except those from the allowed
+// IP addresses or CIDR ranges, which it passes on to Drupal.
+// +k8s:openapi-gen=true
+type MaintenanceSpec struct {
+	Message    string   `json:"message,omitempty"`    // +optional
+	AllowedIPs []string `json:"allowedIPs,omitempty"` // +optional
 }
 
 // Information to install the site
@@ -62,12 +143,177 @@ type CronSpec struct {
 	Schedule string   `json:"schedule"`
 }
 
+// Scheduled backups of the site. Each run stores a new archive, and archives beyond the retention count or older than
+// the retention age are pruned from the target. A retention count or age of zero doesn't limit retention.
+// +k8s:openapi-gen=true
+type BackupsSpec struct {
+	Schedule       string           `json:"schedule"`
+	Suspend        bool             `json:"suspend,omitempty"` // +optional
+	Method         BackupMethod     `json:"method,omitempty"`  // +optional
+	Target         BackupTarget     `json:"target"`
+	RetentionCount int32            `json:"retentionCount,omitempty"` // +optional
+	RetentionAge   *metav1.Duration `json:"retentionAge,omitempty"`   // +optional
+}
+
+// DeletionPolicy is what happens to a Site's database when the Site is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete drops the database and its user
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicySnapshot backs up the database and files to the target of spec.backups, then drops the database
+	// and its user
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+	// DeletionPolicyRetain leaves the database and its user in place
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// SitePhase is a high-level summary of where a Site is in its lifecycle
+type SitePhase string
+
+const (
+	SitePhasePending               SitePhase = "Pending"
+	SitePhaseWaitingForEnvironment SitePhase = "WaitingForEnvironment"
+	SitePhaseProvisioningDatabase  SitePhase = "ProvisioningDatabase"
+	SitePhaseInstalling            SitePhase = "Installing"
+	SitePhaseReady                 SitePhase = "Ready"
+	SitePhaseDeleting              SitePhase = "Deleting"
+	SitePhaseFailed                SitePhase = "Failed"
+)
+
+const (
+	// ConditionEnvironmentAvailable is True when the Site's DrupalEnvironment and DrupalApplication exist
+	ConditionEnvironmentAvailable ConditionType = "EnvironmentAvailable"
+	// ConditionDatabaseReady is True when the Site's database and user exist in the cluster DB and ProxySQL
+	ConditionDatabaseReady ConditionType = "DatabaseReady"
+	// ConditionDomainMapReady is True when the Site's entries in the domain map ConfigMap and Secret are up to date
+	ConditionDomainMapReady ConditionType = "DomainMapReady"
+	// ConditionCronJobsReady is True when the Site's CronJobs match spec.crons and spec.backups
+	ConditionCronJobsReady ConditionType = "CronJobsReady"
+	// ConditionIngressReady is True when the Site's Ingress is up to date
+	ConditionIngressReady ConditionType = "IngressReady"
+	// ConditionInstalled is True once the Site's install Job has succeeded. It is only set when spec.install is given.
+	ConditionInstalled ConditionType = "Installed"
+	// ConditionDomainConflict is True when some of the Site's domains were claimed first by another Site, so they aren't
+	// published in the domain map or Ingress
+	ConditionDomainConflict ConditionType = "DomainConflict"
+	// ConditionDNSReady is True when the records of the Site's published domains point at its Ingress. It is only set
+	// when the operator manages DNS records.
+	ConditionDNSReady ConditionType = "DNSReady"
+)
+
+// TLSState describes whether a certificate is being served for a host
+type TLSState string
+
+const (
+	TLSDisabled TLSState = "Disabled"
+	TLSPending  TLSState = "Pending"
+	TLSIssued   TLSState = "Issued"
+	TLSExpired  TLSState = "Expired"
+)
+
+// MaintenanceSource is the kind of resource whose spec.maintenance puts a Site in maintenance mode
+type MaintenanceSource string
+
+const (
+	MaintenanceSourceSite        MaintenanceSource = "Site"
+	MaintenanceSourceEnvironment MaintenanceSource = "DrupalEnvironment"
+)
+
+// JobOutcome is the result of an on-demand Job run on a Site
+type JobOutcome string
+
+const (
+	JobRunning   JobOutcome = "Running"
+	JobSucceeded JobOutcome = "Succeeded"
+	JobFailed    JobOutcome = "Failed"
+)
+
 // SiteStatus defines the observed state of Site
 // +k8s:openapi-gen=true
 type SiteStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
+	Phase              SitePhase          `json:"phase,omitempty"`              // +optional
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"` // +optional
+	Conditions         []Condition        `json:"conditions,omitempty"`         // +optional
+	Database           SiteDatabaseStatus `json:"database,omitempty"`           // +optional
+	Install            SiteInstallStatus  `json:"install,omitempty"`            // +optional
+	Backups            SiteBackupsStatus  `json:"backups,omitempty"`            // +optional
+	Hosts              []SiteHostStatus   `json:"hosts,omitempty"`              // +optional
+	Jobs               []SiteJobStatus    `json:"jobs,omitempty"`               // +optional
+	DNSRecords         []SiteDNSRecord    `json:"dnsRecords,omitempty"`         // +optional
+	// RedirectHosts are the hosts of spec.redirects whose routing objects the Site has created
+	RedirectHosts []string              `json:"redirectHosts,omitempty"` // +optional
+	Maintenance   SiteMaintenanceStatus `json:"maintenance,omitempty"`   // +optional
+	// DeployHook is the Job that last brought the Site's database and configuration up to date with a release of its
+	// environment
+	DeployHook *SiteDeployHookStatus `json:"deployHook,omitempty"` // +optional
+}
+
+// SiteDeployHookStatus represents site.status.deployHook
+type SiteDeployHookStatus struct {
+	// Release is the release of the environment the hook was run for, as in drupalenvironment.status.deployHooks
+	Release string     `json:"release"`
+	JobName string     `json:"jobName"`
+	Outcome JobOutcome `json:"outcome"`
+}
+
+// SiteDatabaseStatus represents site.status.database
+type SiteDatabaseStatus struct {
+	Name string `json:"name,omitempty"` // +optional
+	User string `json:"user,omitempty"` // +optional
+}
+
+// SiteInstallStatus represents site.status.install
+type SiteInstallStatus struct {
+	JobName             string       `json:"jobName,omitempty"`             // +optional
+	Outcome             JobOutcome   `json:"outcome,omitempty"`             // +optional
+	AdminPasswordSecret string       `json:"adminPasswordSecret,omitempty"` // +optional
+	CompletionTime      *metav1.Time `json:"completionTime,omitempty"`      // +optional
+}
+
+// SiteMaintenanceStatus represents site.status.maintenance. Enabled is true while the Site's Drupal routes go to the
+// maintenance responder, and DrupalMaintenanceMode is the value of Drupal's system.maintenance_mode state, as last set
+// by the operator's Job.
+type SiteMaintenanceStatus struct {
+	Enabled               bool              `json:"enabled,omitempty"`               // +optional
+	Source                MaintenanceSource `json:"source,omitempty"`                // +optional
+	DrupalMaintenanceMode bool              `json:"drupalMaintenanceMode,omitempty"` // +optional
+	JobName               string            `json:"jobName,omitempty"`               // +optional
+	JobOutcome            JobOutcome        `json:"jobOutcome,omitempty"`            // +optional
+}
+
+// SiteBackupsStatus represents site.status.backups
+type SiteBackupsStatus struct {
+	CronJobName        string       `json:"cronJobName,omitempty"`        // +optional
+	LastScheduleTime   *metav1.Time `json:"lastScheduleTime,omitempty"`   // +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"` // +optional
+}
+
+// SiteHostStatus represents a host served by the Site's Ingress
+type SiteHostStatus struct {
+	Host          string   `json:"host"`
+	TLS           TLSState `json:"tls"`
+	TLSSecretName string   `json:"tlsSecretName,omitempty"` // +optional
+	// CertificateExpiry is the end of the validity of the certificate in the TLS Secret
+	CertificateExpiry *metav1.Time `json:"certificateExpiry,omitempty"` // +optional
+}
+
+// SiteDNSRecord is a record the operator created for one of the Site's domains, which it deletes when the domain is
+// removed or the Site is deleted
+type SiteDNSRecord struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Targets []string `json:"targets"`
+}
+
+// SiteJobStatus records an on-demand Job run on the Site and its outcome
+type SiteJobStatus struct {
+	Name           string       `json:"name"`
+	UID            types.UID    `json:"uid"`
+	Executable     string       `json:"executable,omitempty"` // +optional
+	Outcome        JobOutcome   `json:"outcome"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`      // +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"` // +optional
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -75,6 +321,10 @@ type SiteStatus struct {
 // Site is the Schema for the sites API
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Environment",type="string",JSONPath=".spec.environment"
+// +kubebuilder:printcolumn:name="Maintenance",type="boolean",JSONPath=".status.maintenance.enabled"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Site struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -129,43 +379,188 @@ func sanitize(old string) string {
 	return old
 }
 
-// The return value of this function is used directly in SQL statements.  It
-// must return a string that is safe for this purpose.
+// The return value of this function is quoted in SQL statements, and is validated against the MySQL identifier rules
+// before the database is provisioned.
 func (s Site) DatabaseName() string {
 	return sanitize(s.Name)
 }
 
-// The return value of this function is used directly in SQL statements.  It
-// must return a string that is safe for this purpose.
+// The return value of this function is quoted in SQL statements, and is validated against the MySQL identifier rules
+// before the database user is provisioned.
 func (s Site) DatabaseUser() string {
 	return sanitize(s.Name)
 }
 
-func (s *Site) DomainMap() DomainMap {
-	m := make(DomainMap, len(s.Spec.Domains))
-	for _, domain := range s.Spec.Domains {
-		m[domain] = s.DatabaseName()
+// Routes returns the Site's routes with their defaults. A Site without spec.routes routes / to Drupal.
+func (s *Site) Routes() []SiteRoute {
+	if len(s.Spec.Routes) == 0 {
+		return []SiteRoute{{Path: "/", PathType: PathTypePrefix, Target: DrupalRouteTarget()}}
+	}
+	routes := make([]SiteRoute, len(s.Spec.Routes))
+	for i, route := range s.Spec.Routes {
+		routes[i] = route
+		if route.PathType == "" {
+			routes[i].PathType = PathTypePrefix
+		}
+		if route.Target.ServiceName == "" {
+			routes[i].Target = DrupalRouteTarget()
+		}
+	}
+	return routes
+}
+
+// DrupalRouteTarget returns the target of the routes served by Drupal
+func DrupalRouteTarget() RouteTarget {
+	return RouteTarget{ServiceName: DrupalServiceName, ServicePort: intstr.FromInt(DrupalServicePort)}
+}
+
+// DrupalPaths returns the paths Drupal serves the Site under, without trailing slashes except for /
+func (s *Site) DrupalPaths() []string {
+	var paths []string
+	for _, route := range s.Routes() {
+		if route.Target.ServiceName == DrupalServiceName {
+			paths = append(paths, cleanRoutePath(route.Path))
+		}
+	}
+	return paths
+}
+
+// DrushURI returns the URI drush picks the Site from in the domain map, which is its first domain and the first path
+// Drupal serves it under, or "" if the Site has no domains
+func (s *Site) DrushURI() string {
+	if len(s.Spec.Domains) == 0 {
+		return ""
+	}
+	uri := s.Spec.Domains[0]
+	if paths := s.DrupalPaths(); len(paths) > 0 && paths[0] != "/" {
+		uri += paths[0]
+	}
+	return uri
+}
+
+func cleanRoutePath(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// Conflicts returns true if both Sites route or redirect the given host, so that only one of them can publish it. Sites
+// only share a host they both route different paths of.
+func (s *Site) Conflicts(other *Site, host string) bool {
+	switch {
+	case !s.HasHost(host) || !other.HasHost(host):
+		return false
+	case s.Redirect(host) != nil || other.Redirect(host) != nil:
+		return true
+	}
+	paths := map[string]bool{}
+	for _, route := range s.Routes() {
+		paths[cleanRoutePath(route.Path)] = true
+	}
+	for _, route := range other.Routes() {
+		if paths[cleanRoutePath(route.Path)] {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDomain returns true if the domain is one of the Site's domains
+func (s *Site) HasDomain(domain string) bool {
+	for _, d := range s.Spec.Domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// HasHost returns true if the host is one of the Site's domains or redirect hosts
+func (s *Site) HasHost(host string) bool {
+	return s.HasDomain(host) || s.Redirect(host) != nil
+}
+
+// Hosts returns the Site's domains, followed by its redirect hosts
+func (s *Site) Hosts() []string {
+	hosts := append([]string{}, s.Spec.Domains...)
+	for _, redirect := range s.Spec.Redirects {
+		if !s.HasDomain(redirect.FromHost) {
+			hosts = append(hosts, redirect.FromHost)
+		}
+	}
+	return hosts
+}
+
+// Redirect returns the redirect of a host given in spec.redirects, or nil
+func (s *Site) Redirect(host string) *SiteRedirect {
+	for i := range s.Spec.Redirects {
+		if s.Spec.Redirects[i].FromHost == host {
+			return &s.Spec.Redirects[i]
+		}
+	}
+	return nil
+}
+
+// StatusCode returns the status code of the redirect, defaulting to 301
+func (r SiteRedirect) StatusCode() int32 {
+	if r.Code == 0 {
+		return 301
+	}
+	return r.Code
+}
+
+// RedirectIngressName returns the name of the Ingress answering a redirect host of the Site with its redirect
+func (s *Site) RedirectIngressName(host string) string {
+	return s.Name + "-redirect-" + strings.Replace(host, "*", "wildcard", 1)
+}
+
+// DomainMap maps the given domains of the Site, with each path Drupal serves it under, to its database. Drupal's
+// settings.php picks the entry with the longest path that the request's path starts with, by path segment. Redirect
+// hosts are left out, as Drupal never serves them.
+func (s *Site) DomainMap(domains []string) DomainMap {
+	paths := s.DrupalPaths()
+	m := make(DomainMap, len(domains)*len(paths))
+	for _, domain := range domains {
+		if s.Redirect(domain) != nil {
+			continue
+		}
+		for _, path := range paths {
+			if path == "/" {
+				m[domain] = s.DatabaseName()
+			} else {
+				m[domain+path] = s.DatabaseName()
+			}
+		}
 	}
 	return m
 }
 
-func (s *Site) IngressRules() []extv1b1.IngressRule {
+// IngressRules routes the given domains of the Site to the targets of its routes
+func (s *Site) IngressRules(domains []string) []extv1b1.IngressRule {
+	return IngressRules(domains, s.Routes())
+}
+
+// IngressRules routes the given hosts to the targets of the routes
+func IngressRules(hosts []string, routes []SiteRoute) []extv1b1.IngressRule {
+	var paths []extv1b1.HTTPIngressPath
+	for _, route := range routes {
+		paths = append(paths, extv1b1.HTTPIngressPath{
+			Path: route.Path,
+			Backend: extv1b1.IngressBackend{
+				ServiceName: route.Target.ServiceName,
+				ServicePort: route.Target.ServicePort,
+			},
+		})
+	}
 	value := extv1b1.IngressRuleValue{
 		HTTP: &extv1b1.HTTPIngressRuleValue{
-			Paths: []extv1b1.HTTPIngressPath{
-				{
-					Path: "/",
-					Backend: extv1b1.IngressBackend{
-						ServiceName: "drupal",
-						ServicePort: intstr.FromInt(80),
-					},
-				},
-			},
+			Paths: paths,
 		},
 	}
 
-	rules := make([]extv1b1.IngressRule, len(s.Spec.Domains))
-	for i, host := range s.Spec.Domains {
+	rules := make([]extv1b1.IngressRule, len(hosts))
+	for i, host := range hosts {
 		rules[i] = extv1b1.IngressRule{
 			Host:             host,
 			IngressRuleValue: value,
@@ -175,19 +570,91 @@ func (s *Site) IngressRules() []extv1b1.IngressRule {
 	return rules
 }
 
-func (s *Site) IngressTLS() []extv1b1.IngressTLS {
-	if s.Spec.Tls {
-		return []extv1b1.IngressTLS{{
-			Hosts:      s.Spec.Domains,
-			SecretName: s.Name + "-tls-secret",
-		}}
+// IngressTLS returns a TLS entry, with its own Secret, for each of the given domains of the Site that is served over TLS
+func (s *Site) IngressTLS(domains []string) []extv1b1.IngressTLS {
+	var tls []extv1b1.IngressTLS
+	for _, domain := range domains {
+		if secretName, _ := s.TLSSecretName(domain); secretName != "" {
+			tls = append(tls, extv1b1.IngressTLS{
+				Hosts:      []string{domain},
+				SecretName: secretName,
+			})
+		}
+	}
+	return tls
+}
+
+// Returns the TLS settings of a domain given in spec.domainTLS, or nil
+func (s *Site) DomainTLS(domain string) *DomainTLS {
+	for i := range s.Spec.DomainTLS {
+		if s.Spec.DomainTLS[i].Domain == domain {
+			return &s.Spec.DomainTLS[i]
+		}
 	}
 	return nil
 }
 
-// Returns site ingress class for kubernetes.io/ingress.class ingress annotation
-func (s *Site) IngressClass() string {
-	def := "nginx" //Defaults to nginx
+// TLSSecretName returns the name of the Secret with the certificate of a domain, and whether the certificate is
+// supplied rather than issued by cert-manager. It returns "" if the domain is served without TLS.
+func (s *Site) TLSSecretName(domain string) (name string, supplied bool) {
+	settings := s.DomainTLS(domain)
+	switch {
+	case settings == nil && !s.Spec.Tls, settings != nil && settings.Disabled:
+		return "", false
+	case settings != nil && settings.SecretName != "":
+		return settings.SecretName, true
+	case len(s.Spec.Domains) > 0 && domain == s.Spec.Domains[0]:
+		// The first domain keeps the Secret of the Sites that predate per-domain certificates, so that upgrading
+		// doesn't leave it without a certificate until cert-manager issues one
+		return s.LegacyTLSSecretName(), false
+	}
+	return s.Name + "-" + strings.Replace(domain, "*", "wildcard", 1) + "-tls", false
+}
+
+// LegacyTLSSecretName returns the name of the Secret that held the certificate of all of the Site's domains, before
+// each domain got its own
+func (s *Site) LegacyTLSSecretName() string {
+	return s.Name + "-tls-secret"
+}
+
+// SuppliedCertIngressName returns the name of the Ingress serving the Site's domains with supplied certificates. They
+// are kept out of the Site's main Ingress, so that cert-manager doesn't replace their certificates.
+func (s *Site) SuppliedCertIngressName() string {
+	return s.Name + "-supplied-tls"
+}
+
+// SplitSuppliedCerts splits the given domains of the Site into those served with supplied certificates, and the rest
+func (s *Site) SplitSuppliedCerts(domains []string) (others, supplied []string) {
+	for _, domain := range domains {
+		if _, ok := s.TLSSecretName(domain); ok {
+			supplied = append(supplied, domain)
+		} else {
+			others = append(others, domain)
+		}
+	}
+	return others, supplied
+}
+
+// Returns the site's deletion policy, defaulting to Delete
+func (s *Site) DeletionPolicy() DeletionPolicy {
+	if s.Spec.DeletionPolicy == "" {
+		return DeletionPolicyDelete
+	}
+	return s.Spec.DeletionPolicy
+}
+
+// MaintenanceResponderName returns the name of the Deployment, Service and ConfigMap of the Site's maintenance responder
+func (s *Site) MaintenanceResponderName() string {
+	return s.Name + "-maintenance"
+}
+
+// BackupCronJobName returns the name of the CronJob running the Site's scheduled backups
+func (s *Site) BackupCronJobName() string {
+	return s.Name + "-backups"
+}
+
+// Returns site ingress class for kubernetes.io/ingress.class ingress annotation, or def if the Site doesn't set one
+func (s *Site) IngressClass(def string) string {
 	ic := s.Spec.IngressClass
 	if ic != "" {
 		return ic
@@ -195,9 +662,9 @@ func (s *Site) IngressClass() string {
 	return def
 }
 
-// Returns cert-manager issuer for certmanager.k8s.io/cluster-issuer ingress annotation
-func (s *Site) IngressCertIssuer() string {
-	def := "letsencrypt-staging" //Defaults to letsencrypt-staging
+// Returns cert-manager issuer for certmanager.k8s.io/cluster-issuer ingress annotation, or def if the Site doesn't set
+// one
+func (s *Site) IngressCertIssuer(def string) string {
 	cmi := s.Spec.CertIssuer
 	if cmi != "" {
 		return cmi
@@ -0,0 +1,132 @@
+package v1alpha1
+
+// IMPORTANT: Run "operator-sdk generate k8s && operator-sdk generate openapi"
+// to regenerate code after modifying this file.
+// SEE: https://book.kubebuilder.io/reference/generating-crd.html
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupMethod is the tool used to dump a Site's database
+type BackupMethod string
+
+const (
+	// BackupMethodDrush dumps and restores the database with "drush sql-dump" and "drush sql-cli"
+	BackupMethodDrush BackupMethod = "Drush"
+	// BackupMethodMysqldump dumps and restores the database with the mysqldump and mysql clients
+	BackupMethodMysqldump BackupMethod = "Mysqldump"
+)
+
+// OperationPhase is the progress of a one-off operation run as a Job, such as a backup or restore
+type OperationPhase string
+
+const (
+	OperationPending   OperationPhase = "Pending"
+	OperationRunning   OperationPhase = "Running"
+	OperationSucceeded OperationPhase = "Succeeded"
+	OperationFailed    OperationPhase = "Failed"
+)
+
+// BackupTarget is where backup archives are stored. Exactly one of its fields must be set.
+type BackupTarget struct {
+	PVC *PVCBackupTarget `json:"pvc,omitempty"` // +optional
+	S3  *S3BackupTarget  `json:"s3,omitempty"`  // +optional
+}
+
+// PVCBackupTarget stores backup archives on a PersistentVolumeClaim in the Site's namespace
+type PVCBackupTarget struct {
+	ClaimName string `json:"claimName"`
+	SubPath   string `json:"subPath,omitempty"` // +optional
+}
+
+// S3BackupTarget stores backup archives in a bucket of an S3-compatible object store. The credentials Secret must
+// have the keys "accessKeyId" and "secretAccessKey".
+type S3BackupTarget struct {
+	Endpoint          string `json:"endpoint,omitempty"` // +optional
+	Bucket            string `json:"bucket"`
+	Prefix            string `json:"prefix,omitempty"` // +optional
+	Region            string `json:"region,omitempty"` // +optional
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// Validate returns an error unless exactly one kind of target is set
+func (t BackupTarget) Validate() error {
+	switch {
+	case t.PVC == nil && t.S3 == nil:
+		return fmt.Errorf("one of target.pvc or target.s3 must be set")
+	case t.PVC != nil && t.S3 != nil:
+		return fmt.Errorf("only one of target.pvc or target.s3 may be set")
+	case t.PVC != nil && t.PVC.ClaimName == "":
+		return fmt.Errorf("target.pvc.claimName must be set")
+	case t.S3 != nil && (t.S3.Bucket == "" || t.S3.CredentialsSecret == ""):
+		return fmt.Errorf("target.s3.bucket and target.s3.credentialsSecret must be set")
+	}
+	return nil
+}
+
+// SiteBackupSpec defines the desired state of SiteBackup
+// +k8s:openapi-gen=true
+type SiteBackupSpec struct {
+	Site   string       `json:"site"`
+	Method BackupMethod `json:"method,omitempty"` // +optional
+	Target BackupTarget `json:"target"`
+}
+
+// SiteBackupStatus defines the observed state of SiteBackup
+// +k8s:openapi-gen=true
+type SiteBackupStatus struct {
+	Phase   OperationPhase `json:"phase,omitempty"`   // +optional
+	JobName string         `json:"jobName,omitempty"` // +optional
+	// Location is the path of the backup archive, relative to the root of the target
+	Location       string           `json:"location,omitempty"`       // +optional
+	SizeBytes      int64            `json:"sizeBytes,omitempty"`      // +optional
+	Checksum       string           `json:"checksum,omitempty"`       // +optional
+	StartTime      *metav1.Time     `json:"startTime,omitempty"`      // +optional
+	CompletionTime *metav1.Time     `json:"completionTime,omitempty"` // +optional
+	Duration       *metav1.Duration `json:"duration,omitempty"`       // +optional
+	Message        string           `json:"message,omitempty"`        // +optional
+}
+
+// SiteBackup is the Schema for the sitebackups API
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".spec.site"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.sizeBytes"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type SiteBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SiteBackupSpec   `json:"spec,omitempty"`
+	Status SiteBackupStatus `json:"status,omitempty"` // +optional
+}
+
+// SiteBackupList contains a list of SiteBackup
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SiteBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SiteBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SiteBackup{}, &SiteBackupList{})
+}
+
+// BackupMethod returns the method used to dump the database, defaulting to Drush
+func (b *SiteBackup) BackupMethod() BackupMethod {
+	return b.Spec.Method.OrDefault()
+}
+
+// OrDefault returns the method, or Drush if it isn't set
+func (m BackupMethod) OrDefault() BackupMethod {
+	if m == "" {
+		return BackupMethodDrush
+	}
+	return m
+}
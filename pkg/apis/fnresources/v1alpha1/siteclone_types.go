@@ -0,0 +1,94 @@
+package v1alpha1
+
+// IMPORTANT: Run "operator-sdk generate k8s && operator-sdk generate openapi"
+// to regenerate code after modifying this file.
+// SEE: https://book.kubebuilder.io/reference/generating-crd.html
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloneStep is the part of a clone a SiteClone is working on
+type CloneStep string
+
+const (
+	CloneStepCopyingDatabase CloneStep = "CopyingDatabase"
+	CloneStepCopyingFiles    CloneStep = "CopyingFiles"
+	CloneStepSanitizing      CloneStep = "Sanitizing"
+)
+
+// SiteCloneSource identifies the Site a SiteClone copies from
+type SiteCloneSource struct {
+	// Namespace of the source Site, which defaults to the namespace of the SiteClone
+	Namespace string `json:"namespace,omitempty"` // +optional
+	Name      string `json:"name"`
+}
+
+// SanitizeSpec describes a command run against the target Site once its database and files have been copied, for
+// example to scrub personal data with "drush sql-sanitize"
+type SanitizeSpec struct {
+	Command []string `json:"command"`
+}
+
+// SiteCloneSpec defines the desired state of SiteClone
+// +k8s:openapi-gen=true
+type SiteCloneSpec struct {
+	// Site is the existing Site, in the same namespace, whose database and files are replaced with those of the source
+	Site   string          `json:"site"`
+	Source SiteCloneSource `json:"source"`
+	// SkipFiles copies only the database
+	SkipFiles bool          `json:"skipFiles,omitempty"` // +optional
+	Sanitize  *SanitizeSpec `json:"sanitize,omitempty"`  // +optional
+}
+
+// SiteCloneStatus defines the observed state of SiteClone
+// +k8s:openapi-gen=true
+type SiteCloneStatus struct {
+	Phase           OperationPhase `json:"phase,omitempty"`           // +optional
+	Step            CloneStep      `json:"step,omitempty"`            // +optional
+	TablesCopied    int32          `json:"tablesCopied,omitempty"`    // +optional
+	TablesTotal     int32          `json:"tablesTotal,omitempty"`     // +optional
+	FilesJobName    string         `json:"filesJobName,omitempty"`    // +optional
+	SanitizeJobName string         `json:"sanitizeJobName,omitempty"` // +optional
+	StartTime       *metav1.Time   `json:"startTime,omitempty"`       // +optional
+	CompletionTime  *metav1.Time   `json:"completionTime,omitempty"`  // +optional
+	Message         string         `json:"message,omitempty"`         // +optional
+}
+
+// SiteClone is the Schema for the siteclones API
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".spec.site"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source.name"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Step",type="string",JSONPath=".status.step"
+// +kubebuilder:printcolumn:name="Tables",type="integer",JSONPath=".status.tablesCopied"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type SiteClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SiteCloneSpec   `json:"spec,omitempty"`
+	Status SiteCloneStatus `json:"status,omitempty"` // +optional
+}
+
+// SiteCloneList contains a list of SiteClone
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SiteCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SiteClone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SiteClone{}, &SiteCloneList{})
+}
+
+// SourceNamespace returns the namespace of the source Site
+func (c *SiteClone) SourceNamespace() string {
+	if c.Spec.Source.Namespace == "" {
+		return c.Namespace
+	}
+	return c.Spec.Source.Namespace
+}
@@ -0,0 +1,57 @@
+package v1alpha1
+
+// IMPORTANT: Run "operator-sdk generate k8s && operator-sdk generate openapi"
+// to regenerate code after modifying this file.
+// SEE: https://book.kubebuilder.io/reference/generating-crd.html
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SiteRestoreSpec defines the desired state of SiteRestore
+// +k8s:openapi-gen=true
+type SiteRestoreSpec struct {
+	// Site is the existing Site the backup is restored into. It may differ from the Site the backup was taken of.
+	Site string `json:"site"`
+	// Backup is the name of a succeeded SiteBackup in the same namespace
+	Backup string `json:"backup"`
+}
+
+// SiteRestoreStatus defines the observed state of SiteRestore
+// +k8s:openapi-gen=true
+type SiteRestoreStatus struct {
+	Phase          OperationPhase   `json:"phase,omitempty"`          // +optional
+	JobName        string           `json:"jobName,omitempty"`        // +optional
+	StartTime      *metav1.Time     `json:"startTime,omitempty"`      // +optional
+	CompletionTime *metav1.Time     `json:"completionTime,omitempty"` // +optional
+	Duration       *metav1.Duration `json:"duration,omitempty"`       // +optional
+	Message        string           `json:"message,omitempty"`        // +optional
+}
+
+// SiteRestore is the Schema for the siterestores API
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Site",type="string",JSONPath=".spec.site"
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backup"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type SiteRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SiteRestoreSpec   `json:"spec,omitempty"`
+	Status SiteRestoreStatus `json:"status,omitempty"` // +optional
+}
+
+// SiteRestoreList contains a list of SiteRestore
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SiteRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SiteRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SiteRestore{}, &SiteRestoreList{})
+}
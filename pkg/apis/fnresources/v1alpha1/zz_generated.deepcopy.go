@@ -5,9 +5,213 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisSpec) DeepCopyInto(out *AnalysisSpec) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPAnalysis)
+		**out = **in
+	}
+	if in.ErrorRate != nil {
+		in, out := &in.ErrorRate, &out.ErrorRate
+		*out = new(ErrorRateAnalysis)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisSpec.
+func (in *AnalysisSpec) DeepCopy() *AnalysisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTarget) DeepCopyInto(out *BackupTarget) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCBackupTarget)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupTarget)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTarget.
+func (in *BackupTarget) DeepCopy() *BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupsSpec) DeepCopyInto(out *BackupsSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	if in.RetentionAge != nil {
+		in, out := &in.RetentionAge, &out.RetentionAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupsSpec.
+func (in *BackupsSpec) DeepCopy() *BackupsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueGreenRolloutStrategy) DeepCopyInto(out *BlueGreenRolloutStrategy) {
+	*out = *in
+	if in.AutoPromotion != nil {
+		in, out := &in.AutoPromotion, &out.AutoPromotion
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoPromotionSeconds != nil {
+		in, out := &in.AutoPromotionSeconds, &out.AutoPromotionSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownDelaySeconds != nil {
+		in, out := &in.ScaleDownDelaySeconds, &out.ScaleDownDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueGreenRolloutStrategy.
+func (in *BlueGreenRolloutStrategy) DeepCopy() *BlueGreenRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueGreenRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPause) DeepCopyInto(out *CanaryPause) {
+	*out = *in
+	if in.DurationSeconds != nil {
+		in, out := &in.DurationSeconds, &out.DurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPause.
+func (in *CanaryPause) DeepCopy() *CanaryPause {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPause)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRolloutStrategy) DeepCopyInto(out *CanaryRolloutStrategy) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRolloutStrategy.
+func (in *CanaryRolloutStrategy) DeepCopy() *CanaryRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	if in.SetWeight != nil {
+		in, out := &in.SetWeight, &out.SetWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(CanaryPause)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CronSpec) DeepCopyInto(out *CronSpec) {
 	*out = *in
@@ -29,6 +233,48 @@ func (in *CronSpec) DeepCopy() *CronSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseDefaults) DeepCopyInto(out *DatabaseDefaults) {
+	*out = *in
+	if in.AdminSecret != nil {
+		in, out := &in.AdminSecret, &out.AdminSecret
+		*out = new(SecretReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseDefaults.
+func (in *DatabaseDefaults) DeepCopy() *DatabaseDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployHooksStatus) DeepCopyInto(out *DeployHooksStatus) {
+	*out = *in
+	if in.FailedSites != nil {
+		in, out := &in.FailedSites, &out.FailedSites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployHooksStatus.
+func (in *DeployHooksStatus) DeepCopy() *DeployHooksStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployHooksStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in DomainMap) DeepCopyInto(out *DomainMap) {
 	{
@@ -41,38 +287,981 @@ func (in DomainMap) DeepCopyInto(out *DomainMap) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainMap.
-func (in DomainMap) DeepCopy() DomainMap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainMap.
+func (in DomainMap) DeepCopy() DomainMap {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainTLS) DeepCopyInto(out *DomainTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainTLS.
+func (in *DomainTLS) DeepCopy() *DomainTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalApplication) DeepCopyInto(out *DrupalApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplication.
+func (in *DrupalApplication) DeepCopy() *DrupalApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalApplicationList) DeepCopyInto(out *DrupalApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DrupalApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationList.
+func (in *DrupalApplicationList) DeepCopy() *DrupalApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalApplicationSpec) DeepCopyInto(out *DrupalApplicationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationSpec.
+func (in *DrupalApplicationSpec) DeepCopy() *DrupalApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalApplicationStatus) DeepCopyInto(out *DrupalApplicationStatus) {
+	*out = *in
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = make([]DrupalEnvironmentRef, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationStatus.
+func (in *DrupalApplicationStatus) DeepCopy() *DrupalApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalEnvironment) DeepCopyInto(out *DrupalEnvironment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironment.
+func (in *DrupalEnvironment) DeepCopy() *DrupalEnvironment {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalEnvironment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalEnvironment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalEnvironmentList) DeepCopyInto(out *DrupalEnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DrupalEnvironment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentList.
+func (in *DrupalEnvironmentList) DeepCopy() *DrupalEnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalEnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalEnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalEnvironmentRef) DeepCopyInto(out *DrupalEnvironmentRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentRef.
+func (in *DrupalEnvironmentRef) DeepCopy() *DrupalEnvironmentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalEnvironmentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalEnvironmentSpec) DeepCopyInto(out *DrupalEnvironmentSpec) {
+	*out = *in
+	in.Drupal.DeepCopyInto(&out.Drupal)
+	in.Apache.DeepCopyInto(&out.Apache)
+	in.Phpfpm.DeepCopyInto(&out.Phpfpm)
+	in.ProxySQL.DeepCopyInto(&out.ProxySQL)
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReleaseHistoryLimit != nil {
+		in, out := &in.ReleaseHistoryLimit, &out.ReleaseHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentSpec.
+func (in *DrupalEnvironmentSpec) DeepCopy() *DrupalEnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalEnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalEnvironmentStatus) DeepCopyInto(out *DrupalEnvironmentStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Drupal.DeepCopyInto(&out.Drupal)
+	if in.DeployHooks != nil {
+		in, out := &in.DeployHooks, &out.DeployHooks
+		*out = new(DeployHooksStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Releases != nil {
+		in, out := &in.Releases, &out.Releases
+		*out = make([]Release, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(ResolvedImages)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentStatus.
+func (in *DrupalEnvironmentStatus) DeepCopy() *DrupalEnvironmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalEnvironmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalOperatorConfig) DeepCopyInto(out *DrupalOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalOperatorConfig.
+func (in *DrupalOperatorConfig) DeepCopy() *DrupalOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalOperatorConfigList) DeepCopyInto(out *DrupalOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DrupalOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalOperatorConfigList.
+func (in *DrupalOperatorConfigList) DeepCopy() *DrupalOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DrupalOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalOperatorConfigSpec) DeepCopyInto(out *DrupalOperatorConfigSpec) {
+	*out = *in
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	out.Storage = in.Storage
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	in.Database.DeepCopyInto(&out.Database)
+	in.Registry.DeepCopyInto(&out.Registry)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalOperatorConfigSpec.
+func (in *DrupalOperatorConfigSpec) DeepCopy() *DrupalOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrupalOperatorConfigStatus) DeepCopyInto(out *DrupalOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalOperatorConfigStatus.
+func (in *DrupalOperatorConfigStatus) DeepCopy() *DrupalOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrupalOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorRateAnalysis) DeepCopyInto(out *ErrorRateAnalysis) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorRateAnalysis.
+func (in *ErrorRateAnalysis) DeepCopy() *ErrorRateAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorRateAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HSTSSpec) DeepCopyInto(out *HSTSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HSTSSpec.
+func (in *HSTSSpec) DeepCopy() *HSTSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HSTSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPAnalysis) DeepCopyInto(out *HTTPAnalysis) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAnalysis.
+func (in *HTTPAnalysis) DeepCopy() *HTTPAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbe.
+func (in *HTTPProbe) DeepCopy() *HTTPProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressDefaults) DeepCopyInto(out *IngressDefaults) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressDefaults.
+func (in *IngressDefaults) DeepCopy() *IngressDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallSpec) DeepCopyInto(out *InstallSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallSpec.
+func (in *InstallSpec) DeepCopy() *InstallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceSpec) DeepCopyInto(out *MaintenanceSpec) {
+	*out = *in
+	if in.AllowedIPs != nil {
+		in, out := &in.AllowedIPs, &out.AllowedIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceSpec.
+func (in *MaintenanceSpec) DeepCopy() *MaintenanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCBackupTarget) DeepCopyInto(out *PVCBackupTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCBackupTarget.
+func (in *PVCBackupTarget) DeepCopy() *PVCBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryDefaults) DeepCopyInto(out *RegistryDefaults) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryDefaults.
+func (in *RegistryDefaults) DeepCopy() *RegistryDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Release) DeepCopyInto(out *Release) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ReleaseImage, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+	if in.RollbackOf != nil {
+		in, out := &in.RollbackOf, &out.RollbackOf
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Release.
+func (in *Release) DeepCopy() *Release {
+	if in == nil {
+		return nil
+	}
+	out := new(Release)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseImage) DeepCopyInto(out *ReleaseImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseImage.
+func (in *ReleaseImage) DeepCopy() *ReleaseImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedImage) DeepCopyInto(out *ResolvedImage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedImage.
+func (in *ResolvedImage) DeepCopy() *ResolvedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedImages) DeepCopyInto(out *ResolvedImages) {
+	*out = *in
+	out.Drupal = in.Drupal
+	out.Apache = in.Apache
+	out.PhpFpm = in.PhpFpm
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedImages.
+func (in *ResolvedImages) DeepCopy() *ResolvedImages {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Resources) DeepCopyInto(out *Resources) {
+	*out = *in
+	out.Request = in.Request.DeepCopy()
+	out.Limit = in.Limit.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
+func (in *Resources) DeepCopy() *Resources {
+	if in == nil {
+		return nil
+	}
+	out := new(Resources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackStatus) DeepCopyInto(out *RollbackStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackStatus.
+func (in *RollbackStatus) DeepCopy() *RollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(BlueGreenRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteTarget) DeepCopyInto(out *RouteTarget) {
+	*out = *in
+	out.ServicePort = in.ServicePort
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteTarget.
+func (in *RouteTarget) DeepCopy() *RouteTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupTarget) DeepCopyInto(out *S3BackupTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupTarget.
+func (in *S3BackupTarget) DeepCopy() *S3BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SanitizeSpec) DeepCopyInto(out *SanitizeSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SanitizeSpec.
+func (in *SanitizeSpec) DeepCopy() *SanitizeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SanitizeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingDefaults) DeepCopyInto(out *SchedulingDefaults) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingDefaults.
+func (in *SchedulingDefaults) DeepCopy() *SchedulingDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Site) DeepCopyInto(out *Site) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Site.
+func (in *Site) DeepCopy() *Site {
+	if in == nil {
+		return nil
+	}
+	out := new(Site)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Site) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteBackup) DeepCopyInto(out *SiteBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteBackup.
+func (in *SiteBackup) DeepCopy() *SiteBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SiteBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteBackupList) DeepCopyInto(out *SiteBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SiteBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteBackupList.
+func (in *SiteBackupList) DeepCopy() *SiteBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SiteBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteBackupSpec) DeepCopyInto(out *SiteBackupSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteBackupSpec.
+func (in *SiteBackupSpec) DeepCopy() *SiteBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteBackupStatus) DeepCopyInto(out *SiteBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteBackupStatus.
+func (in *SiteBackupStatus) DeepCopy() *SiteBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteBackupsStatus) DeepCopyInto(out *SiteBackupsStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteBackupsStatus.
+func (in *SiteBackupsStatus) DeepCopy() *SiteBackupsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DomainMap)
+	out := new(SiteBackupsStatus)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalApplication) DeepCopyInto(out *DrupalApplication) {
+func (in *SiteClone) DeepCopyInto(out *SiteClone) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplication.
-func (in *DrupalApplication) DeepCopy() *DrupalApplication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteClone.
+func (in *SiteClone) DeepCopy() *SiteClone {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalApplication)
+	out := new(SiteClone)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DrupalApplication) DeepCopyObject() runtime.Object {
+func (in *SiteClone) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -80,13 +1269,13 @@ func (in *DrupalApplication) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalApplicationList) DeepCopyInto(out *DrupalApplicationList) {
+func (in *SiteCloneList) DeepCopyInto(out *SiteCloneList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DrupalApplication, len(*in))
+		*out = make([]SiteClone, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -94,18 +1283,18 @@ func (in *DrupalApplicationList) DeepCopyInto(out *DrupalApplicationList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationList.
-func (in *DrupalApplicationList) DeepCopy() *DrupalApplicationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteCloneList.
+func (in *SiteCloneList) DeepCopy() *SiteCloneList {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalApplicationList)
+	out := new(SiteCloneList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DrupalApplicationList) DeepCopyObject() runtime.Object {
+func (in *SiteCloneList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -113,227 +1302,271 @@ func (in *DrupalApplicationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalApplicationSpec) DeepCopyInto(out *DrupalApplicationSpec) {
+func (in *SiteCloneSource) DeepCopyInto(out *SiteCloneSource) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationSpec.
-func (in *DrupalApplicationSpec) DeepCopy() *DrupalApplicationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteCloneSource.
+func (in *SiteCloneSource) DeepCopy() *SiteCloneSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalApplicationSpec)
+	out := new(SiteCloneSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalApplicationStatus) DeepCopyInto(out *DrupalApplicationStatus) {
+func (in *SiteCloneSpec) DeepCopyInto(out *SiteCloneSpec) {
 	*out = *in
-	if in.Environments != nil {
-		in, out := &in.Environments, &out.Environments
-		*out = make([]DrupalEnvironmentRef, len(*in))
-		copy(*out, *in)
+	out.Source = in.Source
+	if in.Sanitize != nil {
+		in, out := &in.Sanitize, &out.Sanitize
+		*out = new(SanitizeSpec)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalApplicationStatus.
-func (in *DrupalApplicationStatus) DeepCopy() *DrupalApplicationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteCloneSpec.
+func (in *SiteCloneSpec) DeepCopy() *SiteCloneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalApplicationStatus)
+	out := new(SiteCloneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalEnvironment) DeepCopyInto(out *DrupalEnvironment) {
+func (in *SiteCloneStatus) DeepCopyInto(out *SiteCloneStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironment.
-func (in *DrupalEnvironment) DeepCopy() *DrupalEnvironment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteCloneStatus.
+func (in *SiteCloneStatus) DeepCopy() *SiteCloneStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalEnvironment)
+	out := new(SiteCloneStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DrupalEnvironment) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteDNSRecord) DeepCopyInto(out *SiteDNSRecord) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteDNSRecord.
+func (in *SiteDNSRecord) DeepCopy() *SiteDNSRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteDNSRecord)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalEnvironmentList) DeepCopyInto(out *DrupalEnvironmentList) {
+func (in *SiteDatabaseStatus) DeepCopyInto(out *SiteDatabaseStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]DrupalEnvironment, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentList.
-func (in *DrupalEnvironmentList) DeepCopy() *DrupalEnvironmentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteDatabaseStatus.
+func (in *SiteDatabaseStatus) DeepCopy() *SiteDatabaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalEnvironmentList)
+	out := new(SiteDatabaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DrupalEnvironmentList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteDeployHookStatus) DeepCopyInto(out *SiteDeployHookStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteDeployHookStatus.
+func (in *SiteDeployHookStatus) DeepCopy() *SiteDeployHookStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(SiteDeployHookStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalEnvironmentRef) DeepCopyInto(out *DrupalEnvironmentRef) {
+func (in *SiteHostStatus) DeepCopyInto(out *SiteHostStatus) {
 	*out = *in
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentRef.
-func (in *DrupalEnvironmentRef) DeepCopy() *DrupalEnvironmentRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteHostStatus.
+func (in *SiteHostStatus) DeepCopy() *SiteHostStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalEnvironmentRef)
+	out := new(SiteHostStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalEnvironmentSpec) DeepCopyInto(out *DrupalEnvironmentSpec) {
+func (in *SiteInstallStatus) DeepCopyInto(out *SiteInstallStatus) {
 	*out = *in
-	in.Drupal.DeepCopyInto(&out.Drupal)
-	in.Apache.DeepCopyInto(&out.Apache)
-	in.Phpfpm.DeepCopyInto(&out.Phpfpm)
-	in.ProxySQL.DeepCopyInto(&out.ProxySQL)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentSpec.
-func (in *DrupalEnvironmentSpec) DeepCopy() *DrupalEnvironmentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteInstallStatus.
+func (in *SiteInstallStatus) DeepCopy() *SiteInstallStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalEnvironmentSpec)
+	out := new(SiteInstallStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DrupalEnvironmentStatus) DeepCopyInto(out *DrupalEnvironmentStatus) {
+func (in *SiteJobStatus) DeepCopyInto(out *SiteJobStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrupalEnvironmentStatus.
-func (in *DrupalEnvironmentStatus) DeepCopy() *DrupalEnvironmentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteJobStatus.
+func (in *SiteJobStatus) DeepCopy() *SiteJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DrupalEnvironmentStatus)
+	out := new(SiteJobStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
+func (in *SiteList) DeepCopyInto(out *SiteList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Site, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbe.
-func (in *HTTPProbe) DeepCopy() *HTTPProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteList.
+func (in *SiteList) DeepCopy() *SiteList {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPProbe)
+	out := new(SiteList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SiteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstallSpec) DeepCopyInto(out *InstallSpec) {
+func (in *SiteMaintenanceStatus) DeepCopyInto(out *SiteMaintenanceStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallSpec.
-func (in *InstallSpec) DeepCopy() *InstallSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteMaintenanceStatus.
+func (in *SiteMaintenanceStatus) DeepCopy() *SiteMaintenanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(InstallSpec)
+	out := new(SiteMaintenanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Resources) DeepCopyInto(out *Resources) {
+func (in *SiteRedirect) DeepCopyInto(out *SiteRedirect) {
 	*out = *in
-	out.Request = in.Request.DeepCopy()
-	out.Limit = in.Limit.DeepCopy()
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
-func (in *Resources) DeepCopy() *Resources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRedirect.
+func (in *SiteRedirect) DeepCopy() *SiteRedirect {
 	if in == nil {
 		return nil
 	}
-	out := new(Resources)
+	out := new(SiteRedirect)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Site) DeepCopyInto(out *Site) {
+func (in *SiteRestore) DeepCopyInto(out *SiteRestore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Site.
-func (in *Site) DeepCopy() *Site {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRestore.
+func (in *SiteRestore) DeepCopy() *SiteRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(Site)
+	out := new(SiteRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Site) DeepCopyObject() runtime.Object {
+func (in *SiteRestore) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -341,13 +1574,13 @@ func (in *Site) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SiteList) DeepCopyInto(out *SiteList) {
+func (in *SiteRestoreList) DeepCopyInto(out *SiteRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Site, len(*in))
+		*out = make([]SiteRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -355,24 +1588,86 @@ func (in *SiteList) DeepCopyInto(out *SiteList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteList.
-func (in *SiteList) DeepCopy() *SiteList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRestoreList.
+func (in *SiteRestoreList) DeepCopy() *SiteRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(SiteList)
+	out := new(SiteRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SiteList) DeepCopyObject() runtime.Object {
+func (in *SiteRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteRestoreSpec) DeepCopyInto(out *SiteRestoreSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRestoreSpec.
+func (in *SiteRestoreSpec) DeepCopy() *SiteRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteRestoreStatus) DeepCopyInto(out *SiteRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRestoreStatus.
+func (in *SiteRestoreStatus) DeepCopy() *SiteRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SiteRoute) DeepCopyInto(out *SiteRoute) {
+	*out = *in
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SiteRoute.
+func (in *SiteRoute) DeepCopy() *SiteRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(SiteRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SiteSpec) DeepCopyInto(out *SiteSpec) {
 	*out = *in
@@ -389,6 +1684,36 @@ func (in *SiteSpec) DeepCopyInto(out *SiteSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = new(BackupsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DomainTLS != nil {
+		in, out := &in.DomainTLS, &out.DomainTLS
+		*out = make([]DomainTLS, len(*in))
+		copy(*out, *in)
+	}
+	if in.HSTS != nil {
+		in, out := &in.HSTS, &out.HSTS
+		*out = new(HSTSSpec)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]SiteRoute, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redirects != nil {
+		in, out := &in.Redirects, &out.Redirects
+		*out = make([]SiteRedirect, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -405,6 +1730,48 @@ func (in *SiteSpec) DeepCopy() *SiteSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SiteStatus) DeepCopyInto(out *SiteStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Database = in.Database
+	in.Install.DeepCopyInto(&out.Install)
+	in.Backups.DeepCopyInto(&out.Backups)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]SiteHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]SiteJobStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DNSRecords != nil {
+		in, out := &in.DNSRecords, &out.DNSRecords
+		*out = make([]SiteDNSRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RedirectHosts != nil {
+		in, out := &in.RedirectHosts, &out.RedirectHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Maintenance = in.Maintenance
+	if in.DeployHook != nil {
+		in, out := &in.DeployHook, &out.DeployHook
+		*out = new(SiteDeployHookStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -446,6 +1813,16 @@ func (in *SpecDrupal) DeepCopyInto(out *SpecDrupal) {
 	}
 	out.Liveness = in.Liveness
 	out.Readiness = in.Readiness
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(AnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -493,3 +1870,40 @@ func (in *SpecProxySQL) DeepCopy() *SpecProxySQL {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusDrupal) DeepCopyInto(out *StatusDrupal) {
+	*out = *in
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusDrupal.
+func (in *StatusDrupal) DeepCopy() *StatusDrupal {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusDrupal)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageDefaults) DeepCopyInto(out *StorageDefaults) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageDefaults.
+func (in *StorageDefaults) DeepCopy() *StorageDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
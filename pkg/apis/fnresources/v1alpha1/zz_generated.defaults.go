@@ -12,5 +12,42 @@ import (
 // Public to allow building arbitrary schemes.
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&DrupalEnvironment{}, func(obj interface{}) { SetObjectDefaults_DrupalEnvironment(obj.(*DrupalEnvironment)) })
+	scheme.AddTypeDefaultingFunc(&DrupalEnvironmentList{}, func(obj interface{}) { SetObjectDefaults_DrupalEnvironmentList(obj.(*DrupalEnvironmentList)) })
+	scheme.AddTypeDefaultingFunc(&Site{}, func(obj interface{}) { SetObjectDefaults_Site(obj.(*Site)) })
+	scheme.AddTypeDefaultingFunc(&SiteList{}, func(obj interface{}) { SetObjectDefaults_SiteList(obj.(*SiteList)) })
 	return nil
 }
+
+func SetObjectDefaults_DrupalEnvironment(in *DrupalEnvironment) {
+	SetDefaults_SpecDrupal(&in.Spec.Drupal)
+}
+
+func SetObjectDefaults_DrupalEnvironmentList(in *DrupalEnvironmentList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_DrupalEnvironment(a)
+	}
+}
+
+func SetObjectDefaults_Site(in *Site) {
+	SetDefaults_SiteSpec(&in.Spec)
+	for i := range in.Spec.Crons {
+		a := &in.Spec.Crons[i]
+		SetDefaults_CronSpec(a)
+	}
+	if in.Spec.Backups != nil {
+		SetDefaults_BackupsSpec(in.Spec.Backups)
+	}
+	for i := range in.Spec.Routes {
+		a := &in.Spec.Routes[i]
+		SetDefaults_SiteRoute(a)
+	}
+}
+
+func SetObjectDefaults_SiteList(in *SiteList) {
+	for i := range in.Items {
+		a := &in.Items[i]
+		SetObjectDefaults_Site(a)
+	}
+}
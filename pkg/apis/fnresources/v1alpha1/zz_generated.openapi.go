@@ -11,17 +11,133 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./pkg/apis/fnresources/v1alpha1.CronSpec":                schema_pkg_apis_fnresources_v1alpha1_CronSpec(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalApplication":       schema_pkg_apis_fnresources_v1alpha1_DrupalApplication(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalApplicationSpec":   schema_pkg_apis_fnresources_v1alpha1_DrupalApplicationSpec(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalApplicationStatus": schema_pkg_apis_fnresources_v1alpha1_DrupalApplicationStatus(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironment":       schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironment(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironmentSpec":   schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentSpec(ref),
-		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironmentStatus": schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentStatus(ref),
-		"./pkg/apis/fnresources/v1alpha1.InstallSpec":             schema_pkg_apis_fnresources_v1alpha1_InstallSpec(ref),
-		"./pkg/apis/fnresources/v1alpha1.Site":                    schema_pkg_apis_fnresources_v1alpha1_Site(ref),
-		"./pkg/apis/fnresources/v1alpha1.SiteSpec":                schema_pkg_apis_fnresources_v1alpha1_SiteSpec(ref),
-		"./pkg/apis/fnresources/v1alpha1.SiteStatus":              schema_pkg_apis_fnresources_v1alpha1_SiteStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.AnalysisSpec":               schema_pkg_apis_fnresources_v1alpha1_AnalysisSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.BackupsSpec":                schema_pkg_apis_fnresources_v1alpha1_BackupsSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.CronSpec":                   schema_pkg_apis_fnresources_v1alpha1_CronSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.DomainTLS":                  schema_pkg_apis_fnresources_v1alpha1_DomainTLS(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalApplication":          schema_pkg_apis_fnresources_v1alpha1_DrupalApplication(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalApplicationSpec":      schema_pkg_apis_fnresources_v1alpha1_DrupalApplicationSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalApplicationStatus":    schema_pkg_apis_fnresources_v1alpha1_DrupalApplicationStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironment":          schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironment(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironmentSpec":      schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalEnvironmentStatus":    schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfig":       schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfig(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigSpec":   schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfigSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigStatus": schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfigStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.HSTSSpec":                   schema_pkg_apis_fnresources_v1alpha1_HSTSSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.InstallSpec":                schema_pkg_apis_fnresources_v1alpha1_InstallSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.MaintenanceSpec":            schema_pkg_apis_fnresources_v1alpha1_MaintenanceSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.RegistryDefaults":           schema_pkg_apis_fnresources_v1alpha1_RegistryDefaults(ref),
+		"./pkg/apis/fnresources/v1alpha1.RolloutStrategy":            schema_pkg_apis_fnresources_v1alpha1_RolloutStrategy(ref),
+		"./pkg/apis/fnresources/v1alpha1.RouteTarget":                schema_pkg_apis_fnresources_v1alpha1_RouteTarget(ref),
+		"./pkg/apis/fnresources/v1alpha1.Site":                       schema_pkg_apis_fnresources_v1alpha1_Site(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteBackup":                 schema_pkg_apis_fnresources_v1alpha1_SiteBackup(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteBackupSpec":             schema_pkg_apis_fnresources_v1alpha1_SiteBackupSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteBackupStatus":           schema_pkg_apis_fnresources_v1alpha1_SiteBackupStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteClone":                  schema_pkg_apis_fnresources_v1alpha1_SiteClone(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteCloneSpec":              schema_pkg_apis_fnresources_v1alpha1_SiteCloneSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteCloneStatus":            schema_pkg_apis_fnresources_v1alpha1_SiteCloneStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteRedirect":               schema_pkg_apis_fnresources_v1alpha1_SiteRedirect(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteRestore":                schema_pkg_apis_fnresources_v1alpha1_SiteRestore(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteRestoreSpec":            schema_pkg_apis_fnresources_v1alpha1_SiteRestoreSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteRestoreStatus":          schema_pkg_apis_fnresources_v1alpha1_SiteRestoreStatus(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteRoute":                  schema_pkg_apis_fnresources_v1alpha1_SiteRoute(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteSpec":                   schema_pkg_apis_fnresources_v1alpha1_SiteSpec(ref),
+		"./pkg/apis/fnresources/v1alpha1.SiteStatus":                 schema_pkg_apis_fnresources_v1alpha1_SiteStatus(ref),
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_AnalysisSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AnalysisSpec represents drupalenvironment.spec.drupal.analysis. At least one of HTTP and ErrorRate is set.",
+				Properties: map[string]spec.Schema{
+					"http": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.HTTPAnalysis"),
+						},
+					},
+					"errorRate": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.ErrorRateAnalysis"),
+						},
+					},
+					"intervalSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IntervalSeconds is the time between measurements. It defaults to 30.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"count": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Count is the number of measurements. It defaults to 5.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failureLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailureLimit is the number of failed measurements tolerated before the analysis fails",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.ErrorRateAnalysis", "./pkg/apis/fnresources/v1alpha1.HTTPAnalysis"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_BackupsSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Scheduled backups of the site. Each run stores a new archive, and archives beyond the retention count or older than the retention age are pruned from the target. A retention count or age of zero doesn't limit retention.",
+				Properties: map[string]spec.Schema{
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"suspend": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"method": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.BackupTarget"),
+						},
+					},
+					"retentionCount": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"retentionAge": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"schedule", "target"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.BackupTarget", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -77,6 +193,38 @@ func schema_pkg_apis_fnresources_v1alpha1_CronSpec(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_fnresources_v1alpha1_DomainTLS(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TLS settings of one of the site's domains, overriding spec.tls: the domain is served over TLS unless it is disabled. A domain with a secretName is served with the certificate in that Secret, a kubernetes.io/tls Secret in the site's namespace, rather than one issued by cert-manager.",
+				Properties: map[string]spec.Schema{
+					"domain": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"domain"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_fnresources_v1alpha1_DrupalApplication(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -271,12 +419,32 @@ func schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentSpec(ref common.Refer
 							Ref: ref("./pkg/apis/fnresources/v1alpha1.SpecProxySQL"),
 						},
 					},
+					"maintenance": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maintenance puts the Sites of the environment that don't set their own spec.maintenance in maintenance mode",
+							Ref:         ref("./pkg/apis/fnresources/v1alpha1.MaintenanceSpec"),
+						},
+					},
+					"rollbackTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackTo is the revision of an entry of status.releases to redeploy, in place of the tags and git ref of the spec. Drupal keeps running that release until RollbackTo is cleared.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"releaseHistoryLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReleaseHistoryLimit is the number of entries kept in status.releases. It defaults to 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 				Required: []string{"application", "production", "efsid", "gitRef", "drupal", "apache", "phpfpm", "proxySQL"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/fnresources/v1alpha1.SpecApache", "./pkg/apis/fnresources/v1alpha1.SpecDrupal", "./pkg/apis/fnresources/v1alpha1.SpecPhpFpm", "./pkg/apis/fnresources/v1alpha1.SpecProxySQL"},
+			"./pkg/apis/fnresources/v1alpha1.MaintenanceSpec", "./pkg/apis/fnresources/v1alpha1.SpecApache", "./pkg/apis/fnresources/v1alpha1.SpecDrupal", "./pkg/apis/fnresources/v1alpha1.SpecPhpFpm", "./pkg/apis/fnresources/v1alpha1.SpecProxySQL"},
 	}
 }
 
@@ -285,50 +453,68 @@ func schema_pkg_apis_fnresources_v1alpha1_DrupalEnvironmentStatus(ref common.Ref
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Description: "DrupalEnvironmentStatus defines the observed state of DrupalEnvironment",
-				Properties:  map[string]spec.Schema{},
-			},
-		},
-		Dependencies: []string{},
-	}
-}
-
-func schema_pkg_apis_fnresources_v1alpha1_InstallSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Description: "Information to install the site",
 				Properties: map[string]spec.Schema{
-					"installProfile": {
+					"observedGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Type:   []string{"integer"},
+							Format: "int64",
 						},
 					},
-					"adminUsername": {
+					"conditions": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.Condition"),
+									},
+								},
+							},
 						},
 					},
-					"adminEmail": {
+					"drupal": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.StatusDrupal"),
+						},
+					},
+					"deployHooks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeployHooks is the progress of the deploy hooks the Sites run for the current release",
+							Ref:         ref("./pkg/apis/fnresources/v1alpha1.DeployHooksStatus"),
+						},
+					},
+					"releases": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Releases are the latest changes to the images and git ref of the Drupal pods, most recent first",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.Release"),
+									},
+								},
+							},
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images are the images of the current release, pinned to the digests their tags had when it was rolled out",
+							Ref:         ref("./pkg/apis/fnresources/v1alpha1.ResolvedImages"),
 						},
 					},
 				},
-				Required: []string{"installProfile", "adminUsername", "adminEmail"},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.Condition", "./pkg/apis/fnresources/v1alpha1.DeployHooksStatus", "./pkg/apis/fnresources/v1alpha1.Release", "./pkg/apis/fnresources/v1alpha1.ResolvedImages", "./pkg/apis/fnresources/v1alpha1.StatusDrupal"},
 	}
 }
 
-func schema_pkg_apis_fnresources_v1alpha1_Site(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Site is the Schema for the sites API",
+				Description: "DrupalOperatorConfig is the Schema for the drupaloperatorconfigs API",
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -351,31 +537,88 @@ func schema_pkg_apis_fnresources_v1alpha1_Site(ref common.ReferenceCallback) com
 					},
 					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteSpec"),
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteStatus"),
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigStatus"),
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/fnresources/v1alpha1.SiteSpec", "./pkg/apis/fnresources/v1alpha1.SiteStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigSpec", "./pkg/apis/fnresources/v1alpha1.DrupalOperatorConfigStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_fnresources_v1alpha1_SiteSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfigSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SiteSpec defines the desired state of Site",
+				Description: "DrupalOperatorConfigSpec defines the desired state of DrupalOperatorConfig. Fields that aren't set keep the operator's built-in defaults.",
 				Properties: map[string]spec.Schema{
-					"domains": {
+					"ingress": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.IngressDefaults"),
+						},
+					},
+					"storage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.StorageDefaults"),
+						},
+					},
+					"scheduling": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SchedulingDefaults"),
+						},
+					},
+					"database": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.DatabaseDefaults"),
+						},
+					},
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.RegistryDefaults"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.DatabaseDefaults", "./pkg/apis/fnresources/v1alpha1.IngressDefaults", "./pkg/apis/fnresources/v1alpha1.RegistryDefaults", "./pkg/apis/fnresources/v1alpha1.SchedulingDefaults", "./pkg/apis/fnresources/v1alpha1.StorageDefaults"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_DrupalOperatorConfigStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DrupalOperatorConfigStatus defines the observed state of DrupalOperatorConfig",
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"errors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Errors lists the problems found when the spec was last validated",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
@@ -387,64 +630,984 @@ func schema_pkg_apis_fnresources_v1alpha1_SiteSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
-					"environment": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.Condition"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_HSTSSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Strict-Transport-Security header sent for the site's domains. MaxAge is in seconds.",
+				Properties: map[string]spec.Schema{
+					"maxAge": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Type:   []string{"integer"},
+							Format: "int64",
 						},
 					},
-					"install": {
+					"includeSubdomains": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("./pkg/apis/fnresources/v1alpha1.InstallSpec"),
+							Type:   []string{"boolean"},
+							Format: "",
 						},
 					},
-					"crons": {
+					"preload": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Ref: ref("./pkg/apis/fnresources/v1alpha1.CronSpec"),
-									},
-								},
-							},
+							Type:   []string{"boolean"},
+							Format: "",
 						},
 					},
-					"tls": {
+				},
+				Required: []string{"maxAge"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_InstallSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Information to install the site",
+				Properties: map[string]spec.Schema{
+					"installProfile": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
+							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"ingressClass": {
+					"adminUsername": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"certIssuer": {
+					"adminEmail": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
 				},
-				Required: []string{"domains", "environment"},
+				Required: []string{"installProfile", "adminUsername", "adminEmail"},
 			},
 		},
-		Dependencies: []string{
-			"./pkg/apis/fnresources/v1alpha1.CronSpec", "./pkg/apis/fnresources/v1alpha1.InstallSpec"},
+		Dependencies: []string{},
 	}
 }
 
-func schema_pkg_apis_fnresources_v1alpha1_SiteStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_fnresources_v1alpha1_MaintenanceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SiteStatus defines the observed state of Site",
-				Properties:  map[string]spec.Schema{},
-			},
-		},
+				Description: "Maintenance mode of a site or environment. Drupal is put in maintenance mode, and requests the site's routes would send to Drupal are answered with the message and a 503 status by a maintenance responder, except those from the allowed IP addresses or CIDR ranges, which it passes on to Drupal.",
+				Properties: map[string]spec.Schema{
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"allowedIPs": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_RegistryDefaults(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RegistryDefaults configure the images run by the operator. Images other than S3Image and MaintenanceImage are repositories, without a tag.",
+				Properties: map[string]spec.Schema{
+					"customerImageRoot": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"apacheImage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"phpFpmImage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"proxySQLImage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"s3Image": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"maintenanceImage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		Dependencies: []string{},
 	}
 }
+
+func schema_pkg_apis_fnresources_v1alpha1_RolloutStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RolloutStrategy represents drupalenvironment.spec.drupal.rolloutStrategy. Exactly one of BlueGreen and Canary is set.",
+				Properties: map[string]spec.Schema{
+					"blueGreen": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.BlueGreenRolloutStrategy"),
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.CanaryRolloutStrategy"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.BlueGreenRolloutStrategy", "./pkg/apis/fnresources/v1alpha1.CanaryRolloutStrategy"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_RouteTarget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "The Service in the site's namespace that a route sends requests to, such as a static file server. An empty target is the Drupal Service of the site's environment.",
+				Properties: map[string]spec.Schema{
+					"serviceName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"servicePort": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_Site(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Site is the Schema for the sites API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.SiteSpec", "./pkg/apis/fnresources/v1alpha1.SiteStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteBackup is the Schema for the sitebackups API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteBackupSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteBackupStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.SiteBackupSpec", "./pkg/apis/fnresources/v1alpha1.SiteBackupStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteBackupSpec defines the desired state of SiteBackup",
+				Properties: map[string]spec.Schema{
+					"site": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"method": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.BackupTarget"),
+						},
+					},
+				},
+				Required: []string{"site", "target"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.BackupTarget"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteBackupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteBackupStatus defines the observed state of SiteBackup",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"jobName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"location": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Location is the path of the backup archive, relative to the root of the target",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sizeBytes": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"checksum": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteClone(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteClone is the Schema for the siteclones API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteCloneSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteCloneStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.SiteCloneSpec", "./pkg/apis/fnresources/v1alpha1.SiteCloneStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteCloneSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteCloneSpec defines the desired state of SiteClone",
+				Properties: map[string]spec.Schema{
+					"site": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Site is the existing Site, in the same namespace, whose database and files are replaced with those of the source",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteCloneSource"),
+						},
+					},
+					"skipFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SkipFiles copies only the database",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"sanitize": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SanitizeSpec"),
+						},
+					},
+				},
+				Required: []string{"site", "source"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.SanitizeSpec", "./pkg/apis/fnresources/v1alpha1.SiteCloneSource"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteCloneStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteCloneStatus defines the observed state of SiteClone",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"step": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"tablesCopied": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"tablesTotal": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"filesJobName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"sanitizeJobName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteRedirect(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "A host that redirects to a URL, such as a vanity domain redirecting to the site's canonical domain. Redirect hosts are answered by the ingress controller or Gateway, so Drupal never sees them. Code is 301 (the default) or 302. With preservePath, the path and query of the request are appended to the path of the URL.",
+				Properties: map[string]spec.Schema{
+					"fromHost": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"toURL": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"code": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"preservePath": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"fromHost", "toURL"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteRestore(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteRestore is the Schema for the siterestores API",
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteRestoreSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteRestoreStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.SiteRestoreSpec", "./pkg/apis/fnresources/v1alpha1.SiteRestoreStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteRestoreSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteRestoreSpec defines the desired state of SiteRestore",
+				Properties: map[string]spec.Schema{
+					"site": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Site is the existing Site the backup is restored into. It may differ from the Site the backup was taken of.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"backup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backup is the name of a succeeded SiteBackup in the same namespace",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"site", "backup"},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteRestoreStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteRestoreStatus defines the observed state of SiteRestore",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"jobName": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"completionTime": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteRoute(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "A path of the site's domains and the backend serving it. When spec.routes is given, the site's domains only route these paths, rather than / to Drupal, and Drupal serves the site under the paths of the routes targeting it.",
+				Properties: map[string]spec.Schema{
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pathType": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"target": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.RouteTarget"),
+						},
+					},
+				},
+				Required: []string{"path"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.RouteTarget"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteSpec defines the desired state of Site",
+				Properties: map[string]spec.Schema{
+					"domains": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Important: Run \"operator-sdk generate k8s\" to regenerate code after modifying this file Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"environment": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"install": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.InstallSpec"),
+						},
+					},
+					"crons": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.CronSpec"),
+									},
+								},
+							},
+						},
+					},
+					"backups": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.BackupsSpec"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"domainTLS": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.DomainTLS"),
+									},
+								},
+							},
+						},
+					},
+					"httpsRedirect": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"hsts": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.HSTSSpec"),
+						},
+					},
+					"routes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteRoute"),
+									},
+								},
+							},
+						},
+					},
+					"redirects": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteRedirect"),
+									},
+								},
+							},
+						},
+					},
+					"maintenance": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.MaintenanceSpec"),
+						},
+					},
+					"ingressClass": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"certIssuer": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"domains", "environment"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.BackupsSpec", "./pkg/apis/fnresources/v1alpha1.CronSpec", "./pkg/apis/fnresources/v1alpha1.DomainTLS", "./pkg/apis/fnresources/v1alpha1.HSTSSpec", "./pkg/apis/fnresources/v1alpha1.InstallSpec", "./pkg/apis/fnresources/v1alpha1.MaintenanceSpec", "./pkg/apis/fnresources/v1alpha1.SiteRedirect", "./pkg/apis/fnresources/v1alpha1.SiteRoute"},
+	}
+}
+
+func schema_pkg_apis_fnresources_v1alpha1_SiteStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SiteStatus defines the observed state of Site",
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"database": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteDatabaseStatus"),
+						},
+					},
+					"install": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteInstallStatus"),
+						},
+					},
+					"backups": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteBackupsStatus"),
+						},
+					},
+					"hosts": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteHostStatus"),
+									},
+								},
+							},
+						},
+					},
+					"jobs": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteJobStatus"),
+									},
+								},
+							},
+						},
+					},
+					"dnsRecords": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteDNSRecord"),
+									},
+								},
+							},
+						},
+					},
+					"redirectHosts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RedirectHosts are the hosts of spec.redirects whose routing objects the Site has created",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"maintenance": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./pkg/apis/fnresources/v1alpha1.SiteMaintenanceStatus"),
+						},
+					},
+					"deployHook": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeployHook is the Job that last brought the Site's database and configuration up to date with a release of its environment",
+							Ref:         ref("./pkg/apis/fnresources/v1alpha1.SiteDeployHookStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/fnresources/v1alpha1.Condition", "./pkg/apis/fnresources/v1alpha1.SiteBackupsStatus", "./pkg/apis/fnresources/v1alpha1.SiteDNSRecord", "./pkg/apis/fnresources/v1alpha1.SiteDatabaseStatus", "./pkg/apis/fnresources/v1alpha1.SiteDeployHookStatus", "./pkg/apis/fnresources/v1alpha1.SiteHostStatus", "./pkg/apis/fnresources/v1alpha1.SiteInstallStatus", "./pkg/apis/fnresources/v1alpha1.SiteJobStatus", "./pkg/apis/fnresources/v1alpha1.SiteMaintenanceStatus"},
+	}
+}
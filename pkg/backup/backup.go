@@ -0,0 +1,358 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+// A backup archive is a gzipped tarball holding the Site's database dump as "database.sql", and the contents of the
+// environment's files directory under its base name ("files"). Backup Jobs dump the database and files in an init
+// container running the customer image, then a "store" container copies the archive to the target. Restore Jobs
+// fetch and verify the archive in an init container, then load it in a container running the customer image.
+//
+// Values are passed to the scripts run by these containers as environment variables, never interpolated into them.
+
+const (
+	// StoreContainerName is the container of a backup Job that stores the archive. It reports the archive's size and
+	// checksum as a JSON Result in its termination message.
+	StoreContainerName = "store"
+
+	workVolumeName   = "backup"
+	workDir          = "/backup"
+	targetVolumeName = "backup-target"
+	targetDir        = "/target"
+
+	// The Site's database is reached through the environment's ProxySQL
+	dbHost = "proxysql"
+	dbPort = "6033"
+
+	s3AccessKeyIDKey     = "accessKeyId"
+	s3SecretAccessKeyKey = "secretAccessKey"
+)
+
+const dumpDrushScript = `set -eu
+mkdir -p /backup/archive
+drush --uri="$SITE_URI" sql-dump --result-file=/backup/archive/database.sql
+`
+
+const dumpMysqldumpScript = `set -eu
+mkdir -p /backup/archive
+mysqldump --single-transaction --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" "$DB_NAME" > /backup/archive/database.sql
+`
+
+const archiveScript = `tar -czf /backup/site.tar.gz -C /backup/archive database.sql -C "$(dirname "$FILES_DIR")" "$(basename "$FILES_DIR")"
+rm -rf /backup/archive
+`
+
+// The store and fetch scripts start with functions to access the target, either pvcFunctions or s3Functions. Paths
+// passed to them are relative to the root of the target.
+
+const pvcFunctions = `set -eu
+upload() { mkdir -p "$(dirname "/target/$1")" && cp /backup/site.tar.gz "/target/$1"; }
+download() { cp "/target/$1" /backup/site.tar.gz; }
+list() { ls -1 "/target/$1"; }
+remove() { rm -f "/target/$1"; }
+`
+
+const s3Functions = `set -eu
+s3() { aws s3 "$@" ${S3_ENDPOINT:+--endpoint-url "$S3_ENDPOINT"}; }
+url() { echo "s3://$S3_BUCKET/${S3_PREFIX:+$S3_PREFIX/}$1"; }
+upload() { s3 cp /backup/site.tar.gz "$(url "$1")"; }
+download() { s3 cp "$(url "$1")" /backup/site.tar.gz; }
+list() { s3 ls "$(url "$1")/" | awk '{print $4}'; }
+remove() { s3 rm "$(url "$1")"; }
+`
+
+// scheduledLocationScript names the archive of a scheduled backup after the time it is taken, in the directory of the
+// series. The names sort in the order the archives were taken.
+const scheduledLocationScript = `BACKUP_DIR="$LOCATION"
+LOCATION="$BACKUP_DIR/$(date -u +%Y%m%dT%H%M%SZ).tar.gz"
+`
+
+const uploadScript = `upload "$LOCATION"
+size=$(($(wc -c < /backup/site.tar.gz)))
+checksum=$(sha256sum /backup/site.tar.gz | cut -d ' ' -f 1)
+printf '{"size":%d,"checksum":"sha256:%s"}' "$size" "$checksum" > /dev/termination-log
+`
+
+// pruneScript removes the archives of a series of scheduled backups that are beyond the retention count, or older than
+// the retention age. The backup has already been stored, so failing to prune doesn't fail it.
+const pruneScript = `prune() {
+  cutoff=""
+  if [ "$RETENTION_AGE_SECONDS" -gt 0 ]; then
+    cutoff=$(date -u -d "@$(($(date +%s) - RETENTION_AGE_SECONDS))" +%Y%m%dT%H%M%SZ)
+  fi
+  n=0
+  for archive in $(list "$BACKUP_DIR" | grep -E '^[0-9]{8}T[0-9]{6}Z\.tar\.gz$' | sort -r); do
+    n=$((n + 1))
+    if [ "$RETENTION_COUNT" -gt 0 ] && [ "$n" -gt "$RETENTION_COUNT" ]; then
+      remove "$BACKUP_DIR/$archive"
+    elif [ -n "$cutoff" ] && [ "$archive" \< "$cutoff" ]; then
+      remove "$BACKUP_DIR/$archive"
+    fi
+  done
+}
+prune || echo "Failed to prune old backups" >&2
+`
+
+const fetchScript = `download "$LOCATION"
+if [ -n "$CHECKSUM" ]; then
+  echo "${CHECKSUM#sha256:}  /backup/site.tar.gz" | sha256sum -c -
+fi
+`
+
+const extractScript = `set -eu
+mkdir -p /backup/archive
+tar -xzf /backup/site.tar.gz -C /backup/archive
+`
+
+const loadDrushScript = `drush --uri="$SITE_URI" sql-drop --yes
+drush --uri="$SITE_URI" sql-cli < /backup/archive/database.sql
+`
+
+// mysqldump output drops and recreates each table it contains, so tables that aren't in the backup are left in place
+const loadMysqlScript = `mysql --host="$DB_HOST" --port="$DB_PORT" --user="$DB_USER" "$DB_NAME" < /backup/archive/database.sql
+`
+
+const replaceFilesScript = `find "$FILES_DIR" -mindepth 1 -delete
+cp -a "/backup/archive/$(basename "$FILES_DIR")/." "$FILES_DIR/"
+`
+
+// Result is reported by the store container of a backup Job in its termination message
+type Result struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// ResultFromPod returns the Result reported by a pod of a backup Job, or nil if its store container hasn't succeeded.
+func ResultFromPod(pod *corev1.Pod) (*Result, error) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != StoreContainerName || cs.State.Terminated == nil || cs.State.Terminated.ExitCode != 0 {
+			continue
+		}
+
+		result := &Result{}
+		if err := json.Unmarshal([]byte(cs.State.Terminated.Message), result); err != nil {
+			return nil, fmt.Errorf("invalid result %q reported by backup pod %s: %v", cs.State.Terminated.Message, pod.Name, err)
+		}
+		return result, nil
+	}
+	return nil, nil
+}
+
+// Location returns the path of an archive taken of a Site, relative to the root of the backup target
+func Location(site *fn.Site, name string) string {
+	return path.Join(site.Namespace, site.Name, name+".tar.gz")
+}
+
+// ScheduledLocation returns the directory holding the scheduled backups of a Site, relative to the root of the backup
+// target
+func ScheduledLocation(site *fn.Site) string {
+	return path.Join(site.Namespace, site.Name, "scheduled")
+}
+
+// Retention limits the archives kept of a series of scheduled backups. Zero values don't limit retention.
+type Retention struct {
+	Count int32
+	Age   time.Duration
+}
+
+// Archive describes a backup archive of a Site and where it is stored
+type Archive struct {
+	// Site is the Site backed up, or restored into
+	Site *fn.Site
+	// SiteURI is the URI drush picks the Site from, see Site.DrushURI
+	SiteURI string
+	// Env is the Site's DrupalEnvironment
+	Env      *fn.DrupalEnvironment
+	Method   fn.BackupMethod
+	Target   fn.BackupTarget
+	Location string
+	// Checksum is verified before a restore, if set
+	Checksum string
+	// Retention, when set, makes the archive one of a series of scheduled backups. Location is then the directory of
+	// the series, and each archive is named after the time it was taken.
+	Retention *Retention
+}
+
+// BackupJobSpec turns the spec of a customer Job into one that writes the archive to its target
+func (a Archive) BackupJobSpec(base batchv1.JobSpec) batchv1.JobSpec {
+	backoffLimit := int32(2)
+
+	script := dumpDrushScript
+	if a.Method == fn.BackupMethodMysqldump {
+		script = dumpMysqldumpScript
+	}
+
+	dump := a.customerContainer(base, "dump", script+archiveScript)
+
+	spec := base
+	spec.BackoffLimit = &backoffLimit
+	spec.Template.Spec.InitContainers = []corev1.Container{dump}
+	spec.Template.Spec.Containers = []corev1.Container{a.transferContainer(dump, StoreContainerName, a.storeScript())}
+	spec.Template.Spec.Volumes = append(spec.Template.Spec.Volumes, a.volumes()...)
+	return spec
+}
+
+// RestoreJobSpec turns the spec of a customer Job into one that replaces the Site's database and files with the
+// contents of the archive. It isn't retried, since a failed restore may have been partially applied.
+func (a Archive) RestoreJobSpec(base batchv1.JobSpec) batchv1.JobSpec {
+	backoffLimit := int32(0)
+
+	script := extractScript + loadDrushScript
+	if a.Method == fn.BackupMethodMysqldump {
+		script = extractScript + loadMysqlScript
+	}
+
+	restore := a.customerContainer(base, "restore", script+replaceFilesScript)
+
+	spec := base
+	spec.BackoffLimit = &backoffLimit
+	spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	spec.Template.Spec.InitContainers = []corev1.Container{a.transferContainer(restore, "fetch", a.targetFunctions()+fetchScript)}
+	spec.Template.Spec.Containers = []corev1.Container{restore}
+	spec.Template.Spec.Volumes = append(spec.Template.Spec.Volumes, a.volumes()...)
+	return spec
+}
+
+// customerContainer returns the customer container of the base Job spec, running the given script with access to the
+// Site's database and files.
+func (a Archive) customerContainer(base batchv1.JobSpec, name, script string) corev1.Container {
+	c := *base.Template.Spec.Containers[0].DeepCopy()
+	c.Name = name
+	c.Command = []string{"sh", "-c", script}
+	c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: workVolumeName, MountPath: workDir})
+
+	c.Env = append(c.Env,
+		corev1.EnvVar{Name: "SITE_URI", Value: a.SiteURI},
+		corev1.EnvVar{Name: "FILES_DIR", Value: customercontainer.FilesVolumeMount(a.Env).MountPath},
+		corev1.EnvVar{Name: "DB_HOST", Value: dbHost},
+		corev1.EnvVar{Name: "DB_PORT", Value: dbPort},
+		corev1.EnvVar{Name: "DB_NAME", Value: a.Site.DatabaseName()},
+		corev1.EnvVar{Name: "DB_USER", Value: a.Site.DatabaseUser()},
+		corev1.EnvVar{
+			// Read by the mysql clients, so the password isn't visible in the process list
+			Name: "MYSQL_PWD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: a.Site.Name + "-password"},
+					Key:                  "password",
+				},
+			},
+		},
+	)
+	return c
+}
+
+func (a Archive) targetFunctions() string {
+	if a.Target.PVC != nil {
+		return pvcFunctions
+	}
+	return s3Functions
+}
+
+func (a Archive) storeScript() string {
+	if a.Retention != nil {
+		return a.targetFunctions() + scheduledLocationScript + uploadScript + pruneScript
+	}
+	return a.targetFunctions() + uploadScript
+}
+
+// transferEnv returns the environment of the scripts run by transferContainer, other than the target's credentials
+func (a Archive) transferEnv() []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "LOCATION", Value: a.Location},
+		{Name: "CHECKSUM", Value: a.Checksum},
+	}
+	if a.Retention != nil {
+		env = append(env,
+			corev1.EnvVar{Name: "RETENTION_COUNT", Value: strconv.Itoa(int(a.Retention.Count))},
+			corev1.EnvVar{Name: "RETENTION_AGE_SECONDS", Value: strconv.FormatInt(int64(a.Retention.Age/time.Second), 10)},
+		)
+	}
+	return env
+}
+
+// transferContainer returns a container with access to the target, running the given script. Archives on a PVC are
+// copied with the customer image, so no other image has to be pulled.
+func (a Archive) transferContainer(customer corev1.Container, name, script string) corev1.Container {
+	workMount := corev1.VolumeMount{Name: workVolumeName, MountPath: workDir}
+
+	if a.Target.PVC != nil {
+		return corev1.Container{
+			Name:            name,
+			Image:           customer.Image,
+			ImagePullPolicy: customer.ImagePullPolicy,
+			Command:         []string{"sh", "-c", script},
+			Env:             a.transferEnv(),
+			Resources:       customer.Resources,
+			VolumeMounts: []corev1.VolumeMount{
+				workMount,
+				{Name: targetVolumeName, MountPath: targetDir, SubPath: a.Target.PVC.SubPath},
+			},
+		}
+	}
+
+	s3 := a.Target.S3
+	return corev1.Container{
+		Name:    name,
+		Image:   registry.Current().S3Image,
+		Command: []string{"sh", "-c", script},
+		Env: append(a.transferEnv(),
+			corev1.EnvVar{Name: "S3_ENDPOINT", Value: s3.Endpoint},
+			corev1.EnvVar{Name: "S3_BUCKET", Value: s3.Bucket},
+			corev1.EnvVar{Name: "S3_PREFIX", Value: strings.Trim(s3.Prefix, "/")},
+			corev1.EnvVar{Name: "AWS_DEFAULT_REGION", Value: s3.Region},
+			corev1.EnvVar{Name: "AWS_ACCESS_KEY_ID", ValueFrom: s3CredentialsSource(s3, s3AccessKeyIDKey)},
+			corev1.EnvVar{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: s3CredentialsSource(s3, s3SecretAccessKeyKey)},
+		),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{workMount},
+	}
+}
+
+func s3CredentialsSource(s3 *fn.S3BackupTarget, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: s3.CredentialsSecret},
+			Key:                  key,
+		},
+	}
+}
+
+func (a Archive) volumes() []corev1.Volume {
+	volumes := []corev1.Volume{{
+		Name:         workVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+	if a.Target.PVC != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: targetVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: a.Target.PVC.ClaimName},
+			},
+		})
+	}
+	return volumes
+}
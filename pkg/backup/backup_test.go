@@ -0,0 +1,230 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+func TestResultFromPod(t *testing.T) {
+	terminated := func(name string, exitCode int32, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}},
+		}
+	}
+	cases := []struct {
+		name     string
+		statuses []corev1.ContainerStatus
+		want     *Result
+		wantErr  bool
+	}{
+		{name: "no container statuses"},
+		{
+			name:     "store running",
+			statuses: []corev1.ContainerStatus{{Name: StoreContainerName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+		},
+		{
+			name:     "store failed",
+			statuses: []corev1.ContainerStatus{terminated(StoreContainerName, 1, "upload failed")},
+		},
+		{
+			name:     "other container succeeded",
+			statuses: []corev1.ContainerStatus{terminated("dump", 0, `{"size":1,"checksum":"sha256:00"}`)},
+		},
+		{
+			name: "store succeeded",
+			statuses: []corev1.ContainerStatus{
+				terminated("sidecar", 0, ""),
+				terminated(StoreContainerName, 0, `{"size":1048576,"checksum":"sha256:9f86d081884c7d65"}`),
+			},
+			want: &Result{Size: 1048576, Checksum: "sha256:9f86d081884c7d65"},
+		},
+		{
+			name:     "invalid result",
+			statuses: []corev1.ContainerStatus{terminated(StoreContainerName, 0, "size=1")},
+			wantErr:  true,
+		},
+	}
+	for _, c := range cases {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "wlgore-backup-x7k2p"},
+			Status:     corev1.PodStatus{ContainerStatuses: c.statuses},
+		}
+		got, err := ResultFromPod(pod)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: ResultFromPod returned error %v, want error: %t", c.name, err, c.wantErr)
+			continue
+		}
+		if (got == nil) != (c.want == nil) || got != nil && *got != *c.want {
+			t.Errorf("%s: ResultFromPod returned %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+// newTestArchive returns an Archive whose values would break out of the scripts if they were interpolated into them
+func newTestArchive(target fn.BackupTarget) Archive {
+	return Archive{
+		Site:     &fn.Site{ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: "wlgore-prod"}},
+		SiteURI:  `https://wlgore.example.com/"; touch /pwned; "`,
+		Env:      &fn.DrupalEnvironment{ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "wlgore-prod"}},
+		Method:   fn.BackupMethodDrush,
+		Target:   target,
+		Location: `wlgore-prod/wlgore/$(touch /pwned)'.tar.gz`,
+		Checksum: "sha256:`touch /pwned`",
+	}
+}
+
+func newTestBaseJobSpec() batchv1.JobSpec {
+	return batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "drush", Image: "registry.example.com/wlgore:v1"}},
+			},
+		},
+	}
+}
+
+func envValue(c corev1.Container, name string) (string, bool) {
+	for _, env := range c.Env {
+		if env.Name == name {
+			return env.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestJobSpecsPassValuesInEnvironment(t *testing.T) {
+	targets := map[string]fn.BackupTarget{
+		"pvc": {PVC: &fn.PVCBackupTarget{ClaimName: "backups", SubPath: "sites"}},
+		"s3":  {S3: &fn.S3BackupTarget{Bucket: "backups", Prefix: "/sites/", CredentialsSecret: "s3-credentials"}},
+	}
+	for targetName, target := range targets {
+		archive := newTestArchive(target)
+		specs := map[string]batchv1.JobSpec{
+			"backup":  archive.BackupJobSpec(newTestBaseJobSpec()),
+			"restore": archive.RestoreJobSpec(newTestBaseJobSpec()),
+		}
+		archive.Retention = &Retention{Count: 7}
+		specs["scheduled backup"] = archive.BackupJobSpec(newTestBaseJobSpec())
+
+		for specName, spec := range specs {
+			pod := spec.Template.Spec
+			for _, c := range append(pod.InitContainers, pod.Containers...) {
+				script := strings.Join(c.Command, " ")
+				if strings.Contains(script, "pwned") || strings.Contains(script, archive.Site.Name) {
+					t.Errorf("%s %s: container %s interpolates values into its script: %s", targetName, specName, c.Name, script)
+				}
+			}
+
+			customer, transfer := pod.InitContainers[0], pod.Containers[0]
+			if specName == "restore" {
+				customer, transfer = pod.Containers[0], pod.InitContainers[0]
+			}
+			for name, want := range map[string]string{"SITE_URI": archive.SiteURI, "DB_NAME": "wlgore", "DB_USER": "wlgore"} {
+				if value, _ := envValue(customer, name); value != want {
+					t.Errorf("%s %s: %s of container %s is %q, want %q", targetName, specName, name, customer.Name, value, want)
+				}
+			}
+			if value, _ := envValue(transfer, "LOCATION"); value != archive.Location {
+				t.Errorf("%s %s: LOCATION of container %s is %q, want %q", targetName, specName, transfer.Name, value, archive.Location)
+			}
+			if target.S3 != nil {
+				if value, _ := envValue(transfer, "S3_PREFIX"); value != "sites" {
+					t.Errorf("%s %s: S3_PREFIX is %q, want sites", targetName, specName, value)
+				}
+			}
+			_, hasRetention := envValue(transfer, "RETENTION_COUNT")
+			if hasRetention != (specName == "scheduled backup") {
+				t.Errorf("%s %s: RETENTION_COUNT set: %t", targetName, specName, hasRetention)
+			}
+		}
+	}
+}
+
+func TestJobSpecsBackoffLimit(t *testing.T) {
+	archive := newTestArchive(fn.BackupTarget{PVC: &fn.PVCBackupTarget{ClaimName: "backups"}})
+
+	backup := archive.BackupJobSpec(newTestBaseJobSpec())
+	if backup.BackoffLimit == nil || *backup.BackoffLimit != 2 {
+		t.Errorf("backoffLimit of the backup Job is %v, want 2", backup.BackoffLimit)
+	}
+
+	// A failed restore may have been partially applied, so it must not be retried
+	restore := archive.RestoreJobSpec(newTestBaseJobSpec())
+	if restore.BackoffLimit == nil || *restore.BackoffLimit != 0 {
+		t.Errorf("backoffLimit of the restore Job is %v, want 0", restore.BackoffLimit)
+	}
+	if restore.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("restartPolicy of the restore Job is %q, want %q", restore.Template.Spec.RestartPolicy, corev1.RestartPolicyNever)
+	}
+	if names := []string{restore.Template.Spec.InitContainers[0].Name, restore.Template.Spec.Containers[0].Name}; names[0] != "fetch" || names[1] != "restore" {
+		t.Errorf("restore Job runs containers %v, want the archive fetched before it is restored", names)
+	}
+}
+
+// TestFetchVerifiesChecksum runs the fetch container's script against a PVC target, with the work and target
+// directories moved to a temporary directory
+func TestFetchVerifiesChecksum(t *testing.T) {
+	for _, tool := range []string{"sh", "sha256sum"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+	}
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, sub := range []string{"backup", "target/wlgore-prod/wlgore"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	contents := []byte("site archive")
+	if err := ioutil.WriteFile(filepath.Join(dir, "target/wlgore-prod/wlgore/manual.tar.gz"), contents, 0644); err != nil {
+		t.Fatal(err)
+	}
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256(contents))
+
+	cases := []struct {
+		checksum string
+		ok       bool
+	}{
+		{checksum, true},
+		{"", true},
+		{fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("another archive"))), false},
+	}
+	for _, c := range cases {
+		archive := Archive{
+			Site:     &fn.Site{ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: "wlgore-prod"}},
+			Env:      &fn.DrupalEnvironment{},
+			Target:   fn.BackupTarget{PVC: &fn.PVCBackupTarget{ClaimName: "backups"}},
+			Location: "wlgore-prod/wlgore/manual.tar.gz",
+			Checksum: c.checksum,
+		}
+		fetch := archive.RestoreJobSpec(newTestBaseJobSpec()).Template.Spec.InitContainers[0]
+		script := strings.NewReplacer(workDir+"/", dir+"/backup/", targetDir+"/", dir+"/target/").Replace(fetch.Command[2])
+
+		cmd := exec.Command(fetch.Command[0], fetch.Command[1], script)
+		for _, env := range fetch.Env {
+			cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+		}
+		cmd.Env = append(cmd.Env, "PATH="+os.Getenv("PATH"))
+		out, err := cmd.CombinedOutput()
+		if (err == nil) != c.ok {
+			t.Errorf("fetching with checksum %q returned %v, want success: %t\n%s", c.checksum, err, c.ok, out)
+		}
+	}
+}
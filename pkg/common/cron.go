@@ -0,0 +1,119 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes one of the five fields of a standard cron schedule
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronDescriptors = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// ValidateSchedule returns an error if the schedule isn't accepted by the CronJob controller: five space-separated
+// fields, a descriptor such as "@daily", or "@every <duration>".
+func ValidateSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if strings.HasPrefix(schedule, "@") {
+		if strings.HasPrefix(schedule, "@every ") {
+			d, err := time.ParseDuration(strings.TrimPrefix(schedule, "@every "))
+			if err != nil {
+				return fmt.Errorf("invalid duration in %q: %v", schedule, err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("the duration in %q must be positive", schedule)
+			}
+			return nil
+		}
+		for _, d := range cronDescriptors {
+			if schedule == d {
+				return nil
+			}
+		}
+		return fmt.Errorf("unrecognized descriptor %q", schedule)
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields in %q, found %d", len(cronFields), schedule, len(fields))
+	}
+	for i, field := range fields {
+		if err := cronFields[i].validate(field); err != nil {
+			return fmt.Errorf("invalid %s %q: %v", cronFields[i].name, field, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a comma-separated list of values, ranges and steps, such as "1-5,*/15"
+func (f cronField) validate(field string) error {
+	for _, part := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(part, "/")
+		if len(rangeAndStep) > 2 {
+			return fmt.Errorf("too many slashes")
+		}
+		if len(rangeAndStep) == 2 {
+			step, err := strconv.Atoi(rangeAndStep[1])
+			if err != nil || step <= 0 {
+				return fmt.Errorf("step %q must be a positive number", rangeAndStep[1])
+			}
+		}
+
+		r := rangeAndStep[0]
+		if r == "*" || r == "?" {
+			continue
+		}
+		bounds := strings.Split(r, "-")
+		if len(bounds) > 2 {
+			return fmt.Errorf("too many hyphens")
+		}
+		start, err := f.value(bounds[0])
+		if err != nil {
+			return err
+		}
+		if len(bounds) == 2 {
+			end, err := f.value(bounds[1])
+			if err != nil {
+				return err
+			}
+			if end < start {
+				return fmt.Errorf("range %q ends before it starts", r)
+			}
+		}
+	}
+	return nil
+}
+
+// value parses a number or, for months and days of the week, a three-letter name
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
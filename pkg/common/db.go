@@ -14,6 +14,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
 )
 
 type Database struct {
@@ -24,11 +26,29 @@ type Database struct {
 	Password string `json:"pass"`
 }
 
+// ProxySQL admin credentials are generated by the DrupalEnvironment controller into this Secret, in each environment's
+// namespace.
+const (
+	ProxySqlAdminSecretName = "proxysql-admin"
+	ProxySqlAdminUser       = "proxysql-admin"
+)
+
 func RandPassword() (string, error) {
-	chars := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
-		"abcdefghijklmnopqrstuvwxyz" +
-		"0123456789" +
-		`~!@#$%^&*()_+-=[]{}:,./?`)
+	return RandPasswordWithout("")
+}
+
+// RandPasswordWithout generates a password that doesn't contain any of the excluded characters, for use in
+// configuration formats where they have a special meaning.
+func RandPasswordWithout(excluded string) (string, error) {
+	chars := []rune(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(excluded, r) {
+			return -1
+		}
+		return r
+	}, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"+
+		"abcdefghijklmnopqrstuvwxyz"+
+		"0123456789"+
+		`~!@#$%^&*()_+-=[]{}:,./?`))
 	length := 12
 	var b strings.Builder
 	for i := 0; i < length; i++ {
@@ -41,9 +61,13 @@ func RandPassword() (string, error) {
 	return b.String(), nil
 }
 
+// GetAdminDB returns the credentials of the cluster DB, read from the admin Secret set in the DrupalOperatorConfig. The
+// DB_USER_OVERRIDE and DB_PASSWORD_OVERRIDE environment variables take precedence over the Secret, as do the host and
+// port of the DrupalOperatorConfig, which default to DB_HOST_OVERRIDE and DB_PORT_OVERRIDE.
 func GetAdminDB(c client.Client) (Database, error) {
+	config := operatorconfig.Current()
 	dbAdminSecret := &corev1.Secret{}
-	if err := c.Get(context.TODO(), types.NamespacedName{Name: "default-cluster-creds", Namespace: "default"}, dbAdminSecret); err != nil {
+	if err := c.Get(context.TODO(), config.AdminSecret, dbAdminSecret); err != nil {
 		return Database{}, err
 	}
 
@@ -59,15 +83,14 @@ func GetAdminDB(c client.Client) (Database, error) {
 		db.Password = passwd
 	} else {
 		db.Password = string(data["password"])
-
 	}
-	if host := os.Getenv("DB_HOST_OVERRIDE"); host != "" {
-		db.Host = host
+	if config.DatabaseHost != "" {
+		db.Host = config.DatabaseHost
 	} else {
 		db.Host = string(data["host"])
 	}
-	if port := os.Getenv("DB_PORT_OVERRIDE"); port != "" {
-		db.Port = port
+	if config.DatabasePort != "" {
+		db.Port = config.DatabasePort
 	} else {
 		db.Port = string(data["port"])
 	}
@@ -100,6 +123,18 @@ func GetAdminConnection(c client.Client) (*sql.DB, error) {
 }
 
 func GetProxySqlAdminConnection(c client.Client, namespace string) (*sql.DB, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: ProxySqlAdminSecretName, Namespace: namespace}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetProxySqlAdminConnectionAs(c, namespace, string(secret.Data["username"]), string(secret.Data["password"]))
+}
+
+// GetProxySqlAdminConnectionAs connects to the ProxySQL admin interface with the given credentials, rather than the
+// ones in the admin Secret. This is needed while the credentials are being rotated.
+func GetProxySqlAdminConnectionAs(c client.Client, namespace, user, password string) (*sql.DB, error) {
 	// TODO - check if 'proxysql' Deployment is Ready before attempting to connect
 
 	found := &corev1.Service{}
@@ -111,8 +146,8 @@ func GetProxySqlAdminConnection(c client.Client, namespace string) (*sql.DB, err
 	proxySqlDb := Database{
 		Host:     found.Spec.ClusterIP,
 		Name:     "main",
-		User:     "proxysql-admin",
-		Password: "adminpassw0rd", // FIXME !!! - https://backlog.acquia.com/browse/FN-240
+		User:     user,
+		Password: password,
 		Port:     "6032",
 	}
 
@@ -0,0 +1,271 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DatabaseProvisioner manages the lifecycle of Site databases and users, and copies the contents of one Site's database
+// into another's. All operations are idempotent, so they can be repeated on every reconcile.
+type DatabaseProvisioner interface {
+	// EnsureDatabase creates the database if it doesn't exist
+	EnsureDatabase(name string) error
+	// EnsureUser creates the user with the given password if it doesn't exist. The password of an existing user is left
+	// unchanged; see RotatePassword.
+	EnsureUser(user, password string) error
+	// Grant gives the user all privileges on the database
+	Grant(database, user string) error
+	// DropDatabase drops the database if it exists
+	DropDatabase(name string) error
+	// DropUser drops the user if it exists
+	DropUser(user string) error
+	// RotatePassword sets the password of an existing user
+	RotatePassword(user, password string) error
+	// ListTables returns the names of the tables in the database, in order
+	ListTables(database string) ([]string, error)
+	// DropTables drops all of the tables in the database, leaving the database itself in place
+	DropTables(database string) error
+	// CopyTable replaces a table of the target database with a copy of the same table of the source database
+	CopyTable(source, target, table string) error
+}
+
+// UnavailableError is returned by a DatabaseProvisioner when a database server can't be reached, for example because it
+// is still starting. The operation should be retried later.
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return "database unavailable: " + e.Err.Error()
+}
+
+// IsUnavailable returns true if err is an UnavailableError
+func IsUnavailable(err error) bool {
+	_, ok := err.(*UnavailableError)
+	return ok
+}
+
+const (
+	// errCannotUser (1396) is returned by MySQL when creating a user that exists, or dropping one that doesn't
+	errCannotUser = 1396
+	// errProxySQLAdmin (1045) is returned by the ProxySQL admin interface for almost all failed statements
+	errProxySQLAdmin = 1045
+	// errAccessDenied (1045) is returned when connecting with wrong credentials
+	errAccessDenied = 1045
+
+	// proxySQLHostgroup is the ProxySQL hostgroup holding the cluster DB
+	proxySQLHostgroup = 1
+)
+
+// MySQLProvisioner provisions databases and users in the cluster DB, and registers the users with the ProxySQL of the
+// namespace the Site is in. It opens new connections for every operation.
+type MySQLProvisioner struct {
+	client    client.Client
+	namespace string
+}
+
+var _ DatabaseProvisioner = &MySQLProvisioner{}
+
+// NewMySQLProvisioner returns a MySQLProvisioner for Sites in the given namespace
+func NewMySQLProvisioner(c client.Client, namespace string) DatabaseProvisioner {
+	return &MySQLProvisioner{client: c, namespace: namespace}
+}
+
+func (p *MySQLProvisioner) withAdminDB(f func(*sql.DB) error) error {
+	adminDB, err := GetAdminConnection(p.client)
+	if err != nil {
+		return err
+	}
+	defer adminDB.Close()
+
+	if err := adminDB.Ping(); err != nil {
+		return &UnavailableError{Err: err}
+	}
+	return f(adminDB)
+}
+
+// withProxySQL runs f against the ProxySQL admin interface. If ProxySQL has no Service, there is nothing to do, so f is
+// only run when skipIfMissing is false, in which case the NotFound error is returned.
+func (p *MySQLProvisioner) withProxySQL(skipIfMissing bool, f func(*sql.DB) error) error {
+	proxySqlAdmin, err := GetProxySqlAdminConnection(p.client, p.namespace)
+	if err != nil {
+		if errors.IsNotFound(err) && skipIfMissing {
+			return nil
+		}
+		return err
+	}
+	defer proxySqlAdmin.Close()
+
+	if err := proxySqlAdmin.Ping(); err != nil {
+		return &UnavailableError{Err: err}
+	}
+	return f(proxySqlAdmin)
+}
+
+func execAll(db *sql.DB, queries ...string) error {
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isMySQLError(err error, number uint16) bool {
+	driverError, ok := err.(*mysql.MySQLError)
+	return ok && driverError.Number == number
+}
+
+// IsAccessDenied returns true if the error is a MySQL server, or the ProxySQL admin interface, rejecting credentials
+func IsAccessDenied(err error) bool {
+	return isMySQLError(err, errAccessDenied)
+}
+
+func (p *MySQLProvisioner) EnsureDatabase(name string) error {
+	return p.withAdminDB(func(db *sql.DB) error {
+		return execAll(db, CreateDatabaseSQL(name))
+	})
+}
+
+func (p *MySQLProvisioner) EnsureUser(user, password string) error {
+	err := p.withAdminDB(func(db *sql.DB) error {
+		if _, err := db.Exec(CreateUserSQL(user)); err != nil {
+			if isMySQLError(err, errCannotUser) {
+				return nil
+			}
+			return err
+		}
+		return execAll(db, SetPasswordSQL(user, password))
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.withProxySQL(false, func(db *sql.DB) error {
+		if _, err := db.Exec(ProxySQLInsertUserSQL(user, password, proxySQLHostgroup)); err != nil {
+			// The user is already registered
+			if isMySQLError(err, errProxySQLAdmin) {
+				return nil
+			}
+			return err
+		}
+		return execAll(db, `LOAD MYSQL USERS TO RUNTIME`, `SAVE MYSQL USERS TO DISK`)
+	})
+}
+
+func (p *MySQLProvisioner) Grant(database, user string) error {
+	return p.withAdminDB(func(db *sql.DB) error {
+		return execAll(db, GrantAllSQL(database, user), "FLUSH PRIVILEGES")
+	})
+}
+
+func (p *MySQLProvisioner) DropDatabase(name string) error {
+	return p.withAdminDB(func(db *sql.DB) error {
+		return execAll(db, DropDatabaseSQL(name))
+	})
+}
+
+func (p *MySQLProvisioner) DropUser(user string) error {
+	err := p.withAdminDB(func(db *sql.DB) error {
+		if _, err := db.Exec(DropUserSQL(user)); err != nil && !isMySQLError(err, errCannotUser) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.withProxySQL(true, func(db *sql.DB) error {
+		if _, err := db.Exec(ProxySQLDeleteUserSQL(user)); err != nil && !isMySQLError(err, errProxySQLAdmin) {
+			return err
+		}
+		return execAll(db, `LOAD MYSQL USERS TO RUNTIME`, `SAVE MYSQL USERS TO DISK`)
+	})
+}
+
+func (p *MySQLProvisioner) RotatePassword(user, password string) error {
+	err := p.withAdminDB(func(db *sql.DB) error {
+		if _, err := db.Exec(SetPasswordSQL(user, password)); err != nil {
+			return fmt.Errorf("failed to set password of user %s: %v", user, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.withProxySQL(false, func(db *sql.DB) error {
+		return execAll(db,
+			ProxySQLSetUserPasswordSQL(user, password),
+			`LOAD MYSQL USERS TO RUNTIME`,
+			`SAVE MYSQL USERS TO DISK`)
+	})
+}
+
+func (p *MySQLProvisioner) ListTables(database string) ([]string, error) {
+	var tables []string
+	err := p.withAdminDB(func(db *sql.DB) error {
+		rows, err := db.Query(ListTablesSQL(database))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				return err
+			}
+			tables = append(tables, table)
+		}
+		return rows.Err()
+	})
+	return tables, err
+}
+
+// withoutForeignKeyChecks runs the queries on a single connection with foreign key checks disabled, so tables can be
+// dropped and filled in any order
+func withoutForeignKeyChecks(db *sql.DB, queries ...string) error {
+	conn, err := db.Conn(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, query := range append([]string{"SET SESSION foreign_key_checks = 0"}, queries...) {
+		if _, err := conn.ExecContext(context.TODO(), query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MySQLProvisioner) DropTables(database string) error {
+	tables, err := p.ListTables(database)
+	if err != nil {
+		return err
+	}
+
+	return p.withAdminDB(func(db *sql.DB) error {
+		var queries []string
+		for _, table := range tables {
+			queries = append(queries, DropTableSQL(database, table))
+		}
+		return withoutForeignKeyChecks(db, queries...)
+	})
+}
+
+func (p *MySQLProvisioner) CopyTable(source, target, table string) error {
+	return p.withAdminDB(func(db *sql.DB) error {
+		return withoutForeignKeyChecks(db,
+			DropTableSQL(target, table),
+			CreateTableLikeSQL(target, source, table),
+			CopyRowsSQL(target, source, table))
+	})
+}
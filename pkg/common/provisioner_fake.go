@@ -0,0 +1,166 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FakeDatabaseProvisioner is an in-memory DatabaseProvisioner, for testing controllers without a database server.
+type FakeDatabaseProvisioner struct {
+	mu sync.Mutex
+
+	// Databases is the set of existing databases
+	Databases map[string]bool
+	// Users maps existing users to their passwords
+	Users map[string]string
+	// Grants maps each database to the set of users with privileges on it
+	Grants map[string]map[string]bool
+	// Tables maps each database to the set of tables in it
+	Tables map[string]map[string]bool
+	// Unavailable makes every operation fail with an UnavailableError, when set
+	Unavailable bool
+}
+
+var _ DatabaseProvisioner = &FakeDatabaseProvisioner{}
+
+// NewFakeDatabaseProvisioner returns an empty FakeDatabaseProvisioner
+func NewFakeDatabaseProvisioner() *FakeDatabaseProvisioner {
+	return &FakeDatabaseProvisioner{
+		Databases: map[string]bool{},
+		Users:     map[string]string{},
+		Grants:    map[string]map[string]bool{},
+		Tables:    map[string]map[string]bool{},
+	}
+}
+
+func (f *FakeDatabaseProvisioner) lock() error {
+	f.mu.Lock()
+	if f.Unavailable {
+		f.mu.Unlock()
+		return &UnavailableError{Err: fmt.Errorf("fake database is unavailable")}
+	}
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) EnsureDatabase(name string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	f.Databases[name] = true
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) EnsureUser(user, password string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	if _, ok := f.Users[user]; !ok {
+		f.Users[user] = password
+	}
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) Grant(database, user string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	if !f.Databases[database] {
+		return fmt.Errorf("database %s does not exist", database)
+	}
+	if _, ok := f.Users[user]; !ok {
+		return fmt.Errorf("user %s does not exist", user)
+	}
+	if f.Grants[database] == nil {
+		f.Grants[database] = map[string]bool{}
+	}
+	f.Grants[database][user] = true
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) DropDatabase(name string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	delete(f.Databases, name)
+	delete(f.Grants, name)
+	delete(f.Tables, name)
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) DropUser(user string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	delete(f.Users, user)
+	for _, users := range f.Grants {
+		delete(users, user)
+	}
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) RotatePassword(user, password string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	if _, ok := f.Users[user]; !ok {
+		return fmt.Errorf("user %s does not exist", user)
+	}
+	f.Users[user] = password
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) ListTables(database string) ([]string, error) {
+	if err := f.lock(); err != nil {
+		return nil, err
+	}
+	defer f.mu.Unlock()
+
+	var tables []string
+	for table := range f.Tables[database] {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func (f *FakeDatabaseProvisioner) DropTables(database string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	delete(f.Tables, database)
+	return nil
+}
+
+func (f *FakeDatabaseProvisioner) CopyTable(source, target, table string) error {
+	if err := f.lock(); err != nil {
+		return err
+	}
+	defer f.mu.Unlock()
+
+	if !f.Tables[source][table] {
+		return fmt.Errorf("table %s.%s does not exist", source, table)
+	}
+	if !f.Databases[target] {
+		return fmt.Errorf("database %s does not exist", target)
+	}
+	if f.Tables[target] == nil {
+		f.Tables[target] = map[string]bool{}
+	}
+	f.Tables[target][table] = true
+	return nil
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Statements sent to the cluster DB and to the ProxySQL admin interface are built here, so that every identifier and
+// string literal is quoted. Neither DDL nor the ProxySQL admin interface support placeholders, so the values can't be
+// passed as query parameters instead.
+
+const (
+	// MaxDatabaseNameLength is the longest database name MySQL accepts
+	MaxDatabaseNameLength = 64
+	// MaxUserNameLength is the longest user name MySQL 5.7 accepts
+	MaxUserNameLength = 32
+
+	// anyHost is the host part of the accounts created for Sites
+	anyHost = "%"
+)
+
+// identifierRegexp matches the characters allowed in unquoted MySQL identifiers, restricted to ASCII
+var identifierRegexp = regexp.MustCompile(`^[0-9a-zA-Z$_]+$`)
+
+// ValidateIdentifier returns an error if name can't be used as a MySQL identifier of at most maxLength characters.
+// Identifiers are always quoted, but names are kept to the unquoted rules so they can be typed in a mysql shell.
+func ValidateIdentifier(name string, maxLength int) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("identifier must not be empty")
+	case len(name) > maxLength:
+		return fmt.Errorf("identifier %q is longer than %d characters", name, maxLength)
+	case !identifierRegexp.MatchString(name):
+		return fmt.Errorf("identifier %q may only contain the characters [0-9a-zA-Z$_]", name)
+	case strings.Trim(name, "0123456789") == "":
+		return fmt.Errorf("identifier %q must not consist solely of digits", name)
+	}
+	return nil
+}
+
+// QuoteIdentifier quotes a MySQL identifier, such as a database name, with backticks.
+func QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+var literalReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	`"`, `\"`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+// QuoteLiteral quotes a MySQL string literal, escaping the characters that are special within it. It assumes the
+// NO_BACKSLASH_ESCAPES SQL mode is not enabled.
+func QuoteLiteral(value string) string {
+	return "'" + literalReplacer.Replace(value) + "'"
+}
+
+// QuoteProxySQLLiteral quotes a string literal for the ProxySQL admin interface. It is backed by SQLite, which doesn't
+// treat backslashes as escapes, so only single quotes are escaped, by doubling them.
+func QuoteProxySQLLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func quoteAccount(user string) string {
+	return QuoteLiteral(user) + "@" + QuoteLiteral(anyHost)
+}
+
+// Cluster DB statements
+
+func CreateDatabaseSQL(name string) string {
+	return "CREATE DATABASE IF NOT EXISTS " + QuoteIdentifier(name)
+}
+
+func DropDatabaseSQL(name string) string {
+	return "DROP DATABASE IF EXISTS " + QuoteIdentifier(name)
+}
+
+func CreateUserSQL(user string) string {
+	return "CREATE USER " + quoteAccount(user)
+}
+
+func DropUserSQL(user string) string {
+	return "DROP USER " + quoteAccount(user)
+}
+
+func SetPasswordSQL(user, password string) string {
+	return fmt.Sprintf("SET PASSWORD FOR %s = PASSWORD(%s)", quoteAccount(user), QuoteLiteral(password))
+}
+
+func GrantAllSQL(database, user string) string {
+	return fmt.Sprintf("GRANT ALL PRIVILEGES ON %s.* TO %s", QuoteIdentifier(database), quoteAccount(user))
+}
+
+// ListTablesSQL lists the base tables of a database. Views aren't copied with the tables, so they are left out.
+func ListTablesSQL(database string) string {
+	return "SELECT table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema = " +
+		QuoteLiteral(database) + " ORDER BY table_name"
+}
+
+func DropTableSQL(database, table string) string {
+	return "DROP TABLE IF EXISTS " + QuoteIdentifier(database) + "." + QuoteIdentifier(table)
+}
+
+func CreateTableLikeSQL(target, source, table string) string {
+	return fmt.Sprintf("CREATE TABLE %s.%s LIKE %s.%s",
+		QuoteIdentifier(target), QuoteIdentifier(table), QuoteIdentifier(source), QuoteIdentifier(table))
+}
+
+func CopyRowsSQL(target, source, table string) string {
+	return fmt.Sprintf("INSERT INTO %s.%s SELECT * FROM %s.%s",
+		QuoteIdentifier(target), QuoteIdentifier(table), QuoteIdentifier(source), QuoteIdentifier(table))
+}
+
+// ProxySQL admin statements
+
+func ProxySQLInsertUserSQL(user, password string, hostgroup int) string {
+	return fmt.Sprintf("INSERT INTO mysql_users(username,password,default_hostgroup) VALUES (%s,%s,%d)",
+		QuoteProxySQLLiteral(user), QuoteProxySQLLiteral(password), hostgroup)
+}
+
+func ProxySQLSetUserPasswordSQL(user, password string) string {
+	return fmt.Sprintf("UPDATE mysql_users SET password=%s WHERE username=%s",
+		QuoteProxySQLLiteral(password), QuoteProxySQLLiteral(user))
+}
+
+func ProxySQLDeleteUserSQL(user string) string {
+	return "DELETE FROM mysql_users WHERE username=" + QuoteProxySQLLiteral(user)
+}
+
+func ProxySQLCountServersSQL(host string) string {
+	return "SELECT COUNT(*) FROM mysql_servers WHERE hostname=" + QuoteProxySQLLiteral(host)
+}
+
+func ProxySQLInsertServerSQL(hostgroup int, host string, port int) string {
+	return fmt.Sprintf("INSERT INTO mysql_servers(hostgroup_id,hostname,port) VALUES (%d,%s,%d)",
+		hostgroup, QuoteProxySQLLiteral(host), port)
+}
+
+func ProxySQLSetVariableSQL(name, value string) string {
+	return fmt.Sprintf("UPDATE global_variables SET variable_value=%s WHERE variable_name=%s",
+		QuoteProxySQLLiteral(value), QuoteProxySQLLiteral(name))
+}
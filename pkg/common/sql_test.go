@@ -0,0 +1,104 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"wlgore", "`wlgore`"},
+		{"wl`gore", "`wl``gore`"},
+		{"``", "``````"},
+		{"wl'gore", "`wl'gore`"},
+		{`wl\gore`, "`wl\\gore`"},
+		{"wl\x00gore", "`wl\x00gore`"},
+		{"wl\ngore", "`wl\ngore`"},
+	}
+	for _, c := range cases {
+		if got := QuoteIdentifier(c.name); got != c.want {
+			t.Errorf("QuoteIdentifier(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"wlgore", `'wlgore'`},
+		{"", `''`},
+		{"it's", `'it\'s'`},
+		{`C:\drupal`, `'C:\\drupal'`},
+		{`\'; DROP DATABASE wlgore; --`, `'\\\'; DROP DATABASE wlgore; --'`},
+		{"wl`gore", "'wl`gore'"},
+		{`say "hi"`, `'say \"hi\"'`},
+		{"wl\x00gore", `'wl\0gore'`},
+		{"wl\ngore\r", `'wl\ngore\r'`},
+		{"wl\x1agore", `'wl\Zgore'`},
+	}
+	for _, c := range cases {
+		if got := QuoteLiteral(c.value); got != c.want {
+			t.Errorf("QuoteLiteral(%q) = %s, want %s", c.value, got, c.want)
+		}
+	}
+}
+
+func TestQuoteProxySQLLiteral(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"wlgore", `'wlgore'`},
+		{"", `''`},
+		{"it's", `'it''s'`},
+		{`'); DELETE FROM mysql_users; --`, `'''); DELETE FROM mysql_users; --'`},
+		// SQLite doesn't treat backslashes as escapes, so doubling them would change the value
+		{`C:\drupal\'`, `'C:\drupal\'''`},
+		{"wl`gore", "'wl`gore'"},
+		{"wl\x00gore", "'wl\x00gore'"},
+		{"wl\ngore", "'wl\ngore'"},
+	}
+	for _, c := range cases {
+		if got := QuoteProxySQLLiteral(c.value); got != c.want {
+			t.Errorf("QuoteProxySQLLiteral(%q) = %s, want %s", c.value, got, c.want)
+		}
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	cases := []struct {
+		name      string
+		maxLength int
+		valid     bool
+	}{
+		{"wlgore", MaxDatabaseNameLength, true},
+		{"wl_gore$2", MaxDatabaseNameLength, true},
+		{"2wlgore", MaxDatabaseNameLength, true},
+		{"", MaxDatabaseNameLength, false},
+		{"2019", MaxDatabaseNameLength, false},
+		{"wl'gore", MaxDatabaseNameLength, false},
+		{`wl\gore`, MaxDatabaseNameLength, false},
+		{"wl`gore", MaxDatabaseNameLength, false},
+		{"wl\x00gore", MaxDatabaseNameLength, false},
+		{"wl\ngore", MaxDatabaseNameLength, false},
+		{"wlgore\n", MaxDatabaseNameLength, false},
+		{"wl-gore", MaxDatabaseNameLength, false},
+		{"wl gore", MaxDatabaseNameLength, false},
+		{"wlgoré", MaxDatabaseNameLength, false},
+		{strings.Repeat("a", MaxUserNameLength), MaxUserNameLength, true},
+		{strings.Repeat("a", MaxUserNameLength+1), MaxUserNameLength, false},
+		{strings.Repeat("a", MaxDatabaseNameLength), MaxDatabaseNameLength, true},
+		{strings.Repeat("a", MaxDatabaseNameLength+1), MaxDatabaseNameLength, false},
+	}
+	for _, c := range cases {
+		err := ValidateIdentifier(c.name, c.maxLength)
+		if valid := err == nil; valid != c.valid {
+			t.Errorf("ValidateIdentifier(%q, %d) = %v, want valid: %t", c.name, c.maxLength, err, c.valid)
+		}
+	}
+}
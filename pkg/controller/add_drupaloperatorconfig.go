@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/acquia/fn-drupal-operator/pkg/controller/drupaloperatorconfig"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, drupaloperatorconfig.Add)
+}
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/acquia/fn-drupal-operator/pkg/controller/sitebackup"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, sitebackup.Add, sitebackup.AddRestore, sitebackup.AddClone)
+}
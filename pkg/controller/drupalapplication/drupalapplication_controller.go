@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
 )
 
 var log = logf.Log.WithName("controller_drupalapplication")
@@ -66,7 +67,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
-	return nil
+	// Reconcile every DrupalApplication when the operator configuration changes
+	return operatorconfig.Watch(c, mgr.GetClient(), &fnv1alpha1.DrupalApplicationList{})
 }
 
 // blank assignment to verify that ReconcileDrupalApplication implements reconcile.Reconciler
@@ -101,6 +103,10 @@ func (r *ReconcileDrupalApplication) Reconcile(request reconcile.Request) (recon
 
 	rh.logger.Info("Reconciling DrupalApplication", "Request", request)
 
+	if err := operatorconfig.Load(r.client); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Fetch the DrupalApplication instance
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: request.NamespacedName.Name}, rh.app)
 	if err != nil {
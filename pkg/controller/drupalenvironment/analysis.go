@@ -0,0 +1,308 @@
+package drupalenvironment
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+const (
+	drupalAnalysisTemplateName = "drupal-analysis"
+	drupalPreviewServiceName   = "drupal-preview"
+
+	// Labels and annotations Argo sets on the ReplicaSets of a Rollout
+	rolloutPodTemplateHashLabel = "rollouts-pod-template-hash"
+	rolloutRevisionAnnotation   = "rollout.argoproj.io/revision"
+
+	reasonAnalysisFailed    = "AnalysisFailed"
+	reasonDeployHooksFailed = "DeployHooksFailed"
+	reasonAborted           = "Aborted"
+)
+
+// httpCheckScript requests each "<host> <path>" line of $CHECKS from $TARGET with the Host header set, and fails on the
+// first response without the expected status
+const httpCheckScript = `echo "$CHECKS" | while read host path; do
+  [ -z "$host" ] && continue
+  status=$(curl --silent --output /dev/null --write-out '%{http_code}' --max-time "$TIMEOUT" --header "Host: $host" "$TARGET$path")
+  echo "$host$path: $status"
+  [ "$status" = "$EXPECTED_STATUS" ] || exit 1
+done`
+
+// usesPreviewService returns true if the Drupal Rollout brings up new pods behind the drupal-preview Service, which is
+// the case for blue/green Rollouts with analysis
+func usesPreviewService(drupal fnv1alpha1.SpecDrupal) bool {
+	blueGreen := drupal.RolloutStrategy == nil || drupal.RolloutStrategy.Canary == nil
+	return blueGreen && drupal.Analysis != nil
+}
+
+// rolloutAnalysis returns the analysis the Drupal Rollout runs, or nil if the environment has none
+func rolloutAnalysis(drupal fnv1alpha1.SpecDrupal) *rolloutsv1alpha1.RolloutAnalysis {
+	if drupal.Analysis == nil {
+		return nil
+	}
+	return &rolloutsv1alpha1.RolloutAnalysis{
+		Templates: []rolloutsv1alpha1.RolloutAnalysisTemplates{{TemplateName: drupalAnalysisTemplateName}},
+	}
+}
+
+// reconcileAnalysis creates or updates the AnalysisTemplate of spec.drupal.analysis and, for blue/green Rollouts, the
+// preview Service the analysis checks the new pods through. They are deleted when the environment has no analysis.
+func (rh *requestHandler) reconcileAnalysis() (requeue bool, err error) {
+	r := rh.reconciler
+	drupal := rh.env.Spec.Drupal
+
+	if usesPreviewService(drupal) {
+		svc := rh.drupalService(drupalPreviewServiceName)
+		err := r.client.Create(context.TODO(), svc)
+		if err == nil {
+			rh.logger.Info("Created Service", "Namespace", svc.Namespace, "Name", svc.Name)
+		} else if !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+	} else if err := rh.deleteIfExists(drupalPreviewServiceName, &v1.Service{}); err != nil {
+		return false, err
+	}
+
+	if drupal.Analysis == nil {
+		err := rh.deleteIfExists(drupalAnalysisTemplateName, &rolloutsv1alpha1.AnalysisTemplate{})
+		// Argo releases without analysis don't serve AnalysisTemplates
+		if err != nil && !meta.IsNoMatchError(err) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	metrics, err := rh.analysisMetrics(drupal.Analysis)
+	if err != nil {
+		return false, err
+	}
+	template := &rolloutsv1alpha1.AnalysisTemplate{ObjectMeta: metav1.ObjectMeta{Name: drupalAnalysisTemplateName, Namespace: rh.namespace}}
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, template, func(o runtime.Object) error {
+		template := o.(*rolloutsv1alpha1.AnalysisTemplate)
+		if template.CreationTimestamp.IsZero() {
+			template.Labels = rh.env.ChildLabels()
+			rh.associateResourceWithController(template)
+		}
+		template.Spec.Metrics = metrics
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if op != controllerutil.OperationResultNone {
+		rh.logger.Info("Reconciled Drupal AnalysisTemplate", "operation", op)
+		return true, nil
+	}
+	return false, nil
+}
+
+// deleteIfExists deletes the named object of the environment's namespace, if it exists
+func (rh *requestHandler) deleteIfExists(name string, obj runtime.Object) error {
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rh.namespace}, obj)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	rh.logger.Info("Deleting unused object", "Name", name, "Kind", fmt.Sprintf("%T", obj))
+	if err := rh.reconciler.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// analysisMetrics returns the metrics of the AnalysisTemplate of spec.drupal.analysis
+func (rh *requestHandler) analysisMetrics(analysis *fnv1alpha1.AnalysisSpec) ([]rolloutsv1alpha1.Metric, error) {
+	intervalSeconds := analysis.IntervalSeconds
+	if intervalSeconds == 0 {
+		intervalSeconds = 30
+	}
+	interval := rolloutsv1alpha1.DurationString(fmt.Sprintf("%ds", intervalSeconds))
+	count := analysis.Count
+	if count == 0 {
+		count = 5
+	}
+
+	var metrics []rolloutsv1alpha1.Metric
+	if analysis.HTTP != nil {
+		job, err := rh.httpCheckJob(analysis.HTTP)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, rolloutsv1alpha1.Metric{
+			Name:         "http",
+			Interval:     interval,
+			Count:        count,
+			FailureLimit: analysis.FailureLimit,
+			Provider: rolloutsv1alpha1.MetricProvider{
+				Job: &rolloutsv1alpha1.JobMetric{
+					Metadata: metav1.ObjectMeta{Labels: rh.env.ChildLabels()},
+					Spec:     job,
+				},
+			},
+		})
+	}
+	if errorRate := analysis.ErrorRate; errorRate != nil {
+		metrics = append(metrics, rolloutsv1alpha1.Metric{
+			Name:             "error-rate",
+			Interval:         interval,
+			Count:            count,
+			FailureLimit:     analysis.FailureLimit,
+			SuccessCondition: "result <= " + errorRate.MaxErrorRate,
+			Provider: rolloutsv1alpha1.MetricProvider{
+				Prometheus: &rolloutsv1alpha1.PrometheusMetric{
+					Address: errorRate.Address,
+					Query:   errorRate.Query,
+				},
+			},
+		})
+	}
+	return metrics, nil
+}
+
+// httpCheckJob returns the spec of the Job that checks the domains of each Site of the environment. Blue/green
+// Rollouts are checked through the preview Service, which only selects the new pods, and canary Rollouts through the
+// drupal Service, which reaches the new pods in proportion to the canary weight.
+func (rh *requestHandler) httpCheckJob(check *fnv1alpha1.HTTPAnalysis) (batchv1.JobSpec, error) {
+	checkPath := check.Path
+	if checkPath == "" {
+		checkPath = "/"
+	}
+	expectedStatus := check.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = 200
+	}
+	timeout := check.TimeoutSeconds
+	if timeout == 0 {
+		timeout = 10
+	}
+	target := drupalServiceName
+	if usesPreviewService(rh.env.Spec.Drupal) {
+		target = drupalPreviewServiceName
+	}
+
+	sites := &fnv1alpha1.SiteList{}
+	if err := rh.reconciler.client.List(context.TODO(), client.InNamespace(rh.namespace), sites); err != nil {
+		return batchv1.JobSpec{}, err
+	}
+	var checks []string
+	for _, site := range sites.Items {
+		if site.Spec.Environment != rh.env.Name || site.GetDeletionTimestamp() != nil {
+			continue
+		}
+		sitePath := checkPath
+		if paths := site.DrupalPaths(); len(paths) > 0 {
+			sitePath = path.Join(paths[0], checkPath)
+		}
+		for _, domain := range site.Spec.Domains {
+			checks = append(checks, domain+" "+sitePath)
+		}
+	}
+	// The order of the List isn't stable, and would update the AnalysisTemplate needlessly
+	sort.Strings(checks)
+
+	backoffLimit := int32(0)
+	return batchv1.JobSpec{
+		BackoffLimit: &backoffLimit,
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: rh.env.ChildLabels(),
+			},
+			Spec: v1.PodSpec{
+				RestartPolicy:    v1.RestartPolicyNever,
+				NodeSelector:     operatorconfig.Current().NodeSelector,
+				ImagePullSecrets: registry.Current().PullSecrets(),
+				Containers: []v1.Container{
+					{
+						// The image of the release has curl, and is already on the nodes running it
+						Name:    "http-check",
+						Image:   customercontainer.ImageName(rh.app, rh.env),
+						Command: []string{"/bin/sh", "-c", httpCheckScript},
+						Env: []v1.EnvVar{
+							{Name: "TARGET", Value: "http://" + target},
+							{Name: "CHECKS", Value: strings.Join(checks, "\n")},
+							{Name: "EXPECTED_STATUS", Value: fmt.Sprint(expectedStatus)},
+							{Name: "TIMEOUT", Value: fmt.Sprint(timeout)},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// observeRollback records the release of an aborted Rollout, and the release that kept serving in its place, from the
+// ReplicaSets of the Rollout
+func (rh *requestHandler) observeRollback(rollout *rolloutsv1alpha1.Rollout, status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	rs := rollout.Status
+	if !rs.Abort {
+		return nil
+	}
+
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := rh.reconciler.client.List(context.TODO(), client.InNamespace(rh.namespace).MatchingLabels(labelsForDeployment(rh.env)), replicaSets); err != nil {
+		return err
+	}
+	revision := func(hash string) (revision, tag string) {
+		for _, replicaSet := range replicaSets.Items {
+			if hash == "" || replicaSet.Labels[rolloutPodTemplateHashLabel] != hash {
+				continue
+			}
+			for _, c := range replicaSet.Spec.Template.Spec.InitContainers {
+				if c.Name == codeCopyContainerName {
+					tag = imageTag(c.Image)
+				}
+			}
+			return replicaSet.Annotations[rolloutRevisionAnnotation], tag
+		}
+		return "", ""
+	}
+
+	abortedRevision, abortedTag := revision(rs.CurrentPodHash)
+	if previous := status.Drupal.Rollback; previous != nil && previous.AbortedRevision == abortedRevision {
+		return nil
+	}
+	stableHash := rs.BlueGreen.ActiveSelector
+	if rollout.Spec.Strategy.Canary != nil {
+		stableHash = rs.Canary.StableRS
+	}
+	stableRevision, stableTag := revision(stableHash)
+
+	reason := reasonAborted
+	switch {
+	case rh.deployHooks != nil && rh.deployHooks.Phase == fnv1alpha1.DeployHooksFailed:
+		reason = reasonDeployHooksFailed
+	case rh.env.Spec.Drupal.Analysis != nil:
+		reason = reasonAnalysisFailed
+	}
+	rh.logger.Info("Drupal Rollout aborted", "Reason", reason, "Revision", abortedRevision, "Tag", abortedTag,
+		"StableRevision", stableRevision, "StableTag", stableTag)
+	status.Drupal.Rollback = &fnv1alpha1.RollbackStatus{
+		Reason:          reason,
+		AbortedRevision: abortedRevision,
+		AbortedTag:      abortedTag,
+		StableRevision:  stableRevision,
+		StableTag:       stableTag,
+		Time:            metav1.Now(),
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package drupalenvironment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// reconcileDeployHooks starts the deploy hooks of the environment's Sites when spec.drupal.tag or spec.gitRef changes,
+// and follows their outcome. Each Site runs its hook through the Site controller, which records it in the Site's status.
+// The release the environment runs when it is first reconciled needs no hooks.
+func (rh *requestHandler) reconcileDeployHooks() error {
+	release := rh.env.Release()
+	if rh.deployHooks == nil || rh.deployHooks.Release != release {
+		phase := fnv1alpha1.DeployHooksRunning
+		if rh.deployHooks == nil {
+			phase = fnv1alpha1.DeployHooksSucceeded
+		} else {
+			rh.logger.Info("Running deploy hooks for new release", "Release", release, "Tag", rh.env.Spec.Drupal.Tag,
+				"GitRef", rh.env.Spec.GitRef)
+		}
+		rh.deployHooks = &fnv1alpha1.DeployHooksStatus{
+			Release: release,
+			Tag:     rh.env.Spec.Drupal.Tag,
+			GitRef:  rh.env.Spec.GitRef,
+			Phase:   phase,
+		}
+	}
+	hooks := rh.deployHooks
+	if hooks.Phase != fnv1alpha1.DeployHooksRunning {
+		return nil
+	}
+
+	sites := &fnv1alpha1.SiteList{}
+	if err := rh.reconciler.client.List(context.TODO(), client.InNamespace(rh.namespace), sites); err != nil {
+		return err
+	}
+	hooks.Sites, hooks.SucceededSites, hooks.FailedSites = 0, 0, nil
+	for _, site := range sites.Items {
+		if site.Spec.Environment != rh.env.Name || site.GetDeletionTimestamp() != nil {
+			continue
+		}
+		hooks.Sites++
+		hook := site.Status.DeployHook
+		if hook == nil || hook.Release != release {
+			continue
+		}
+		switch hook.Outcome {
+		case fnv1alpha1.JobSucceeded:
+			hooks.SucceededSites++
+		case fnv1alpha1.JobFailed:
+			hooks.FailedSites = append(hooks.FailedSites, site.Name)
+		}
+	}
+	sort.Strings(hooks.FailedSites)
+
+	switch {
+	case len(hooks.FailedSites) > 0:
+		rh.logger.Info("Deploy hooks failed", "Release", release, "Sites", hooks.FailedSites)
+		hooks.Phase = fnv1alpha1.DeployHooksFailed
+	case hooks.SucceededSites == hooks.Sites:
+		rh.logger.Info("Deploy hooks succeeded", "Release", release, "Sites", hooks.Sites)
+		hooks.Phase = fnv1alpha1.DeployHooksSucceeded
+	}
+	return nil
+}
+
+// holdPromotion returns true while the deploy hooks of the release being rolled out haven't all succeeded, during which
+// a blue/green Rollout isn't promoted automatically
+func (rh *requestHandler) holdPromotion() bool {
+	return rh.deployHooks != nil && rh.deployHooks.Phase != fnv1alpha1.DeployHooksSucceeded
+}
+
+// reconcileRolloutPromotion aborts the update of the Drupal Rollout when a deploy hook failed, so that the previous
+// release keeps serving. A blue/green Rollout held for the hooks needs no promoting once they have succeeded: its
+// strategy enables automatic promotion again, and Argo lifts the pause.
+func (rh *requestHandler) reconcileRolloutPromotion() (requeue bool, err error) {
+	if rh.deployHooks == nil || rh.deployHooks.Phase != fnv1alpha1.DeployHooksFailed {
+		return false, nil
+	}
+
+	rollout := &rolloutsv1alpha1.Rollout{}
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: drupalRolloutName, Namespace: rh.namespace}, rollout)
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if rollout.Status.Abort || !rolloutInProgress(rollout) {
+		return false, nil
+	}
+
+	rh.logger.Info("Aborting Drupal Rollout after failed deploy hooks", "Release", rh.deployHooks.Release)
+	rollout.Status.Abort = true
+	// The Rollout CRD has no status subresource, so status.abort is set like "kubectl argo rollouts abort" does
+	if err := rh.reconciler.client.Update(context.TODO(), rollout); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rolloutInProgress returns true while the Rollout is bringing up pods for a new pod template, or hasn't yet switched
+// traffic to them
+func rolloutInProgress(rollout *rolloutsv1alpha1.Rollout) bool {
+	rs := rollout.Status
+	if rs.UpdatedReplicas < rs.Replicas {
+		return true
+	}
+	if rollout.Spec.Strategy.Canary != nil {
+		return rs.Canary.StableRS != rs.CurrentPodHash
+	}
+	return rs.BlueGreen.ActiveSelector != rs.CurrentPodHash
+}
+
+func (rh *requestHandler) observeDeployHooks(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	status.DeployHooks = rh.deployHooks.DeepCopy()
+	if status.DeployHooks == nil {
+		return nil
+	}
+
+	switch hooks := status.DeployHooks; hooks.Phase {
+	case fnv1alpha1.DeployHooksSucceeded:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionDeployHooksSucceeded, v1.ConditionTrue, "HooksSucceeded", "")
+	case fnv1alpha1.DeployHooksFailed:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionDeployHooksSucceeded, v1.ConditionFalse, "HooksFailed",
+			"Deploy hooks failed for Sites: "+strings.Join(hooks.FailedSites, ", "))
+	default:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionDeployHooksSucceeded, v1.ConditionFalse, "HooksRunning",
+			fmt.Sprintf("%d/%d Sites have run their deploy hook", hooks.SucceededSites, hooks.Sites))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package drupalenvironment
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// newDeployHooksTestEnvironment returns a DrupalEnvironment whose spec.drupal.tag has changed since its deploy hooks
+// were last run
+func newDeployHooksTestEnvironment() *fnv1alpha1.DrupalEnvironment {
+	return &fnv1alpha1.DrupalEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+		Spec: fnv1alpha1.DrupalEnvironmentSpec{
+			Application: "wlgore-app",
+			Drupal:      fnv1alpha1.SpecDrupal{Tag: "v2"},
+		},
+	}
+}
+
+// newDeployHooksTestSite returns a Site of the environment, which has run its deploy hook for the given release with
+// the given outcome. An empty release means it hasn't run one yet.
+func newDeployHooksTestSite(name, environment, release string, outcome fnv1alpha1.JobOutcome) *fnv1alpha1.Site {
+	site := &fnv1alpha1.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       fnv1alpha1.SiteSpec{Environment: environment},
+	}
+	if release != "" {
+		site.Status.DeployHook = &fnv1alpha1.SiteDeployHookStatus{Release: release, JobName: name + "-deploy-hook", Outcome: outcome}
+	}
+	return site
+}
+
+func TestReconcileDeployHooks(t *testing.T) {
+	env := newDeployHooksTestEnvironment()
+	release := env.Release()
+
+	cases := []struct {
+		name           string
+		sites          []runtime.Object
+		phase          fnv1alpha1.DeployHooksPhase
+		sitesCount     int32
+		succeededSites int32
+		failedSites    []string
+	}{
+		{
+			name:  "no Sites",
+			phase: fnv1alpha1.DeployHooksSucceeded,
+		},
+		{
+			name: "no Sites of the environment",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("other", "stage", "", ""),
+			},
+			phase: fnv1alpha1.DeployHooksSucceeded,
+		},
+		{
+			name: "hooks not started",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("wlgore", "prod", "", ""),
+			},
+			phase:      fnv1alpha1.DeployHooksRunning,
+			sitesCount: 1,
+		},
+		{
+			name: "hook of the previous release",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("wlgore", "prod", "0badf00d", fnv1alpha1.JobSucceeded),
+			},
+			phase:      fnv1alpha1.DeployHooksRunning,
+			sitesCount: 1,
+		},
+		{
+			name: "some hooks running",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("wlgore", "prod", release, fnv1alpha1.JobSucceeded),
+				newDeployHooksTestSite("gore-tex", "prod", release, fnv1alpha1.JobRunning),
+			},
+			phase:          fnv1alpha1.DeployHooksRunning,
+			sitesCount:     2,
+			succeededSites: 1,
+		},
+		{
+			name: "all hooks succeeded",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("wlgore", "prod", release, fnv1alpha1.JobSucceeded),
+				newDeployHooksTestSite("gore-tex", "prod", release, fnv1alpha1.JobSucceeded),
+				newDeployHooksTestSite("other", "stage", "", ""),
+			},
+			phase:          fnv1alpha1.DeployHooksSucceeded,
+			sitesCount:     2,
+			succeededSites: 2,
+		},
+		{
+			name: "hooks failed",
+			sites: []runtime.Object{
+				newDeployHooksTestSite("wlgore", "prod", release, fnv1alpha1.JobFailed),
+				newDeployHooksTestSite("gore-tex", "prod", release, fnv1alpha1.JobRunning),
+				newDeployHooksTestSite("acme", "prod", release, fnv1alpha1.JobFailed),
+			},
+			phase:       fnv1alpha1.DeployHooksFailed,
+			sitesCount:  3,
+			failedSites: []string{"acme", "wlgore"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			env := newDeployHooksTestEnvironment()
+			rh := &requestHandler{
+				reconciler: newTestReconciler(t, env, c.sites...),
+				env:        env,
+				namespace:  testNamespace,
+				logger:     log,
+				// The hooks of the previous release had succeeded
+				deployHooks: &fnv1alpha1.DeployHooksStatus{Release: "0badf00d", Tag: "v1", Phase: fnv1alpha1.DeployHooksSucceeded},
+			}
+
+			if err := rh.reconcileDeployHooks(); err != nil {
+				t.Fatal(err)
+			}
+			hooks := rh.deployHooks
+			if hooks.Release != release || hooks.Tag != "v2" {
+				t.Errorf("deploy hooks are for release %s of tag %s, want %s of v2", hooks.Release, hooks.Tag, release)
+			}
+			if hooks.Phase != c.phase {
+				t.Errorf("phase is %s, want %s", hooks.Phase, c.phase)
+			}
+			if hooks.Sites != c.sitesCount || hooks.SucceededSites != c.succeededSites {
+				t.Errorf("%d/%d Sites succeeded, want %d/%d", hooks.SucceededSites, hooks.Sites, c.succeededSites, c.sitesCount)
+			}
+			if len(hooks.FailedSites) != len(c.failedSites) {
+				t.Fatalf("failed Sites are %v, want %v", hooks.FailedSites, c.failedSites)
+			}
+			for i := range c.failedSites {
+				if hooks.FailedSites[i] != c.failedSites[i] {
+					t.Errorf("failed Sites are %v, want %v", hooks.FailedSites, c.failedSites)
+				}
+			}
+			if rh.holdPromotion() != (c.phase != fnv1alpha1.DeployHooksSucceeded) {
+				t.Errorf("holdPromotion is %t in phase %s", rh.holdPromotion(), hooks.Phase)
+			}
+		})
+	}
+}
+
+func TestReconcileDeployHooksPhaseTransitions(t *testing.T) {
+	env := newDeployHooksTestEnvironment()
+	env.Spec.Drupal.Tag = "v1"
+	site := newDeployHooksTestSite("wlgore", "prod", "", "")
+	r := newTestReconciler(t, env, site)
+	rh := &requestHandler{reconciler: r, env: env, namespace: testNamespace, logger: log}
+
+	// The release the environment runs when it is first reconciled needs no hooks
+	if err := rh.reconcileDeployHooks(); err != nil {
+		t.Fatal(err)
+	}
+	if rh.deployHooks.Phase != fnv1alpha1.DeployHooksSucceeded {
+		t.Fatalf("phase of the first release is %s, want %s", rh.deployHooks.Phase, fnv1alpha1.DeployHooksSucceeded)
+	}
+
+	steps := []struct {
+		tag     string
+		outcome fnv1alpha1.JobOutcome
+		phase   fnv1alpha1.DeployHooksPhase
+	}{
+		{"v2", "", fnv1alpha1.DeployHooksRunning},
+		{"v2", fnv1alpha1.JobRunning, fnv1alpha1.DeployHooksRunning},
+		{"v2", fnv1alpha1.JobSucceeded, fnv1alpha1.DeployHooksSucceeded},
+		{"v3", "", fnv1alpha1.DeployHooksRunning},
+		{"v3", fnv1alpha1.JobFailed, fnv1alpha1.DeployHooksFailed},
+		// A failed release stays failed until the next one, even if the hook is rerun
+		{"v3", fnv1alpha1.JobSucceeded, fnv1alpha1.DeployHooksFailed},
+		{"v4", "", fnv1alpha1.DeployHooksRunning},
+	}
+	for _, step := range steps {
+		env.Spec.Drupal.Tag = step.tag
+		site.Status.DeployHook = nil
+		if step.outcome != "" {
+			site.Status.DeployHook = &fnv1alpha1.SiteDeployHookStatus{Release: env.Release(), Outcome: step.outcome}
+		}
+		if err := r.client.Status().Update(context.TODO(), site); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := rh.reconcileDeployHooks(); err != nil {
+			t.Fatal(err)
+		}
+		if rh.deployHooks.Phase != step.phase {
+			t.Errorf("phase of %s with a %q hook is %s, want %s", step.tag, step.outcome, rh.deployHooks.Phase, step.phase)
+		}
+
+		status := &fnv1alpha1.DrupalEnvironmentStatus{}
+		if err := rh.observeDeployHooks(status); err != nil {
+			t.Fatal(err)
+		}
+		if succeeded := fnv1alpha1.IsConditionTrue(status.Conditions, fnv1alpha1.ConditionDeployHooksSucceeded); succeeded != (step.phase == fnv1alpha1.DeployHooksSucceeded) {
+			t.Errorf("DeployHooksSucceeded condition of %s is %t in phase %s", step.tag, succeeded, step.phase)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package drupalenvironment
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+// reconcileImageDigests resolves the Drupal, Apache and PHP-FPM images of the release to digests when the release
+// changes, and requeues so that the Rollout is built from them once they are recorded in status.images. The tags
+// aren't resolved again until they change, so that pushing a tag again doesn't change what the release runs. When a
+// tag can't be resolved, for example because the registry needs credentials the operator doesn't have, the release is
+// deployed by tag and the failure is reported in the ImagesResolved condition, rather than holding up the rest of the
+// environment.
+func (rh *requestHandler) reconcileImageDigests() (requeue bool, err error) {
+	config := registry.Current()
+	if !config.ResolveDigests {
+		rh.images = nil
+		return false, nil
+	}
+
+	desired := fnv1alpha1.ResolvedImages{
+		Drupal: fnv1alpha1.ResolvedImage{Image: customercontainer.TagImageName(rh.app, rh.env)},
+		Apache: fnv1alpha1.ResolvedImage{Image: apacheTagImage(rh.env)},
+		PhpFpm: fnv1alpha1.ResolvedImage{Image: phpFpmTagImage(rh.env)},
+	}
+	rollback, err := rh.rollbackRelease()
+	if err != nil {
+		return false, err
+	}
+	if rollback != nil {
+		// A rollback runs the images of its release, with the digests its pods pulled
+		for _, image := range rollback.Images {
+			ref, digest := splitDigest(image.Image)
+			if digest == "" {
+				digest = image.Digest
+			}
+			switch image.Container {
+			case codeCopyContainerName:
+				desired.Drupal = fnv1alpha1.ResolvedImage{Image: ref, Digest: digest}
+			case "apache":
+				desired.Apache = fnv1alpha1.ResolvedImage{Image: ref, Digest: digest}
+			case "php-fpm":
+				desired.PhpFpm = fnv1alpha1.ResolvedImage{Image: ref, Digest: digest}
+			}
+		}
+	}
+
+	var current fnv1alpha1.ResolvedImages
+	if rh.images != nil {
+		current = *rh.images
+	}
+	var resolver *registry.Resolver
+	for _, image := range []struct{ desired, current *fnv1alpha1.ResolvedImage }{
+		{&desired.Drupal, &current.Drupal},
+		{&desired.Apache, &current.Apache},
+		{&desired.PhpFpm, &current.PhpFpm},
+	} {
+		if image.desired.Digest != "" {
+			continue
+		}
+		if image.current.Image == image.desired.Image && image.current.Digest != "" {
+			image.desired.Digest = image.current.Digest
+			continue
+		}
+		if resolver == nil {
+			credentials, err := rh.pullSecretCredentials()
+			if err != nil {
+				return false, err
+			}
+			resolver = config.Resolver(credentials)
+		}
+		digest, err := resolver.Digest(context.TODO(), image.desired.Image)
+		if err != nil {
+			rh.logger.Error(err, "Failed to resolve image digest, deploying by tag", "Image", image.desired.Image)
+			rh.imagesErr = err
+			rh.images = nil
+			return false, nil
+		}
+		image.desired.Digest = digest
+	}
+
+	if rh.images != nil && *rh.images == desired {
+		return false, nil
+	}
+	rh.logger.Info("Resolved image digests", "Drupal", desired.Drupal.Pinned(), "Apache", desired.Apache.Pinned(),
+		"PhpFpm", desired.PhpFpm.Pinned())
+	rh.images = &desired
+	return true, nil
+}
+
+// pullSecretCredentials returns the registry credentials of the image pull Secrets in the environment's namespace
+func (rh *requestHandler) pullSecretCredentials() (registry.Credentials, error) {
+	var secrets []v1.Secret
+	for _, name := range registry.Current().ImagePullSecrets {
+		secret := v1.Secret{}
+		err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rh.namespace}, &secret)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return registry.SecretCredentials(secrets)
+}
+
+func (rh *requestHandler) observeImages(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	status.Images = rh.images.DeepCopy()
+	switch {
+	case rh.imagesErr != nil:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionImagesResolved, v1.ConditionFalse, "ResolveFailed",
+			rh.imagesErr.Error())
+	case status.Images != nil:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionImagesResolved, v1.ConditionTrue, "DigestsResolved", "")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package drupalenvironment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+// reconcileRepeatedly reconciles the DrupalEnvironment a few times, as the controller's watches would. Its requests
+// end early, once ProxySQL can't be configured, as no ProxySQL is running.
+func reconcileRepeatedly(r *ReconcileDrupalEnvironment, name string) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}}
+	for i := 0; i < 10; i++ {
+		r.Reconcile(request)
+	}
+}
+
+func TestReconcileDeploysByTagWhenDigestsCantBeResolved(t *testing.T) {
+	// The registry requires credentials, and the operator has none
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	previous := registry.Current()
+	defer registry.Set(previous)
+	config := registry.Default()
+	config.CustomerImageRoot = host + "/customer/"
+	config.ApacheImage = host + "/apache/default"
+	config.PhpFpmImage = host + "/php-fpm/default"
+	config.ResolveDigests = true
+	registry.Set(config)
+
+	os.Setenv("USE_DYNAMIC_PROVISIONING", "true")
+	defer os.Unsetenv("USE_DYNAMIC_PROVISIONING")
+
+	env := &fnv1alpha1.DrupalEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+		Spec: fnv1alpha1.DrupalEnvironmentSpec{
+			Application: "wlgore-app",
+			Drupal:      fnv1alpha1.SpecDrupal{Tag: "v1"},
+			Apache:      fnv1alpha1.SpecApache{Tag: "latest"},
+			Phpfpm:      fnv1alpha1.SpecPhpFpm{Tag: "latest"},
+		},
+	}
+	env.SetId("7b2d37a4-4a0e-4f5e-8a4e-2f0b4ab3b3f1")
+	fnv1alpha1.SetObjectDefaults_DrupalEnvironment(env)
+	r := newTestReconciler(t, env)
+
+	reconcileRepeatedly(r, env.Name)
+
+	rollout := &rolloutsv1alpha1.Rollout{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: drupalRolloutName}, rollout); err != nil {
+		t.Fatalf("the Drupal Rollout wasn't created: %v", err)
+	}
+	for _, c := range append(rollout.Spec.Template.Spec.InitContainers, rollout.Spec.Template.Spec.Containers...) {
+		if strings.Contains(c.Image, "@") {
+			t.Errorf("container %s runs %s, want the image by tag", c.Name, c.Image)
+		}
+	}
+
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: env.Name}, env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Status.Images != nil {
+		t.Errorf("status.images is %+v, want none", env.Status.Images)
+	}
+	resolved := fnv1alpha1.GetCondition(env.Status.Conditions, fnv1alpha1.ConditionImagesResolved)
+	if resolved == nil || resolved.Status != corev1.ConditionFalse || !strings.Contains(resolved.Message, "no credentials") {
+		t.Errorf("ImagesResolved condition is %+v, want False for the missing credentials", resolved)
+	}
+}
@@ -16,6 +16,8 @@ import (
 
 	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
 )
 
 const (
@@ -24,6 +26,11 @@ const (
 
 	drupalRolloutName = "drupal"
 	drupalServiceName = "drupal"
+
+	codeCopyContainerName = "code-copy"
+
+	// gitRefAnnotation on the Drupal pod template records the git ref the code was built from
+	gitRefAnnotation = fnv1alpha1.LabelPrefix + "git-ref"
 )
 
 func drupalCodeMount(path string) v1.VolumeMount {
@@ -55,12 +62,25 @@ func PhpConfigVolume() v1.Volume {
 	}
 }
 
+func apacheTagImage(env *fnv1alpha1.DrupalEnvironment) string {
+	return registry.Current().ApacheImage + ":" + env.Spec.Apache.Tag
+}
+
+func phpFpmTagImage(env *fnv1alpha1.DrupalEnvironment) string {
+	return registry.Current().PhpFpmImage + ":" + env.Spec.Phpfpm.Tag
+}
+
 func apacheContainer(env *fnv1alpha1.DrupalEnvironment) v1.Container {
 	drupal := env.Spec.Drupal
 
+	image := apacheTagImage(env)
+	if env.Status.Images != nil {
+		image = customercontainer.PinnedImage(image, env.Spec.RollbackTo != nil, env.Status.Images.Apache)
+	}
+
 	apacheContainer := v1.Container{
 		Name:            "apache",
-		Image:           "881217801864.dkr.ecr.us-east-1.amazonaws.com/apache/default:" + env.Spec.Apache.Tag,
+		Image:           image,
 		ImagePullPolicy: drupal.PullPolicy,
 		Ports: []v1.ContainerPort{{
 			ContainerPort: 8080,
@@ -130,7 +150,10 @@ func (rh *requestHandler) phpFpmContainer() v1.Container {
 
 	phpFpmContainer := customercontainer.Template(rh.app, rh.env)
 	phpFpmContainer.Name = "php-fpm"
-	phpFpmContainer.Image = "881217801864.dkr.ecr.us-east-1.amazonaws.com/php-fpm/default:" + phpfpm.Tag
+	phpFpmContainer.Image = phpFpmTagImage(rh.env)
+	if images := rh.env.Status.Images; images != nil {
+		phpFpmContainer.Image = customercontainer.PinnedImage(phpFpmContainer.Image, rh.env.Spec.RollbackTo != nil, images.PhpFpm)
+	}
 	phpFpmContainer.Resources = v1.ResourceRequirements{
 		Requests: v1.ResourceList{
 			v1.ResourceCPU:    phpfpm.Cpu.Request,
@@ -156,11 +179,8 @@ func (rh *requestHandler) phpFpmContainer() v1.Container {
 func (rh *requestHandler) drupalRolloutSpec() rolloutsv1alpha1.RolloutSpec {
 	ls := labelsForDeployment(rh.env)
 	rootUser := int64(0)
-	rolloutAutoPromote := true // TODO: may not want to auto-promote in a multisite configuration
-	rolloutAutoPromoteDelay := int32(10)
 	// userReadOnly := int32(0400)
 	twoReplicas := int32(2)
-	scaleDownDelay := int32(30) // see https://github.com/argoproj/argo-rollouts/issues/19#issuecomment-476329960
 
 	drupal := rh.env.Spec.Drupal
 
@@ -169,7 +189,7 @@ func (rh *requestHandler) drupalRolloutSpec() rolloutsv1alpha1.RolloutSpec {
 	}
 
 	codeCopyContainer := v1.Container{
-		Name:            "code-copy",
+		Name:            codeCopyContainerName,
 		Image:           customercontainer.ImageName(rh.app, rh.env),
 		ImagePullPolicy: drupal.PullPolicy,
 		Command: []string{
@@ -227,18 +247,14 @@ func (rh *requestHandler) drupalRolloutSpec() rolloutsv1alpha1.RolloutSpec {
 		Selector: &metav1.LabelSelector{
 			MatchLabels: ls,
 		},
-		Strategy: rolloutsv1alpha1.RolloutStrategy{
-			BlueGreenStrategy: &rolloutsv1alpha1.BlueGreenStrategy{
-				ActiveService:         drupalServiceName,
-				AutoPromotionEnabled:  &rolloutAutoPromote,
-				AutoPromotionSeconds:  &rolloutAutoPromoteDelay,
-				ScaleDownDelaySeconds: &scaleDownDelay,
-			},
-		},
+		Strategy: drupalRolloutStrategy(drupal, rh.holdPromotion()),
 		Replicas: &twoReplicas, // This field will actually be controlled by the HPA; this is just an initial value
 		Template: v1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: ls,
+				Annotations: map[string]string{
+					gitRefAnnotation: rh.env.Spec.GitRef,
+				},
 			},
 			Spec: v1.PodSpec{
 				InitContainers: []v1.Container{
@@ -249,9 +265,8 @@ func (rh *requestHandler) drupalRolloutSpec() rolloutsv1alpha1.RolloutSpec {
 					apacheContainer,
 					phpFpmContainer,
 				},
-				NodeSelector: map[string]string{
-					"function": "workers",
-				},
+				NodeSelector:     operatorconfig.Current().NodeSelector,
+				ImagePullSecrets: registry.Current().PullSecrets(),
 				Volumes: []v1.Volume{
 					customercontainer.FilesVolume(rh.env),
 					{
@@ -271,6 +286,69 @@ func (rh *requestHandler) drupalRolloutSpec() rolloutsv1alpha1.RolloutSpec {
 	return spec
 }
 
+// drupalRolloutStrategy returns the Argo strategy of the Drupal Rollout for spec.drupal.rolloutStrategy. Without one,
+// the Rollout is blue/green and promoted automatically. holdPromotion keeps a blue/green Rollout from being promoted
+// automatically, until the deploy hooks of the release have succeeded. The analysis of spec.drupal.analysis runs in
+// the background of a canary Rollout, and before a blue/green Rollout is promoted.
+func drupalRolloutStrategy(drupal fnv1alpha1.SpecDrupal, holdPromotion bool) rolloutsv1alpha1.RolloutStrategy {
+	strategy := drupal.RolloutStrategy
+	if strategy != nil && strategy.Canary != nil {
+		canary := strategy.Canary
+		steps := make([]rolloutsv1alpha1.CanaryStep, 0, len(canary.Steps))
+		for _, step := range canary.Steps {
+			s := rolloutsv1alpha1.CanaryStep{SetWeight: step.SetWeight}
+			if step.Pause != nil {
+				s.Pause = &rolloutsv1alpha1.RolloutPause{}
+				if step.Pause.DurationSeconds != nil {
+					s.Pause.Duration = rolloutsv1alpha1.DurationFromInt(int(*step.Pause.DurationSeconds))
+				}
+			}
+			steps = append(steps, s)
+		}
+		rolloutStrategy := rolloutsv1alpha1.RolloutStrategy{
+			Canary: &rolloutsv1alpha1.CanaryStrategy{
+				Steps:          steps,
+				MaxSurge:       canary.MaxSurge,
+				MaxUnavailable: canary.MaxUnavailable,
+			},
+		}
+		if analysis := rolloutAnalysis(drupal); analysis != nil {
+			rolloutStrategy.Canary.Analysis = &rolloutsv1alpha1.RolloutAnalysisBackground{RolloutAnalysis: *analysis}
+		}
+		return rolloutStrategy
+	}
+
+	autoPromote := true
+	autoPromoteDelay := int32(10)
+	scaleDownDelay := int32(30) // see https://github.com/argoproj/argo-rollouts/issues/19#issuecomment-476329960
+	if strategy != nil && strategy.BlueGreen != nil {
+		blueGreen := strategy.BlueGreen
+		if blueGreen.AutoPromotion != nil {
+			autoPromote = *blueGreen.AutoPromotion
+		}
+		if blueGreen.AutoPromotionSeconds != nil {
+			autoPromoteDelay = *blueGreen.AutoPromotionSeconds
+		}
+		if blueGreen.ScaleDownDelaySeconds != nil {
+			scaleDownDelay = *blueGreen.ScaleDownDelaySeconds
+		}
+	}
+	autoPromote = autoPromote && !holdPromotion
+	blueGreen := &rolloutsv1alpha1.BlueGreenStrategy{
+		ActiveService:         drupalServiceName,
+		AutoPromotionEnabled:  &autoPromote,
+		ScaleDownDelaySeconds: &scaleDownDelay,
+	}
+	if autoPromote {
+		blueGreen.AutoPromotionSeconds = &autoPromoteDelay
+	}
+	if usesPreviewService(drupal) {
+		blueGreen.PreviewService = drupalPreviewServiceName
+		blueGreen.PrePromotionAnalysis = rolloutAnalysis(drupal)
+	}
+	return rolloutsv1alpha1.RolloutStrategy{BlueGreen: blueGreen}
+}
+
 // labelsForDeployment returns the labels for selecting the resources
 // belonging to the given DrupalEnvironment CR name.
 func labelsForDeployment(drupalEnv *fnv1alpha1.DrupalEnvironment) map[string]string {
@@ -310,7 +388,7 @@ func (rh *requestHandler) pv(name string) *v1.PersistentVolume {
 			Labels:    rh.env.ChildLabels(),
 		},
 		Spec: v1.PersistentVolumeSpec{
-			StorageClassName: "efs",
+			StorageClassName: operatorconfig.Current().FilesStorageClass,
 			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
 			Capacity: v1.ResourceList{
 				v1.ResourceStorage: resource.MustParse("128Mi"),
@@ -327,7 +405,7 @@ func (rh *requestHandler) pv(name string) *v1.PersistentVolume {
 }
 
 func (rh *requestHandler) pvc(name string) *v1.PersistentVolumeClaim {
-	efs := "efs"
+	storageClass := operatorconfig.Current().FilesStorageClass
 
 	pvc := &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -336,7 +414,7 @@ func (rh *requestHandler) pvc(name string) *v1.PersistentVolumeClaim {
 			Labels:    rh.env.ChildLabels(),
 		},
 		Spec: v1.PersistentVolumeClaimSpec{
-			StorageClassName: &efs,
+			StorageClassName: &storageClass,
 			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
 			Resources: v1.ResourceRequirements{
 				Requests: v1.ResourceList{
@@ -366,12 +444,19 @@ func (rh *requestHandler) reconcileDrupalRollout() (requeue bool, err error) {
 		},
 	}
 
+	spec := rh.drupalRolloutSpec()
+	rollback, err := rh.rollbackRelease()
+	if err != nil {
+		return false, err
+	}
+	if rollback != nil {
+		rollbackTemplate(&spec.Template, rollback)
+	}
+
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, rollout, func(o runtime.Object) error {
 		rollout := o.(*rolloutsv1alpha1.Rollout)
 		rollout.ObjectMeta.Labels = rh.env.ChildLabels()
 
-		spec := rh.drupalRolloutSpec()
-
 		if rollout.ObjectMeta.CreationTimestamp.IsZero() {
 			// Create
 			spec.DeepCopyInto(&rollout.Spec)
@@ -385,6 +470,9 @@ func (rh *requestHandler) reconcileDrupalRollout() (requeue bool, err error) {
 	if err != nil {
 		return false, err
 	}
+	if err := rh.recordRelease(&rollout.Spec.Template, rollback); err != nil {
+		return false, err
+	}
 	if op != controllerutil.OperationResultNone {
 		rh.logger.Info("Reconciled Drupal Rollout", "operation", op)
 		return true, nil
@@ -397,7 +485,14 @@ func (rh *requestHandler) reconcileDrupalRollout() (requeue bool, err error) {
 // as many fields on a Rollout can't be changed after creation, and some fields have private members, which
 // precludes easy use of the "cmp" library for comparison.
 func syncDrupalRollout(rollout *rolloutsv1alpha1.Rollout, spec rolloutsv1alpha1.RolloutSpec) {
+	// The strategy is replaced as a whole, so that the Rollout can switch between blue/green and canary in place
 	spec.Strategy.DeepCopyInto(&rollout.Spec.Strategy)
+	if rollout.Spec.Template.Annotations == nil {
+		rollout.Spec.Template.Annotations = map[string]string{}
+	}
+	rollout.Spec.Template.Annotations[gitRefAnnotation] = spec.Template.Annotations[gitRefAnnotation]
+	rollout.Spec.Template.Spec.ImagePullSecrets = spec.Template.Spec.ImagePullSecrets
+	rollout.Spec.Template.Spec.NodeSelector = spec.Template.Spec.NodeSelector
 
 	// Iterate through the Init Containers in the rollout and new spec, matching by name,
 	// in case their order differs
@@ -0,0 +1,161 @@
+package drupalenvironment
+
+import (
+	"reflect"
+	"testing"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestDrupalRolloutStrategy(t *testing.T) {
+	analysis := &fnv1alpha1.AnalysisSpec{HTTP: &fnv1alpha1.HTTPAnalysis{}}
+	rolloutAnalysis := rolloutsv1alpha1.RolloutAnalysis{
+		Templates: []rolloutsv1alpha1.RolloutAnalysisTemplates{{TemplateName: drupalAnalysisTemplateName}},
+	}
+	maxSurge := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(0)
+
+	cases := []struct {
+		name          string
+		drupal        fnv1alpha1.SpecDrupal
+		holdPromotion bool
+		want          rolloutsv1alpha1.RolloutStrategy
+	}{
+		{
+			name: "default",
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				AutoPromotionEnabled:  boolPtr(true),
+				AutoPromotionSeconds:  int32Ptr(10),
+				ScaleDownDelaySeconds: int32Ptr(30),
+			}},
+		},
+		{
+			// Promotion waits for the deploy hooks of the release
+			name:          "default holding promotion",
+			holdPromotion: true,
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				AutoPromotionEnabled:  boolPtr(false),
+				ScaleDownDelaySeconds: int32Ptr(30),
+			}},
+		},
+		{
+			name: "blue/green",
+			drupal: fnv1alpha1.SpecDrupal{RolloutStrategy: &fnv1alpha1.RolloutStrategy{BlueGreen: &fnv1alpha1.BlueGreenRolloutStrategy{
+				AutoPromotionSeconds:  int32Ptr(60),
+				ScaleDownDelaySeconds: int32Ptr(120),
+			}}},
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				AutoPromotionEnabled:  boolPtr(true),
+				AutoPromotionSeconds:  int32Ptr(60),
+				ScaleDownDelaySeconds: int32Ptr(120),
+			}},
+		},
+		{
+			name: "blue/green promoted by hand",
+			drupal: fnv1alpha1.SpecDrupal{RolloutStrategy: &fnv1alpha1.RolloutStrategy{BlueGreen: &fnv1alpha1.BlueGreenRolloutStrategy{
+				AutoPromotion:        boolPtr(false),
+				AutoPromotionSeconds: int32Ptr(60),
+			}}},
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				AutoPromotionEnabled:  boolPtr(false),
+				ScaleDownDelaySeconds: int32Ptr(30),
+			}},
+		},
+		{
+			name:   "blue/green with analysis",
+			drupal: fnv1alpha1.SpecDrupal{Analysis: analysis},
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				PreviewService:        drupalPreviewServiceName,
+				AutoPromotionEnabled:  boolPtr(true),
+				AutoPromotionSeconds:  int32Ptr(10),
+				ScaleDownDelaySeconds: int32Ptr(30),
+				PrePromotionAnalysis:  &rolloutAnalysis,
+			}},
+		},
+		{
+			name:          "blue/green with analysis holding promotion",
+			drupal:        fnv1alpha1.SpecDrupal{Analysis: analysis},
+			holdPromotion: true,
+			want: rolloutsv1alpha1.RolloutStrategy{BlueGreen: &rolloutsv1alpha1.BlueGreenStrategy{
+				ActiveService:         drupalServiceName,
+				PreviewService:        drupalPreviewServiceName,
+				AutoPromotionEnabled:  boolPtr(false),
+				ScaleDownDelaySeconds: int32Ptr(30),
+				PrePromotionAnalysis:  &rolloutAnalysis,
+			}},
+		},
+		{
+			name: "canary",
+			drupal: fnv1alpha1.SpecDrupal{RolloutStrategy: &fnv1alpha1.RolloutStrategy{Canary: &fnv1alpha1.CanaryRolloutStrategy{
+				Steps: []fnv1alpha1.CanaryStep{
+					{SetWeight: int32Ptr(20)},
+					{Pause: &fnv1alpha1.CanaryPause{DurationSeconds: int32Ptr(300)}},
+					{SetWeight: int32Ptr(50)},
+					{Pause: &fnv1alpha1.CanaryPause{}},
+				},
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			}}},
+			want: rolloutsv1alpha1.RolloutStrategy{Canary: &rolloutsv1alpha1.CanaryStrategy{
+				Steps: []rolloutsv1alpha1.CanaryStep{
+					{SetWeight: int32Ptr(20)},
+					{Pause: &rolloutsv1alpha1.RolloutPause{Duration: rolloutsv1alpha1.DurationFromInt(300)}},
+					{SetWeight: int32Ptr(50)},
+					{Pause: &rolloutsv1alpha1.RolloutPause{}},
+				},
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			}},
+		},
+		{
+			// Only blue/green Rollouts are held for the deploy hooks
+			name: "canary with analysis holding promotion",
+			drupal: fnv1alpha1.SpecDrupal{
+				RolloutStrategy: &fnv1alpha1.RolloutStrategy{Canary: &fnv1alpha1.CanaryRolloutStrategy{
+					Steps: []fnv1alpha1.CanaryStep{{SetWeight: int32Ptr(10)}},
+				}},
+				Analysis: analysis,
+			},
+			holdPromotion: true,
+			want: rolloutsv1alpha1.RolloutStrategy{Canary: &rolloutsv1alpha1.CanaryStrategy{
+				Steps:    []rolloutsv1alpha1.CanaryStep{{SetWeight: int32Ptr(10)}},
+				Analysis: &rolloutsv1alpha1.RolloutAnalysisBackground{RolloutAnalysis: rolloutAnalysis},
+			}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := drupalRolloutStrategy(c.drupal, c.holdPromotion)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("drupalRolloutStrategy returned\n%+v\nwant\n%+v", describeStrategy(got), describeStrategy(c.want))
+			}
+		})
+	}
+}
+
+// describeStrategy returns the fields of the strategy that is set, as pointers don't print their values
+func describeStrategy(s rolloutsv1alpha1.RolloutStrategy) interface{} {
+	if s.Canary != nil {
+		return *s.Canary
+	}
+	if s.BlueGreen != nil {
+		return *s.BlueGreen
+	}
+	return s
+}
@@ -9,6 +9,7 @@ import (
 
 	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	"github.com/acquia/fn-drupal-operator/pkg/common"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
 
 	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/go-logr/logr"
@@ -38,12 +39,20 @@ var log = logf.Log.WithName("controller_drupalenvironment")
 // Add creates a new DrupalEnvironment Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileDrupalEnvironment{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	apiClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileDrupalEnvironment{client: mgr.GetClient(), apiClient: apiClient, scheme: mgr.GetScheme()}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -63,12 +72,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Watch for changes to secondary resources and requeue the owner DrupalEnvironment
 	typesToWatch := []runtime.Object{
 		&v1.ConfigMap{},
-		// &v1.PersistentVolumeClaim{}, // Probably doesn't need to be watched, as its spec can't be changed
+		&v1.PersistentVolumeClaim{}, // Spec can't be changed, but its phase is reported in status
 		&v1.Secret{},
 		&v1.Service{},
 		&appsv1.Deployment{},
 		&autoscalingv1.HorizontalPodAutoscaler{},
 		&rolloutsv1alpha1.Rollout{},
+		&fnv1alpha1.Site{}, // The outcome of a Site's deploy hook decides whether the Rollout is promoted
 	}
 	for _, t := range typesToWatch {
 		err = c.Watch(&source.Kind{Type: t}, &handler.EnqueueRequestForOwner{
@@ -80,7 +90,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
-	return nil
+	// Reconcile every DrupalEnvironment when the operator configuration changes
+	return operatorconfig.Watch(c, mgr.GetClient(), &fnv1alpha1.DrupalEnvironmentList{})
 }
 
 var _ reconcile.Reconciler = &ReconcileDrupalEnvironment{}
@@ -90,7 +101,10 @@ type ReconcileDrupalEnvironment struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
-	scheme *runtime.Scheme
+	// apiClient reads from the API server rather than the cache, for unstructured objects and for Pods, which aren't
+	// worth an informer over the whole cluster
+	apiClient client.Client
+	scheme    *runtime.Scheme
 }
 
 // Reconcile reads that state of the cluster for a DrupalEnvironment object and makes changes based on the state read
@@ -101,6 +115,10 @@ type ReconcileDrupalEnvironment struct {
 func (r *ReconcileDrupalEnvironment) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	logger := log.WithValues("Request.Name", request.Name, "Request.Namespace", request.Namespace)
 
+	if err := operatorconfig.Load(r.client); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Fetch the DrupalEnvironment instance
 	env := &fnv1alpha1.DrupalEnvironment{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, env)
@@ -129,6 +147,10 @@ func (r *ReconcileDrupalEnvironment) Reconcile(request reconcile.Request) (recon
 		env:        env,
 		app:        &fnv1alpha1.DrupalApplication{},
 		logger:     logger,
+
+		deployHooks: env.Status.DeployHooks.DeepCopy(),
+		releases:    env.DeepCopy().Status.Releases,
+		images:      env.Status.Images.DeepCopy(),
 	}
 
 	// Check if this resource is being deleted
@@ -206,6 +228,9 @@ func (r *ReconcileDrupalEnvironment) Reconcile(request reconcile.Request) (recon
 		}
 	}
 
+	// Report the observed state of child resources, however far this reconcile gets
+	defer rh.updateStatus()
+
 	// Check if domain map secret/configmap exist, otherwise create them
 	domainsCM := &v1.ConfigMap{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "domain-map", Namespace: rh.namespace}, domainsCM)
@@ -349,11 +374,30 @@ catch_workers_output = yes`, rh.env.Spec.Phpfpm.Procs),
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
+	requeue, err = rh.reconcileImageDigests()
+	if err != nil || requeue {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+
+	if err := rh.reconcileDeployHooks(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	requeue, err = rh.reconcileAnalysis()
+	if err != nil || requeue {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+
 	requeue, err = rh.reconcileDrupalRollout()
 	if err != nil || requeue {
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
+	requeue, err = rh.reconcileRolloutPromotion()
+	if err != nil || requeue {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+
 	requeue, err = rh.reconcileProxySQLPVC()
 	if err != nil || requeue {
 		return reconcile.Result{Requeue: requeue}, err
@@ -398,6 +442,14 @@ type requestHandler struct {
 	app       *fnv1alpha1.DrupalApplication
 	namespace string
 	logger    logr.Logger
+
+	// deployHooks is the progress of the deploy hooks of the current release, which is recorded in the status
+	deployHooks *fnv1alpha1.DeployHooksStatus
+	// releases are the entries of status.releases, most recent first
+	releases []fnv1alpha1.Release
+	// images are the images of the current release pinned to their digests, and imagesErr the failure to resolve them
+	images    *fnv1alpha1.ResolvedImages
+	imagesErr error
 }
 
 func (rh *requestHandler) associateResourceWithController(o metav1.Object) {
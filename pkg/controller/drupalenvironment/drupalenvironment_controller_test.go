@@ -0,0 +1,33 @@
+package drupalenvironment
+
+import (
+	"testing"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/acquia/fn-drupal-operator/pkg/apis"
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+const testNamespace = "wlgore-prod"
+
+// newTestReconciler returns a ReconcileDrupalEnvironment backed by the fake client, with a DrupalEnvironment, its
+// application and the given objects
+func newTestReconciler(t *testing.T, env *fnv1alpha1.DrupalEnvironment, objs ...runtime.Object) *ReconcileDrupalEnvironment {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := rolloutsv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	app := &fnv1alpha1.DrupalApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: env.Spec.Application},
+		Spec:       fnv1alpha1.DrupalApplicationSpec{GitRepo: "wlgore"},
+	}
+	c := fake.NewFakeClient(append(objs, app, env)...)
+	return &ReconcileDrupalEnvironment{client: c, apiClient: c, scheme: scheme.Scheme}
+}
@@ -7,6 +7,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 )
 
 const drupalHPAName = "drupal"
@@ -14,9 +16,9 @@ const drupalHPAName = "drupal"
 func (rh *requestHandler) hpa() *autoscalingv1.HorizontalPodAutoscaler {
 	drupalSpec := rh.env.Spec.Drupal
 	targetmetric := drupalSpec.TargetCPUUtilizationPercentage
-	// FIXME: default values for CRDs
+	// Set by the defaulting webhook, but not on DrupalEnvironments created before it was installed
 	if targetmetric == nil {
-		tmp := int32(50)
+		tmp := fnv1alpha1.DefaultTargetCPUUtilizationPercentage
 		targetmetric = &tmp
 	}
 
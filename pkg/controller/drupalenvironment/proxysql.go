@@ -2,25 +2,45 @@ package drupalenvironment
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
 	"fmt"
+	"strconv"
 
+	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	"github.com/acquia/fn-drupal-operator/pkg/common"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
 )
 
+const (
+	proxySQLConfigSecretName     = "proxysql-cnf"
+	proxySQLConfigHashAnnotation = fnv1alpha1.LabelPrefix + "proxysql-config-hash"
+
+	// legacyProxySQLAdminPassword was hard-coded in proxysql.cnf before admin credentials were generated per environment
+	legacyProxySQLAdminPassword = "adminpassw0rd"
+
+	// The proxysql-cnf Secret keeps the admin credentials ProxySQL ran with before, until it has been given new ones
+	previousUsernameKey = "previousUsername"
+	previousPasswordKey = "previousPassword"
+)
+
+// proxySQLConfig is formatted with the admin username and password
 var proxySQLConfig string = `datadir="/var/lib/proxysql"
 admin_variables=
 {
-        admin_credentials="proxysql-admin:adminpassw0rd"
+        admin_credentials="%s:%s"
         mysql_ifaces="0.0.0.0:6032"
         refresh_interval=2000
 }
@@ -47,10 +67,12 @@ mysql_variables=
 }`
 
 func (rh *requestHandler) reconcileProxySQL() (requeue bool, err error) {
-	requeue, err = rh.reconcileConfigMap("proxysql-cnf", map[string]string{
-		"proxysql.cnf": proxySQLConfig,
-	})
+	requeue, err = rh.reconcileProxySQLAdminSecret()
+	if err != nil || requeue {
+		return requeue, err
+	}
 
+	configHash, requeue, err := rh.reconcileProxySQLConfig()
 	if err != nil || requeue {
 		return requeue, err
 	}
@@ -67,7 +89,7 @@ func (rh *requestHandler) reconcileProxySQL() (requeue bool, err error) {
 
 	op, err := controllerutil.CreateOrUpdate(context.TODO(), r.client, dep, func(existing runtime.Object) error {
 		realDEP := existing.(*appsv1.Deployment)
-		desired := rh.proxysqlDeployment(name)
+		desired := rh.proxysqlDeployment(name, configHash)
 
 		if realDEP.CreationTimestamp.IsZero() {
 			desired.DeepCopyInto(realDEP)
@@ -75,7 +97,10 @@ func (rh *requestHandler) reconcileProxySQL() (requeue bool, err error) {
 			return nil
 		}
 		realDEP.Spec.Replicas = desired.Spec.Replicas
+		realDEP.Spec.Template.Annotations = desired.Spec.Template.Annotations
 		realDEP.Spec.Template.Spec.Volumes = desired.Spec.Template.Spec.Volumes
+		realDEP.Spec.Template.Spec.ImagePullSecrets = desired.Spec.Template.Spec.ImagePullSecrets
+		realDEP.Spec.Template.Spec.NodeSelector = desired.Spec.Template.Spec.NodeSelector
 
 		realContainer := &realDEP.Spec.Template.Spec.Containers[0]
 		desiredContainer := &desired.Spec.Template.Spec.Containers[0]
@@ -94,9 +119,218 @@ func (rh *requestHandler) reconcileProxySQL() (requeue bool, err error) {
 		return true, nil
 	}
 
+	// proxysql.cnf used to be kept in a ConfigMap, which is no longer mounted now that it holds the admin password
+	legacyCM := &v1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: proxySQLConfigSecretName, Namespace: rh.namespace}, legacyCM)
+	if err == nil && common.IsControlledBy(rh.env, legacyCM) {
+		rh.logger.Info("Deleting legacy ProxySQL ConfigMap", "Namespace", legacyCM.Namespace, "Name", legacyCM.Name)
+		if err := r.client.Delete(context.TODO(), legacyCM); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
 	return false, nil
 }
 
+// reconcileProxySQLAdminSecret generates the ProxySQL admin credentials, if they don't exist yet. They can be rotated
+// by changing the password in the Secret.
+func (rh *requestHandler) reconcileProxySQLAdminSecret() (requeue bool, err error) {
+	r := rh.reconciler
+
+	found := &v1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: common.ProxySqlAdminSecretName, Namespace: rh.namespace}, found)
+	if err == nil {
+		return false, nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	// ":" separates the username and password in admin_credentials
+	password, err := common.RandPasswordWithout(":")
+	if err != nil {
+		return false, err
+	}
+
+	sec := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ProxySqlAdminSecretName,
+			Namespace: rh.namespace,
+			Labels:    rh.env.ChildLabels(),
+		},
+		StringData: map[string]string{
+			"username": common.ProxySqlAdminUser,
+			"password": password,
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	rh.associateResourceWithController(sec)
+
+	rh.logger.Info("Creating Secret", "Namespace", sec.Namespace, "Name", sec.Name)
+	if err := r.client.Create(context.TODO(), sec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reconcileProxySQLConfig renders proxysql.cnf with the admin credentials into a Secret, and returns its hash so that
+// the ProxySQL Deployment is rolled when it changes.
+//
+// ProxySQL only reads admin_credentials from proxysql.cnf when its data directory is empty, so when the credentials in
+// the admin Secret differ from the ones last rendered, they are also changed through the admin interface. The new
+// credentials are saved to the Secret first, along with the previous ones, so that a rotation interrupted after
+// ProxySQL accepted them is completed rather than locking the operator out.
+func (rh *requestHandler) reconcileProxySQLConfig() (configHash string, requeue bool, err error) {
+	r := rh.reconciler
+
+	adminSecret := &v1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: common.ProxySqlAdminSecretName, Namespace: rh.namespace}, adminSecret)
+	if err != nil {
+		return "", false, err
+	}
+	user, password := string(adminSecret.Data["username"]), string(adminSecret.Data["password"])
+
+	cnf := fmt.Sprintf(proxySQLConfig, user, password)
+	configHash = fmt.Sprintf("%x", sha256.Sum256([]byte(cnf)))
+	data := map[string][]byte{
+		"proxysql.cnf": []byte(cnf),
+		"username":     []byte(user),
+		"password":     []byte(password),
+	}
+
+	found := &v1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: proxySQLConfigSecretName, Namespace: rh.namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", false, err
+	}
+	exists := err == nil
+
+	if exists && len(found.Data[previousUsernameKey]) > 0 {
+		// A rotation was interrupted: complete it before starting another
+		if requeue, err := rh.completeProxySQLAdminRotation(found); requeue || err != nil {
+			return "", requeue, err
+		}
+	}
+
+	var appliedUser, appliedPassword string
+	if exists {
+		appliedUser, appliedPassword = string(found.Data["username"]), string(found.Data["password"])
+	} else {
+		// Environments created before the admin Secret existed are still running with the hard-coded password
+		dep := &appsv1.Deployment{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: "proxysql", Namespace: rh.namespace}, dep)
+		if err == nil {
+			appliedUser, appliedPassword = common.ProxySqlAdminUser, legacyProxySQLAdminPassword
+		} else if !errors.IsNotFound(err) {
+			return "", false, err
+		}
+	}
+	if appliedUser != "" && (appliedUser != user || appliedPassword != password) {
+		data[previousUsernameKey] = []byte(appliedUser)
+		data[previousPasswordKey] = []byte(appliedPassword)
+	}
+
+	if !exists {
+		found = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      proxySQLConfigSecretName,
+				Namespace: rh.namespace,
+				Labels:    rh.env.ChildLabels(),
+			},
+			Data: data,
+			Type: v1.SecretTypeOpaque,
+		}
+		rh.associateResourceWithController(found)
+
+		rh.logger.Info("Creating Secret", "Namespace", found.Namespace, "Name", found.Name)
+		if err := r.client.Create(context.TODO(), found); err != nil {
+			return "", false, err
+		}
+		requeue = true
+	} else if !cmp.Equal(found.Data, data) {
+		rh.logger.Info("Updating Secret", "Namespace", found.Namespace, "Name", found.Name)
+		found.Data = data
+		if err := r.client.Update(context.TODO(), found); err != nil {
+			return "", false, err
+		}
+	}
+
+	if len(found.Data[previousUsernameKey]) > 0 {
+		if requeue, err := rh.completeProxySQLAdminRotation(found); requeue || err != nil {
+			return "", requeue, err
+		}
+	}
+	return configHash, requeue, nil
+}
+
+// completeProxySQLAdminRotation applies the credentials of the proxysql-cnf Secret to the running ProxySQL, and then
+// drops the previous credentials from the Secret
+func (rh *requestHandler) completeProxySQLAdminRotation(cnfSecret *v1.Secret) (requeue bool, err error) {
+	requeue, err = rh.applyProxySQLAdminCredentials(
+		string(cnfSecret.Data[previousUsernameKey]), string(cnfSecret.Data[previousPasswordKey]),
+		string(cnfSecret.Data["username"]), string(cnfSecret.Data["password"]))
+	if requeue || err != nil {
+		return requeue, err
+	}
+
+	delete(cnfSecret.Data, previousUsernameKey)
+	delete(cnfSecret.Data, previousPasswordKey)
+	rh.logger.Info("Updating Secret", "Namespace", cnfSecret.Namespace, "Name", cnfSecret.Name)
+	return false, rh.reconciler.client.Update(context.TODO(), cnfSecret)
+}
+
+// applyProxySQLAdminCredentials changes the admin credentials of the running ProxySQL, and saves them to its data
+// directory so they survive the restart caused by the new proxysql.cnf. ProxySQL is connected to with the new
+// credentials first, in case an earlier attempt already changed them, and with the old ones if they are rejected.
+func (rh *requestHandler) applyProxySQLAdminCredentials(oldUser, oldPassword, user, password string) (requeue bool, err error) {
+	proxySqlAdmin, err := rh.proxySQLAdminConnection(user, password)
+	if common.IsAccessDenied(err) {
+		proxySqlAdmin, err = rh.proxySQLAdminConnection(oldUser, oldPassword)
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Without a Service, nothing can be connected to ProxySQL, so it is safe to just roll it
+			return false, nil
+		}
+		rh.logger.Error(err, "Failed to connect to the ProxySQL admin interface")
+		return true, nil
+	}
+	defer func() {
+		if err := proxySqlAdmin.Close(); err != nil {
+			rh.logger.Error(err, "Close() failed")
+		}
+	}()
+
+	rh.logger.Info("Rotating ProxySQL admin credentials")
+	queries := []string{
+		common.ProxySQLSetVariableSQL("admin-admin_credentials", user+":"+password),
+		`LOAD ADMIN VARIABLES TO RUNTIME`,
+		`SAVE ADMIN VARIABLES TO DISK`,
+	}
+	for _, query := range queries {
+		if _, err := proxySqlAdmin.Exec(query); err != nil {
+			rh.logger.Error(err, "Query failed rotating admin credentials")
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// proxySQLAdminConnection connects to the ProxySQL admin interface with the given credentials
+func (rh *requestHandler) proxySQLAdminConnection(user, password string) (*sql.DB, error) {
+	proxySqlAdmin, err := common.GetProxySqlAdminConnectionAs(rh.reconciler.client, rh.namespace, user, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := proxySqlAdmin.Ping(); err != nil {
+		proxySqlAdmin.Close()
+		return nil, err
+	}
+	return proxySqlAdmin, nil
+}
+
 func (rh *requestHandler) reconcileProxySQLPVC() (requeue bool, err error) {
 	r := rh.reconciler
 
@@ -186,7 +420,13 @@ func (rh *requestHandler) reconcileProxysqlServerConfig() (requeue bool, err err
 		return true, nil
 	}
 
-	query := fmt.Sprintf(`SELECT COUNT(*) FROM mysql_servers WHERE hostname='%s'`, clusterDbAdmin.Host)
+	port, err := strconv.Atoi(clusterDbAdmin.Port)
+	if err != nil {
+		rh.logger.Error(err, "Invalid cluster DB port", "Port", clusterDbAdmin.Port)
+		return false, err
+	}
+
+	query := common.ProxySQLCountServersSQL(clusterDbAdmin.Host)
 	row := proxySqlAdmin.QueryRow(query)
 	var numRows int
 	err = row.Scan(&numRows)
@@ -197,7 +437,7 @@ func (rh *requestHandler) reconcileProxysqlServerConfig() (requeue bool, err err
 	}
 
 	if numRows == 0 {
-		query = fmt.Sprintf(`INSERT INTO mysql_servers(hostgroup_id,hostname,port) VALUES (1,'%s',%s)`, clusterDbAdmin.Host, clusterDbAdmin.Port)
+		query = common.ProxySQLInsertServerSQL(1, clusterDbAdmin.Host, port)
 		_, err = proxySqlAdmin.Exec(query)
 		if err != nil {
 			rh.logger.Error(err, "Query failed", "Query", query)
@@ -205,15 +445,15 @@ func (rh *requestHandler) reconcileProxysqlServerConfig() (requeue bool, err err
 		}
 	}
 
-	_, err = proxySqlAdmin.Exec(fmt.Sprintf(`UPDATE global_variables SET variable_value='%s' WHERE variable_name='mysql-monitor_password'`, clusterDbAdmin.Password))
+	_, err = proxySqlAdmin.Exec(common.ProxySQLSetVariableSQL("mysql-monitor_password", clusterDbAdmin.Password))
 	if err != nil {
 		rh.logger.Error(err, "Query failed setting monitor password")
 		return false, err
 	}
 
 	queries := []string{
-		fmt.Sprintf(`UPDATE global_variables SET variable_value='%s' WHERE variable_name='mysql-monitor_username'`, clusterDbAdmin.User),
-		fmt.Sprintf(`UPDATE global_variables SET variable_value='2000' WHERE variable_name IN ('mysql-monitor_connect_interval','mysql-monitor_ping_interval','mysql-monitor_read_only_interval')`),
+		common.ProxySQLSetVariableSQL("mysql-monitor_username", clusterDbAdmin.User),
+		`UPDATE global_variables SET variable_value='2000' WHERE variable_name IN ('mysql-monitor_connect_interval','mysql-monitor_ping_interval','mysql-monitor_read_only_interval')`,
 		`LOAD MYSQL VARIABLES TO RUNTIME`,
 		`SAVE MYSQL VARIABLES TO DISK`,
 		`LOAD MYSQL SERVERS TO RUNTIME`,
@@ -279,13 +519,11 @@ func (rh *requestHandler) proxysqlPVC() *v1.PersistentVolumeClaim {
 	}
 }
 
-func (rh *requestHandler) proxysqlDeployment(name string) *appsv1.Deployment {
+func (rh *requestHandler) proxysqlDeployment(name, configHash string) *appsv1.Deployment {
 	accessMode := int32(420)
 
-	proxySQLConfigMap := v1.ConfigMapVolumeSource{
-		LocalObjectReference: v1.LocalObjectReference{
-			Name: "proxysql-cnf",
-		},
+	proxySQLConfigSecret := v1.SecretVolumeSource{
+		SecretName:  proxySQLConfigSecretName,
 		DefaultMode: &accessMode,
 	}
 
@@ -314,15 +552,17 @@ func (rh *requestHandler) proxysqlDeployment(name string) *appsv1.Deployment {
 			Template: v1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: ls,
+					Annotations: map[string]string{
+						proxySQLConfigHashAnnotation: configHash,
+					},
 				},
 				Spec: v1.PodSpec{
-					NodeSelector: map[string]string{
-						"function": "workers",
-					},
+					NodeSelector:     operatorconfig.Current().NodeSelector,
+					ImagePullSecrets: registry.Current().PullSecrets(),
 					Containers: []v1.Container{
 						{
 							Name:            "proxysql",
-							Image:           "severalnines/proxysql:" + rh.env.Spec.ProxySQL.Tag,
+							Image:           registry.Current().ProxySQLImage + ":" + rh.env.Spec.ProxySQL.Tag,
 							ImagePullPolicy: v1.PullIfNotPresent,
 							Ports: []v1.ContainerPort{
 								{
@@ -360,7 +600,7 @@ func (rh *requestHandler) proxysqlDeployment(name string) *appsv1.Deployment {
 					Volumes: []v1.Volume{
 						{
 							Name:         "proxysql-config",
-							VolumeSource: v1.VolumeSource{ConfigMap: &proxySQLConfigMap},
+							VolumeSource: v1.VolumeSource{Secret: &proxySQLConfigSecret},
 						},
 						{
 							Name:         "proxysql-disk",
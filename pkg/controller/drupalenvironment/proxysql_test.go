@@ -0,0 +1,152 @@
+package drupalenvironment
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+// newProxySQLTestHandler returns a requestHandler of an environment whose admin Secret holds the given password
+func newProxySQLTestHandler(t *testing.T, password string, objs ...runtime.Object) *requestHandler {
+	env := &fnv1alpha1.DrupalEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+		Spec:       fnv1alpha1.DrupalEnvironmentSpec{Application: "wlgore-app"},
+	}
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ProxySqlAdminSecretName, Namespace: testNamespace},
+		Data:       map[string][]byte{"username": []byte(common.ProxySqlAdminUser), "password": []byte(password)},
+	}
+	return &requestHandler{
+		reconciler: newTestReconciler(t, env, append(objs, adminSecret)...),
+		env:        env,
+		namespace:  testNamespace,
+		logger:     log,
+	}
+}
+
+// newProxySQLConfigSecret returns the proxysql-cnf Secret of a ProxySQL running with the given password
+func newProxySQLConfigSecret(password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: proxySQLConfigSecretName, Namespace: testNamespace},
+		Data: map[string][]byte{
+			"proxysql.cnf": []byte(fmt.Sprintf(proxySQLConfig, common.ProxySqlAdminUser, password)),
+			"username":     []byte(common.ProxySqlAdminUser),
+			"password":     []byte(password),
+		},
+	}
+}
+
+// unreachableProxySQLService returns a ProxySQL Service whose admin interface refuses connections
+func unreachableProxySQLService(t *testing.T) *corev1.Service {
+	if l, err := net.Listen("tcp", "127.0.0.1:6032"); err != nil {
+		t.Skipf("the ProxySQL admin port is in use: %v", err)
+	} else {
+		l.Close()
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxysql", Namespace: testNamespace},
+		Spec:       corev1.ServiceSpec{ClusterIP: "127.0.0.1"},
+	}
+}
+
+// getProxySQLConfigSecret returns the data of the proxysql-cnf Secret
+func getProxySQLConfigSecret(t *testing.T, rh *requestHandler) map[string]string {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: testNamespace, Name: proxySQLConfigSecretName}
+	if err := rh.reconciler.client.Get(context.TODO(), key, secret); err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]string{}
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	return data
+}
+
+func TestReconcileProxySQLConfigPersistsCredentialsBeforeRotating(t *testing.T) {
+	service := unreachableProxySQLService(t)
+	rh := newProxySQLTestHandler(t, "new-password", newProxySQLConfigSecret("old-password"), service)
+
+	// ProxySQL can't be reached, so it keeps running with the old credentials, which are kept along with the new ones
+	configHash, requeue, err := rh.reconcileProxySQLConfig()
+	if err != nil || !requeue || configHash != "" {
+		t.Fatalf("reconcileProxySQLConfig returned %q, %t, %v, want a requeue without rolling ProxySQL", configHash, requeue, err)
+	}
+	data := getProxySQLConfigSecret(t, rh)
+	if data["password"] != "new-password" || data[previousUsernameKey] != common.ProxySqlAdminUser || data[previousPasswordKey] != "old-password" {
+		t.Errorf("proxysql-cnf holds %v, want the new password and the previous credentials", data)
+	}
+
+	// A second rotation before the first is applied doesn't lose the credentials ProxySQL runs with
+	adminSecret := &corev1.Secret{}
+	if err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: common.ProxySqlAdminSecretName}, adminSecret); err != nil {
+		t.Fatal(err)
+	}
+	adminSecret.Data["password"] = []byte("newer-password")
+	if err := rh.reconciler.client.Update(context.TODO(), adminSecret); err != nil {
+		t.Fatal(err)
+	}
+	if _, requeue, err := rh.reconcileProxySQLConfig(); err != nil || !requeue {
+		t.Fatalf("reconcileProxySQLConfig returned %t, %v, want a requeue", requeue, err)
+	}
+	if data := getProxySQLConfigSecret(t, rh); data[previousPasswordKey] != "old-password" {
+		t.Errorf("proxysql-cnf holds %v, want the previous password kept", data)
+	}
+
+	// Once the rotation is done, ProxySQL is rolled with the new proxysql.cnf and the previous credentials are dropped
+	if err := rh.reconciler.client.Delete(context.TODO(), service); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if configHash, requeue, err = rh.reconcileProxySQLConfig(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if configHash == "" || requeue {
+		t.Errorf("reconcileProxySQLConfig returned %q, %t after rotating", configHash, requeue)
+	}
+	data = getProxySQLConfigSecret(t, rh)
+	if _, ok := data[previousPasswordKey]; ok || data["password"] != "newer-password" {
+		t.Errorf("proxysql-cnf holds %v, want only the newer credentials", data)
+	}
+	if data["proxysql.cnf"] != fmt.Sprintf(proxySQLConfig, common.ProxySqlAdminUser, "newer-password") {
+		t.Errorf("proxysql.cnf wasn't rendered with the newer password:\n%s", data["proxysql.cnf"])
+	}
+}
+
+func TestReconcileProxySQLConfigRotatesLegacyPassword(t *testing.T) {
+	service := unreachableProxySQLService(t)
+	legacy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "proxysql", Namespace: testNamespace}}
+	rh := newProxySQLTestHandler(t, "generated-password", legacy, service)
+
+	if _, requeue, err := rh.reconcileProxySQLConfig(); err != nil || !requeue {
+		t.Fatalf("reconcileProxySQLConfig returned %t, %v, want a requeue", requeue, err)
+	}
+	data := getProxySQLConfigSecret(t, rh)
+	if data["password"] != "generated-password" || data[previousPasswordKey] != legacyProxySQLAdminPassword {
+		t.Errorf("proxysql-cnf holds %v, want the generated password and the legacy one", data)
+	}
+}
+
+func TestReconcileProxySQLConfigOfNewEnvironment(t *testing.T) {
+	rh := newProxySQLTestHandler(t, "generated-password")
+
+	configHash, requeue, err := rh.reconcileProxySQLConfig()
+	if err != nil || !requeue || configHash == "" {
+		t.Fatalf("reconcileProxySQLConfig returned %q, %t, %v", configHash, requeue, err)
+	}
+	data := getProxySQLConfigSecret(t, rh)
+	if _, ok := data[previousUsernameKey]; ok {
+		t.Errorf("proxysql-cnf of a new ProxySQL holds previous credentials: %v", data)
+	}
+}
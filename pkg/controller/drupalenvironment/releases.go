@@ -0,0 +1,317 @@
+package drupalenvironment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+const defaultReleaseHistoryLimit = 10
+
+// splitDigest splits an image reference pinned to a digest, such as repo:tag@sha256:..., into the rest of the
+// reference and the digest. It also takes the image IDs reported in container statuses.
+func splitDigest(image string) (ref, digest string) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// templateImages returns the images of the containers of a pod template, init containers first
+func templateImages(template *v1.PodTemplateSpec) []fnv1alpha1.ReleaseImage {
+	var images []fnv1alpha1.ReleaseImage
+	for _, containers := range [][]v1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+		for _, c := range containers {
+			_, digest := splitDigest(c.Image)
+			images = append(images, fnv1alpha1.ReleaseImage{Container: c.Name, Image: c.Image, Digest: digest})
+		}
+	}
+	return images
+}
+
+// sameRelease is true if a release has the given images and git ref. The digests observed since the release was
+// recorded are ignored.
+func sameRelease(release *fnv1alpha1.Release, images []fnv1alpha1.ReleaseImage, gitRef string) bool {
+	if release.GitRef != gitRef || len(release.Images) != len(images) {
+		return false
+	}
+	for i := range images {
+		if release.Images[i].Container != images[i].Container || release.Images[i].Image != images[i].Image {
+			return false
+		}
+	}
+	return true
+}
+
+// rollbackRelease returns the release spec.rollbackTo redeploys: the entry with that revision or, once it has been
+// redeployed, the latest entry that redeployed it. It returns nil if spec.rollbackTo isn't set.
+func (rh *requestHandler) rollbackRelease() (*fnv1alpha1.Release, error) {
+	revision := rh.env.Spec.RollbackTo
+	if revision == nil {
+		return nil, nil
+	}
+	for i := range rh.releases {
+		release := &rh.releases[i]
+		if release.Revision == *revision || (release.RollbackOf != nil && *release.RollbackOf == *revision) {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("spec.rollbackTo: release %d is not in status.releases", *revision)
+}
+
+// rollbackTemplate replaces the images and git ref of the Drupal pod template with those of a release, pinned to the
+// digests its pods pulled when they are known
+func rollbackTemplate(template *v1.PodTemplateSpec, release *fnv1alpha1.Release) {
+	images := make(map[string]string, len(release.Images))
+	for _, image := range release.Images {
+		ref, digest := splitDigest(image.Image)
+		if digest == "" {
+			digest = image.Digest
+		}
+		if digest != "" {
+			ref += "@" + digest
+		}
+		images[image.Container] = ref
+	}
+	for _, containers := range [][]v1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+		for i := range containers {
+			if image, ok := images[containers[i].Name]; ok {
+				containers[i].Image = image
+			}
+		}
+	}
+	template.Annotations[gitRefAnnotation] = release.GitRef
+}
+
+// recordRelease adds an entry to status.releases when the images or git ref of the Drupal pod template differ from
+// those of the latest release, and drops the entries beyond spec.releaseHistoryLimit
+func (rh *requestHandler) recordRelease(template *v1.PodTemplateSpec, rollback *fnv1alpha1.Release) error {
+	images := templateImages(template)
+	gitRef := template.Annotations[gitRefAnnotation]
+
+	var latest *fnv1alpha1.Release
+	if len(rh.releases) > 0 {
+		latest = &rh.releases[0]
+		if sameRelease(latest, images, gitRef) {
+			return nil
+		}
+	}
+
+	spec := rh.env.Spec
+	release := fnv1alpha1.Release{
+		Revision:  1,
+		DrupalTag: spec.Drupal.Tag,
+		ApacheTag: spec.Apache.Tag,
+		PhpFpmTag: spec.Phpfpm.Tag,
+		GitRef:    gitRef,
+		Images:    images,
+		Time:      metav1.Now(),
+		Outcome:   fnv1alpha1.ReleaseProgressing,
+	}
+	if latest != nil {
+		release.Revision = latest.Revision + 1
+	}
+
+	// The fields of the spec that changed decide whose change the release is
+	var fields [][]string
+	switch {
+	case rollback != nil:
+		release.DrupalTag, release.ApacheTag, release.PhpFpmTag = rollback.DrupalTag, rollback.ApacheTag, rollback.PhpFpmTag
+		revision := *spec.RollbackTo
+		release.RollbackOf = &revision
+		fields = append(fields, []string{"spec", "rollbackTo"})
+	case latest != nil:
+		if latest.DrupalTag != release.DrupalTag {
+			fields = append(fields, []string{"spec", "drupal", "tag"})
+		}
+		if latest.ApacheTag != release.ApacheTag {
+			fields = append(fields, []string{"spec", "apache", "tag"})
+		}
+		if latest.PhpFpmTag != release.PhpFpmTag {
+			fields = append(fields, []string{"spec", "phpfpm", "tag"})
+		}
+		if latest.GitRef != release.GitRef {
+			fields = append(fields, []string{"spec", "gitRef"})
+		}
+	}
+	changedBy, err := rh.changedBy(fields)
+	if err != nil {
+		return err
+	}
+	release.ChangedBy = changedBy
+
+	for i := range rh.releases {
+		if rh.releases[i].Outcome == fnv1alpha1.ReleaseProgressing {
+			rh.releases[i].Outcome = fnv1alpha1.ReleaseSuperseded
+		}
+	}
+	rh.releases = append([]fnv1alpha1.Release{release}, rh.releases...)
+
+	limit := defaultReleaseHistoryLimit
+	if spec.ReleaseHistoryLimit != nil && *spec.ReleaseHistoryLimit > 0 {
+		limit = int(*spec.ReleaseHistoryLimit)
+	}
+	if len(rh.releases) > limit {
+		rh.releases = rh.releases[:limit]
+	}
+
+	rh.logger.Info("Recorded Drupal release", "Revision", release.Revision, "Tag", release.DrupalTag,
+		"GitRef", release.GitRef, "ChangedBy", release.ChangedBy)
+	return nil
+}
+
+// changedBy returns the field manager that most recently set any of the given fields of the DrupalEnvironment, or any
+// field of its spec when none of them is found. It returns "" if the API server doesn't track managed fields.
+func (rh *requestHandler) changedBy(fields [][]string) (string, error) {
+	// managedFields isn't part of ObjectMeta in this API version, so the DrupalEnvironment is read unstructured
+	env := &unstructured.Unstructured{}
+	env.SetGroupVersionKind(fnv1alpha1.SchemeGroupVersion.WithKind("DrupalEnvironment"))
+	err := rh.reconciler.apiClient.Get(context.TODO(), types.NamespacedName{Name: rh.env.Name, Namespace: rh.env.Namespace}, env)
+	if err != nil {
+		return "", err
+	}
+	managedFields, _, err := unstructured.NestedSlice(env.Object, "metadata", "managedFields")
+	if err != nil {
+		return "", err
+	}
+
+	lastManager := func(fields [][]string) string {
+		var manager string
+		var last time.Time
+		for _, entry := range managedFields {
+			entry, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// API servers before 1.17 name the field set "fields"
+			fieldSet, ok := entry["fieldsV1"].(map[string]interface{})
+			if !ok {
+				fieldSet, _ = entry["fields"].(map[string]interface{})
+			}
+			if !ownsAny(fieldSet, fields) {
+				continue
+			}
+			name, _ := entry["manager"].(string)
+			timestamp, _ := entry["time"].(string)
+			t, _ := time.Parse(time.RFC3339, timestamp)
+			if manager == "" || t.After(last) {
+				manager, last = name, t
+			}
+		}
+		return manager
+	}
+
+	if manager := lastManager(fields); manager != "" {
+		return manager, nil
+	}
+	return lastManager([][]string{{"spec"}}), nil
+}
+
+// ownsAny is true if the field set of a managedFields entry contains any of the given field paths
+func ownsAny(fieldSet map[string]interface{}, paths [][]string) bool {
+	for _, path := range paths {
+		set := fieldSet
+		for _, name := range path {
+			set, _ = set["f:"+name].(map[string]interface{})
+		}
+		if set != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// observeReleases records the outcome of the latest release from the Drupal Rollout, and the digests of its images
+// from the pods running it
+func (rh *requestHandler) observeReleases(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	if len(rh.releases) == 0 {
+		status.Releases = nil
+		return nil
+	}
+	status.Releases = make([]fnv1alpha1.Release, len(rh.releases))
+	for i := range rh.releases {
+		rh.releases[i].DeepCopyInto(&status.Releases[i])
+	}
+
+	latest := &status.Releases[0]
+	missingDigest := false
+	for _, image := range latest.Images {
+		missingDigest = missingDigest || image.Digest == ""
+	}
+	if latest.Outcome != fnv1alpha1.ReleaseProgressing && !missingDigest {
+		return nil
+	}
+
+	rollout := &rolloutsv1alpha1.Rollout{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: drupalRolloutName, Namespace: rh.namespace}, rollout)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Until Argo has created the ReplicaSet of the release, the status of the Rollout is that of the previous one
+	hash := rollout.Status.CurrentPodHash
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := rh.reconciler.client.List(context.TODO(), client.InNamespace(rh.namespace).MatchingLabels(labelsForDeployment(rh.env)), replicaSets); err != nil {
+		return err
+	}
+	current := false
+	for _, replicaSet := range replicaSets.Items {
+		template := &replicaSet.Spec.Template
+		if hash != "" && replicaSet.Labels[rolloutPodTemplateHashLabel] == hash &&
+			sameRelease(latest, templateImages(template), template.Annotations[gitRefAnnotation]) {
+			current = true
+		}
+	}
+	if !current {
+		return nil
+	}
+
+	if latest.Outcome == fnv1alpha1.ReleaseProgressing {
+		switch {
+		case rollout.Status.Abort:
+			latest.Outcome = fnv1alpha1.ReleaseAborted
+		case !rolloutInProgress(rollout):
+			latest.Outcome = fnv1alpha1.ReleaseDeployed
+		}
+	}
+
+	if missingDigest {
+		ls := labelsForDeployment(rh.env)
+		ls[rolloutPodTemplateHashLabel] = hash
+		pods := &v1.PodList{}
+		if err := rh.reconciler.apiClient.List(context.TODO(), client.InNamespace(rh.namespace).MatchingLabels(ls), pods); err != nil {
+			return err
+		}
+		digests := map[string]string{}
+		for _, pod := range pods.Items {
+			for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+				for _, s := range statuses {
+					if _, digest := splitDigest(s.ImageID); digest != "" {
+						digests[s.Name] = digest
+					}
+				}
+			}
+		}
+		for i := range latest.Images {
+			if latest.Images[i].Digest == "" {
+				latest.Images[i].Digest = digests[latest.Images[i].Container]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package drupalenvironment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rolloutsv1alpha1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// updateStatus observes the DrupalEnvironment's child resources and records their state on the status subresource.
+// It is deferred by Reconcile, so errors are logged rather than returned.
+func (rh *requestHandler) updateStatus() {
+	status := rh.env.Status.DeepCopy()
+	status.ObservedGeneration = rh.env.Generation
+
+	observers := []func(*fnv1alpha1.DrupalEnvironmentStatus) error{
+		rh.observeProxySQL,
+		rh.observeStorage,
+		rh.observeRollout,
+		rh.observeHPA,
+		rh.observeDeployHooks,
+		rh.observeReleases,
+		rh.observeImages,
+	}
+	for _, observe := range observers {
+		if err := observe(status); err != nil {
+			rh.logger.Error(err, "Failed to observe DrupalEnvironment status")
+			return
+		}
+	}
+
+	ready := fnv1alpha1.IsConditionTrue(status.Conditions, fnv1alpha1.ConditionProxySQLReady) &&
+		fnv1alpha1.IsConditionTrue(status.Conditions, fnv1alpha1.ConditionStorageBound) &&
+		fnv1alpha1.IsConditionTrue(status.Conditions, fnv1alpha1.ConditionRolloutHealthy)
+	if ready {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionReady, v1.ConditionTrue, "EnvironmentReady", "")
+	} else {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionReady, v1.ConditionFalse, "EnvironmentNotReady",
+			"One or more of ProxySQLReady, StorageBound or RolloutHealthy is not True")
+	}
+
+	if cmp.Equal(*status, rh.env.Status) {
+		return
+	}
+
+	rh.env.Status = *status
+	if err := rh.reconciler.client.Status().Update(context.TODO(), rh.env); err != nil {
+		rh.logger.Error(err, "Failed to update DrupalEnvironment status")
+	}
+}
+
+func (rh *requestHandler) observeProxySQL(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	dep := &appsv1.Deployment{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "proxysql", Namespace: rh.namespace}, dep)
+	if err != nil && errors.IsNotFound(err) {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionProxySQLReady, v1.ConditionFalse, "DeploymentNotFound",
+			"ProxySQL Deployment has not been created yet")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	ready := dep.Status.ReadyReplicas > 0 && dep.Status.ReadyReplicas >= desired
+	reason := "ReplicasReady"
+	if !ready {
+		reason = "ReplicasNotReady"
+	}
+	fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionProxySQLReady, fnv1alpha1.ConditionStatusFor(ready), reason,
+		fmt.Sprintf("%d/%d ProxySQL replicas ready", dep.Status.ReadyReplicas, desired))
+	return nil
+}
+
+func (rh *requestHandler) observeStorage(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	var unbound []string
+
+	for _, name := range []string{string(rh.env.Id()) + "-files", "proxysql-data"} {
+		pvc := &v1.PersistentVolumeClaim{}
+		err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: rh.namespace}, pvc)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err != nil || pvc.Status.Phase != v1.ClaimBound {
+			unbound = append(unbound, name)
+		}
+	}
+
+	if len(unbound) > 0 {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionStorageBound, v1.ConditionFalse, "ClaimsNotBound",
+			"PersistentVolumeClaims not bound: "+strings.Join(unbound, ", "))
+		return nil
+	}
+	fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionStorageBound, v1.ConditionTrue, "ClaimsBound", "")
+	return nil
+}
+
+func (rh *requestHandler) observeRollout(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	rollout := &rolloutsv1alpha1.Rollout{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: drupalRolloutName, Namespace: rh.namespace}, rollout)
+	if err != nil && errors.IsNotFound(err) {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionRolloutHealthy, v1.ConditionFalse, "RolloutNotFound",
+			"Drupal Rollout has not been created yet")
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionProgressing, v1.ConditionUnknown, "RolloutNotFound", "")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	rs := rollout.Status
+	status.Drupal.Replicas = rs.Replicas
+	status.Drupal.UpdatedReplicas = rs.UpdatedReplicas
+	status.Drupal.ReadyReplicas = rs.ReadyReplicas
+	status.Drupal.AvailableReplicas = rs.AvailableReplicas
+	for _, c := range rollout.Spec.Template.Spec.InitContainers {
+		if c.Name == codeCopyContainerName {
+			status.Drupal.Tag = imageTag(c.Image)
+		}
+	}
+
+	if err := rh.observeRollback(rollout, status); err != nil {
+		return err
+	}
+
+	switch {
+	case rs.Abort:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionRolloutHealthy, v1.ConditionFalse, "RolloutAborted",
+			"Drupal Rollout has been aborted")
+	case rs.AvailableReplicas == 0:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionRolloutHealthy, v1.ConditionFalse, "NoAvailableReplicas",
+			"Drupal Rollout has no available replicas")
+	default:
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionRolloutHealthy, v1.ConditionTrue, "ReplicasAvailable",
+			fmt.Sprintf("%d/%d Drupal replicas available", rs.AvailableReplicas, rs.Replicas))
+	}
+
+	// The new ReplicaSet only becomes active once the rollout is promoted
+	if rolloutInProgress(rollout) {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionProgressing, v1.ConditionTrue, "RolloutInProgress",
+			fmt.Sprintf("%d/%d Drupal replicas updated", rs.UpdatedReplicas, rs.Replicas))
+	} else {
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionProgressing, v1.ConditionFalse, "RolloutComplete", "")
+	}
+	return nil
+}
+
+func (rh *requestHandler) observeHPA(status *fnv1alpha1.DrupalEnvironmentStatus) error {
+	hpa := &autoscalingv1.HorizontalPodAutoscaler{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: drupalHPAName, Namespace: rh.namespace}, hpa)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	status.Drupal.DesiredReplicas = hpa.Status.DesiredReplicas
+	return nil
+}
+
+// imageTag returns the tag portion of a container image reference, or "" if it has none
+func imageTag(image string) string {
+	image, _ = splitDigest(image)
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
@@ -0,0 +1,108 @@
+package drupaloperatorconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+)
+
+const (
+	reasonApplied = "Applied"
+	reasonInvalid = "Invalid"
+	reasonIgnored = "Ignored"
+)
+
+var log = logf.Log.WithName("controller_drupaloperatorconfig")
+
+// Add creates a new DrupalOperatorConfig Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileDrupalOperatorConfig{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("drupaloperatorconfig-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &fnv1alpha1.DrupalOperatorConfig{}}, &handler.EnqueueRequestForObject{})
+}
+
+var _ reconcile.Reconciler = &ReconcileDrupalOperatorConfig{}
+
+// ReconcileDrupalOperatorConfig validates DrupalOperatorConfigs and reports the result in their status. The
+// configuration itself is loaded by each controller before it reconciles, so that they all see the same version.
+type ReconcileDrupalOperatorConfig struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// Reconcile validates a DrupalOperatorConfig and updates its Valid condition
+func (r *ReconcileDrupalOperatorConfig) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := log.WithValues("Request.Name", request.Name)
+	logger.Info("Reconciling DrupalOperatorConfig")
+
+	config := &fnv1alpha1.DrupalOperatorConfig{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: request.Name}, config)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The defaults are restored by operatorconfig.Load
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	status := config.Status.DeepCopy()
+	status.ObservedGeneration = config.Generation
+
+	if config.Name != fnv1alpha1.OperatorConfigName {
+		status.Errors = nil
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionValid, corev1.ConditionFalse, reasonIgnored,
+			fmt.Sprintf("Only the DrupalOperatorConfig named %q is used", fnv1alpha1.OperatorConfigName))
+	} else if _, errs := operatorconfig.Resolve(config.Spec); len(errs) > 0 {
+		status.Errors = errs
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionValid, corev1.ConditionFalse, reasonInvalid,
+			"The previous configuration is still in effect: "+strings.Join(errs, "; "))
+		logger.Info("DrupalOperatorConfig is invalid", "Errors", errs)
+	} else {
+		status.Errors = nil
+		fnv1alpha1.SetCondition(&status.Conditions, fnv1alpha1.ConditionValid, corev1.ConditionTrue, reasonApplied, "")
+		if err := operatorconfig.Load(r.client); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !cmp.Equal(*status, config.Status) {
+		config.Status = *status
+		if err := r.client.Status().Update(context.TODO(), config); err != nil {
+			logger.Error(err, "Failed to update status")
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
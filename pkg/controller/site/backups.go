@@ -0,0 +1,208 @@
+package site
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/backup"
+)
+
+// backupJobTemplateHashAnnotation records the hash of the Job template a backup CronJob was built with. The API server
+// defaults fields of the template, so it can't be compared with the desired template directly.
+const backupJobTemplateHashAnnotation = fn.LabelPrefix + "backup-job-template-hash"
+
+func (rh *requestHandler) backupCronJobName() string {
+	return rh.site.BackupCronJobName()
+}
+
+// BackupCronJob builds the CronJob running the scheduled backups described by spec.backups
+func (rh *requestHandler) BackupCronJob() (batchv1b1.CronJob, error) {
+	failedJobsHistoryLimit := int32(1)
+	successfulJobsHistoryLimit := int32(3)
+	startingDeadlineSeconds := int64(900)
+
+	backups := rh.site.Spec.Backups
+	retention := &backup.Retention{Count: backups.RetentionCount}
+	if backups.RetentionAge != nil {
+		retention.Age = backups.RetentionAge.Duration
+	}
+	archive := backup.Archive{
+		Site:      rh.site,
+		SiteURI:   rh.site.DrushURI(),
+		Env:       rh.env,
+		Method:    backups.Method.OrDefault(),
+		Target:    backups.Target,
+		Location:  backup.ScheduledLocation(rh.site),
+		Retention: retention,
+	}
+
+	labels := rh.site.ChildLabels()
+	labels["type"] = "backup"
+
+	jobTemplate := batchv1b1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: archive.BackupJobSpec(rh.customerJobSpec(nil)),
+	}
+	templateJSON, err := json.Marshal(jobTemplate)
+	if err != nil {
+		return batchv1b1.CronJob{}, err
+	}
+
+	return batchv1b1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rh.backupCronJobName(),
+			Namespace: rh.site.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				backupJobTemplateHashAnnotation: fmt.Sprintf("%x", sha256.Sum256(templateJSON)),
+			},
+		},
+		Spec: batchv1b1.CronJobSpec{
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			Suspend:                    &backups.Suspend,
+			StartingDeadlineSeconds:    &startingDeadlineSeconds,
+
+			// A backup that is still running when the next one is due is left to finish
+			ConcurrencyPolicy: batchv1b1.ForbidConcurrent,
+
+			Schedule: backups.Schedule,
+
+			JobTemplate: jobTemplate,
+		},
+	}, nil
+}
+
+// deleteUnwantedBackupCrons deletes the Site's backup CronJob when spec.backups is removed, the same way
+// deleteUnwantedCrons deletes the CronJobs of removed crons.
+func (rh *requestHandler) deleteUnwantedBackupCrons() (bool, error) {
+	cronList, err := rh.getOwnedCronJobs("backup")
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range cronList.Items {
+		if rh.site.Spec.Backups == nil || c.Name != rh.backupCronJobName() {
+			bg := client.PropagationPolicy(metav1.DeletePropagationBackground)
+			if err := rh.reconciler.client.Delete(context.TODO(), &c, bg); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// reconcileBackupCronJob creates, updates or deletes the CronJob running the Site's scheduled backups
+func (rh *requestHandler) reconcileBackupCronJob() (requeue bool, err error) {
+	if requeue, err = rh.deleteUnwantedBackupCrons(); requeue || err != nil {
+		return
+	}
+	if rh.site.Spec.Backups == nil {
+		return false, nil
+	}
+	if err := rh.site.Spec.Backups.Target.Validate(); err != nil {
+		return false, fmt.Errorf("invalid spec.backups: %v", err)
+	}
+
+	newCronJob, err := rh.BackupCronJob()
+	if err != nil {
+		return false, err
+	}
+
+	cronjob := &batchv1b1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: newCronJob.Name, Namespace: newCronJob.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(context.TODO(), rh.reconciler.client, cronjob, func(existing runtime.Object) error {
+		realCronJob := existing.(*batchv1b1.CronJob)
+
+		if realCronJob.CreationTimestamp.IsZero() {
+			newCronJob.DeepCopyInto(realCronJob)
+			rh.reconciler.associateResourceWithController(rh.logger, realCronJob, rh.site)
+			return nil
+		}
+
+		realCronJob.Labels = newCronJob.Labels
+
+		realCronSpec := &realCronJob.Spec
+		newSpec := &newCronJob.Spec
+
+		realCronSpec.Suspend = newSpec.Suspend
+		realCronSpec.Schedule = newSpec.Schedule
+		realCronSpec.ConcurrencyPolicy = newSpec.ConcurrencyPolicy
+		realCronSpec.StartingDeadlineSeconds = newSpec.StartingDeadlineSeconds
+
+		hash := newCronJob.Annotations[backupJobTemplateHashAnnotation]
+		if realCronJob.Annotations[backupJobTemplateHashAnnotation] != hash {
+			if realCronJob.Annotations == nil {
+				realCronJob.Annotations = map[string]string{}
+			}
+			realCronJob.Annotations[backupJobTemplateHashAnnotation] = hash
+			realCronSpec.JobTemplate = newSpec.JobTemplate
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+	if op != controllerutil.OperationResultNone {
+		rh.logger.Info("Successfully reconciled backup CronJob", "Name", newCronJob.Name, "operation", op)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// observeBackups records the Site's backup CronJob and when it was last run in status, along with the time of the
+// latest successful backup. The Jobs of both scheduled backups and SiteBackups are labelled type=backup, so both count.
+// Finished Jobs are eventually removed, so the latest time seen is kept.
+func (rh *requestHandler) observeBackups() error {
+	rh.status.Backups.CronJobName = ""
+	if rh.site.Spec.Backups != nil {
+		cronjob := &batchv1b1.CronJob{}
+		err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.backupCronJobName()}, cronjob)
+		if err == nil {
+			rh.status.Backups.CronJobName = cronjob.Name
+			rh.status.Backups.LastScheduleTime = cronjob.Status.LastScheduleTime
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	jobList := &batchv1.JobList{}
+	listOpts := client.InNamespace(rh.site.Namespace).MatchingLabels(map[string]string{
+		fn.SiteIdLabel: string(rh.site.Id()),
+		"type":         "backup",
+	})
+	if err := rh.reconciler.client.List(context.TODO(), listOpts, jobList); err != nil {
+		return err
+	}
+
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if JobOutcome(job) != fn.JobSucceeded || job.Status.CompletionTime == nil {
+			continue
+		}
+		last := rh.status.Backups.LastSuccessfulTime
+		if last == nil || last.Before(job.Status.CompletionTime) {
+			rh.status.Backups.LastSuccessfulTime = job.Status.CompletionTime.DeepCopy()
+		}
+	}
+
+	return nil
+}
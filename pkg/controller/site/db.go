@@ -2,10 +2,15 @@ package site
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	"github.com/acquia/fn-drupal-operator/pkg/common"
 )
 
@@ -30,6 +35,74 @@ func (rh *requestHandler) getDB() (common.Database, error) {
 	}, nil
 }
 
+// validateDatabaseNames checks that the Site's name results in a valid MySQL database and user name, which no other
+// Site of the namespace uses.
+func (rh *requestHandler) validateDatabaseNames() error {
+	if err := common.ValidateIdentifier(rh.site.DatabaseName(), common.MaxDatabaseNameLength); err != nil {
+		return fmt.Errorf("invalid database name: %v", err)
+	}
+	if err := common.ValidateIdentifier(rh.site.DatabaseUser(), common.MaxUserNameLength); err != nil {
+		return fmt.Errorf("invalid database user: %v", err)
+	}
+	owner, err := rh.databaseOwner()
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		return fmt.Errorf("database %s and user %s are already used by Site %s", rh.site.DatabaseName(),
+			rh.site.DatabaseUser(), owner.Name)
+	}
+	return nil
+}
+
+// databaseOwner returns the Site of the namespace which uses the Site's database and user, if another one does.
+// Sanitizing removes characters from Site names, so "a-b" and "ab" have the same database, which belongs to the Site
+// created first.
+func (rh *requestHandler) databaseOwner() (*fn.Site, error) {
+	sites := &fn.SiteList{}
+	if err := rh.reconciler.client.List(context.TODO(), client.InNamespace(rh.site.Namespace), sites); err != nil {
+		return nil, err
+	}
+	for i := range sites.Items {
+		other := &sites.Items[i]
+		if other.UID == rh.site.UID || !claimedBefore(other, rh.site) {
+			continue
+		}
+		if other.DatabaseName() == rh.site.DatabaseName() || other.DatabaseUser() == rh.site.DatabaseUser() {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// sitesSharingDatabase maps a Site to the other Sites of its namespace with the same database, which can use it once
+// the Site is deleted
+func sitesSharingDatabase(c client.Client) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		site, ok := o.Object.(*fn.Site)
+		if !ok {
+			return nil
+		}
+
+		sites := &fn.SiteList{}
+		if err := c.List(context.TODO(), client.InNamespace(site.Namespace), sites); err != nil {
+			log.Error(err, "Failed to list Sites sharing a database", "Site", site.Name, "Namespace", site.Namespace)
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range sites.Items {
+			other := &sites.Items[i]
+			if other.UID != site.UID && other.DatabaseName() == site.DatabaseName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: other.Namespace, Name: other.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
 // getPassword returns the database password secret.
 func (rh *requestHandler) getPassword() (string, error) {
 	s := rh.site
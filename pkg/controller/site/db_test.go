@@ -0,0 +1,60 @@
+package site
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+func TestReconcileRefusesDatabaseOfAnotherSite(t *testing.T) {
+	provisioner := common.NewFakeDatabaseProvisioner()
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	first := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wl-gore", Namespace: testNamespace, UID: "wl-gore-uid", CreationTimestamp: created},
+		Spec:       fn.SiteSpec{Environment: "prod", Domains: []string{"wlgore.example.com"}},
+	}
+	second := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace, UID: "wlgore-uid", CreationTimestamp: metav1.Now()},
+		Spec:       fn.SiteSpec{Environment: "prod", Domains: []string{"www.wlgore.example.com"}},
+	}
+	if first.DatabaseName() != second.DatabaseName() {
+		t.Fatalf("Sites %s and %s have different databases", first.Name, second.Name)
+	}
+	database := first.DatabaseName()
+	r, c := newTestReconciler(t, provisioner, first, second)
+
+	reconcileUntilDone(t, r, first.Name)
+	password := provisioner.Users[database]
+	reconcileUntilDone(t, r, second.Name)
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: second.Name}, second); err != nil {
+		t.Fatal(err)
+	}
+	ready := fn.GetCondition(second.Status.Conditions, fn.ConditionDatabaseReady)
+	if ready == nil || ready.Status != corev1.ConditionFalse || !strings.Contains(ready.Message, first.Name) {
+		t.Errorf("DatabaseReady condition of %s is %+v, want False naming %s", second.Name, ready, first.Name)
+	}
+	if provisioner.Users[database] != password {
+		t.Errorf("the password of user %s was changed", database)
+	}
+	if second.Status.Database.Name != "" {
+		t.Errorf("status.database of %s is %+v, want none", second.Name, second.Status.Database)
+	}
+
+	// The refused Site leaves the database alone when it is deleted
+	reconcileDeletion(t, r, c, second)
+	if !provisioner.Databases[database] {
+		t.Errorf("database %s was dropped by Site %s", database, second.Name)
+	}
+	if _, ok := provisioner.Users[database]; !ok {
+		t.Errorf("user %s was dropped by Site %s", database, second.Name)
+	}
+}
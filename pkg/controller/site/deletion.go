@@ -0,0 +1,126 @@
+package site
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/backup"
+)
+
+const (
+	reasonSnapshotStarted      = "SnapshotStarted"
+	reasonSnapshotTaken        = "SnapshotTaken"
+	reasonSnapshotFailed       = "SnapshotFailed"
+	reasonDatabaseRetained     = "DatabaseRetained"
+	snapshotLocationAnnotation = fn.LabelPrefix + "backup-location"
+)
+
+func (rh *requestHandler) snapshotJobName() string {
+	return rh.site.Name + "-final-backup"
+}
+
+// finalizeSnapshot takes a final backup of the Site's database and files when its deletion policy is Snapshot, and
+// returns requeue=true until the backup Job has succeeded. If the backup can't be taken, an error is returned so that
+// the database isn't dropped. A failed backup Job is left in place; deleting it retries the backup.
+func (rh *requestHandler) finalizeSnapshot() (requeue bool, err error) {
+	if rh.site.DeletionPolicy() != fn.DeletionPolicySnapshot {
+		return false, nil
+	}
+
+	job := &batchv1.Job{}
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.snapshotJobName()}, job)
+	if err != nil && errors.IsNotFound(err) {
+		return rh.createSnapshotJob()
+	} else if err != nil {
+		return false, err
+	}
+
+	location := job.Annotations[snapshotLocationAnnotation]
+	switch JobOutcome(job) {
+	case fn.JobSucceeded:
+		rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeNormal, reasonSnapshotTaken,
+			"Final backup stored at %s", location)
+		return false, nil
+	case fn.JobFailed:
+		rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeWarning, reasonSnapshotFailed,
+			"Final backup Job %s failed; delete it to retry", job.Name)
+		return false, fmt.Errorf("final backup Job %s failed, so the database is kept; delete the Job to retry, or "+
+			"set spec.deletionPolicy to %s or %s", job.Name, fn.DeletionPolicyRetain, fn.DeletionPolicyDelete)
+	}
+	return true, nil
+}
+
+// createSnapshotJob starts the final backup of the Site, to the target of spec.backups
+func (rh *requestHandler) createSnapshotJob() (requeue bool, err error) {
+	backups := rh.site.Spec.Backups
+	if backups == nil {
+		return false, fmt.Errorf("spec.deletionPolicy %s requires spec.backups.target; set spec.deletionPolicy to %s "+
+			"or %s to delete the Site without a final backup", fn.DeletionPolicySnapshot, fn.DeletionPolicyRetain, fn.DeletionPolicyDelete)
+	}
+	if err := backups.Target.Validate(); err != nil {
+		return false, fmt.Errorf("invalid spec.backups: %v", err)
+	}
+
+	// The Site's parents aren't fetched when it is being deleted, but they are needed to build the Job
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.site.Spec.Environment}, rh.env)
+	if err != nil {
+		return false, fmt.Errorf("failed to get DrupalEnvironment %s for the final backup: %v", rh.site.Spec.Environment, err)
+	}
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: rh.env.Spec.Application}, rh.app)
+	if err != nil {
+		return false, fmt.Errorf("failed to get DrupalApplication %s for the final backup: %v", rh.env.Spec.Application, err)
+	}
+
+	archive := backup.Archive{
+		Site:     rh.site,
+		SiteURI:  rh.site.DrushURI(),
+		Env:      rh.env,
+		Method:   backups.Method.OrDefault(),
+		Target:   backups.Target,
+		Location: backup.Location(rh.site, "final-"+rh.site.GetDeletionTimestamp().UTC().Format("20060102T150405Z")),
+	}
+
+	labels := rh.site.ChildLabels()
+	labels["type"] = "backup"
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rh.snapshotJobName(),
+			Namespace: rh.site.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				snapshotLocationAnnotation: archive.Location,
+			},
+		},
+		Spec: archive.BackupJobSpec(rh.customerJobSpec(nil)),
+	}
+	rh.reconciler.associateResourceWithController(rh.logger, job, rh.site)
+
+	rh.logger.Info("Creating final backup Job", "Name", job.Name, "Location", archive.Location)
+	if err := rh.reconciler.client.Create(context.TODO(), job); err != nil {
+		return false, err
+	}
+	rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeNormal, reasonSnapshotStarted,
+		"Taking a final backup to %s before dropping the database", archive.Location)
+	return true, nil
+}
+
+// retainDatabase returns true if the Site's database and user are to be left in place when it is deleted, and records
+// that in an event.
+func (rh *requestHandler) retainDatabase() bool {
+	if rh.site.DeletionPolicy() != fn.DeletionPolicyRetain {
+		return false
+	}
+
+	rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeNormal, reasonDatabaseRetained,
+		"Database %s and user %s were retained in the cluster DB and ProxySQL, with the user's password in Secret %s",
+		rh.site.DatabaseName(), rh.site.DatabaseUser(), rh.site.Name+"-password")
+	return true
+}
@@ -0,0 +1,95 @@
+package site
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+const (
+	reasonDeployHookFailed    = "DeployHookFailed"
+	reasonDeployHookSucceeded = "DeployHookSucceeded"
+)
+
+// deployHookCommand brings the Site's database and configuration up to date with the code of a new release
+var deployHookCommand = []string{"/bin/sh", "-c", "drush updatedb --yes && drush config:import --yes && drush cache:rebuild"}
+
+// reconcileDeployHook runs the Site's deploy hook when its environment rolls out a new release, and records the outcome
+// in the Site's status, from which the DrupalEnvironment controller decides whether to promote or abort the release.
+// A failed hook isn't retried for the same release.
+func (rh *requestHandler) reconcileDeployHook() error {
+	hooks := rh.env.Status.DeployHooks
+	status := rh.status.DeployHook
+	if hooks != nil && hooks.Phase == fn.DeployHooksRunning && (status == nil || status.Release != hooks.Release) {
+		return rh.startDeployHook(hooks.Release)
+	}
+	if status == nil || status.Outcome != fn.JobRunning {
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: status.JobName}, job)
+	if err != nil && errors.IsNotFound(err) {
+		// The Job was deleted before it finished, so it is run again
+		return rh.startDeployHook(status.Release)
+	} else if err != nil {
+		return err
+	}
+
+	status.Outcome = JobOutcome(job)
+	switch status.Outcome {
+	case fn.JobSucceeded:
+		rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeNormal, reasonDeployHookSucceeded,
+			"Deploy hook Job %s succeeded for release %s", job.Name, status.Release)
+	case fn.JobFailed:
+		rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeWarning, reasonDeployHookFailed,
+			"Deploy hook Job %s failed for release %s", job.Name, status.Release)
+	}
+	return nil
+}
+
+// startDeployHook creates the deploy hook Job of a release, replacing the Job of the previous release, which is kept
+// until then for inspection
+func (rh *requestHandler) startDeployHook(release string) error {
+	jobObj := rh.deployHookJob(release)
+
+	if previous := rh.status.DeployHook; previous != nil && previous.JobName != jobObj.Name {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: rh.site.Namespace, Name: previous.JobName}}
+		bg := client.PropagationPolicy(metav1.DeletePropagationBackground)
+		if err := rh.reconciler.client.Delete(context.TODO(), job, bg); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	rh.reconciler.associateResourceWithController(rh.logger, &jobObj, rh.site)
+	rh.logger.Info("Creating deploy hook Job", "Name", jobObj.Name, "Release", release)
+	if err := rh.reconciler.client.Create(context.TODO(), &jobObj); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	rh.status.DeployHook = &fn.SiteDeployHookStatus{Release: release, JobName: jobObj.Name, Outcome: fn.JobRunning}
+	return nil
+}
+
+// deployHookJob returns the deploy hook Job of a release. It runs in the customer container of the environment, so
+// with the code of the release, and drush picks the Site from the DRUSH_OPTIONS_URI environment variable.
+func (rh *requestHandler) deployHookJob(release string) batchv1.Job {
+	job := rh.onDemandJob("deploy-hook", deployHookCommand)
+	job.Name = fmt.Sprintf("%s-deploy-%s", rh.site.Name, release)
+	job.Annotations["executable"] = "drush"
+	// Failed hooks are kept for inspection until the next release
+	job.Spec.TTLSecondsAfterFinished = nil
+
+	if uri := rh.site.DrushURI(); uri != "" {
+		container := &job.Spec.Template.Spec.Containers[0]
+		container.Env = append(container.Env, corev1.EnvVar{Name: "DRUSH_OPTIONS_URI", Value: uri})
+	}
+	return job
+}
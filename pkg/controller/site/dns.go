@@ -0,0 +1,154 @@
+package site
+
+import (
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/dns"
+)
+
+// reconcileDNS points the published domains of the Site that it doesn't share with other Sites at the load balancer of
+// its routing objects, and deletes the records of the other domains. The records are tracked in the Site's status, so that only records created
+// by the operator are ever deleted. Domains outside the zone of the DNS provider are left alone. It requeues until a
+// routing object of the Site has been given an address.
+func (rh *requestHandler) reconcileDNS() (requeue bool, err error) {
+	provider := rh.reconciler.dnsProvider
+	if provider == nil {
+		return false, nil
+	}
+
+	var domains []string
+	for _, domain := range rh.recordDomains {
+		if provider.Manages(domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	desired := map[string]fn.SiteDNSRecord{}
+	if len(domains) > 0 {
+		// All of the Site's routing objects receive traffic through the same load balancer or Gateway
+		published, err := rh.reconciler.observeRoutes(rh.logger, rh.site, rh.status.RedirectHosts)
+		if err != nil {
+			return false, err
+		}
+		var recordType dns.RecordType
+		var targets []string
+		for _, routes := range published {
+			if recordType, targets = loadBalancerTargets(routes.addresses); len(targets) > 0 {
+				break
+			}
+		}
+		if len(targets) == 0 {
+			rh.logger.Info("Waiting for the Ingress load balancer address to create DNS records")
+			return true, nil
+		}
+		for _, domain := range domains {
+			desired[domain] = fn.SiteDNSRecord{Name: domain, Type: string(recordType), Targets: targets}
+		}
+	}
+
+	// Records that fail to be deleted or replaced are kept in the status, to be retried
+	var records []fn.SiteDNSRecord
+	var firstErr error
+	current := map[string]fn.SiteDNSRecord{}
+	for _, record := range rh.status.DNSRecords {
+		current[record.Name] = record
+		if _, ok := desired[record.Name]; ok {
+			continue
+		}
+		rh.logger.Info("Deleting DNS record", "Name", record.Name)
+		if err := provider.DeleteRecord(record.Name); err != nil {
+			rh.logger.Error(err, "Failed to delete DNS record", "Name", record.Name)
+			records = append(records, record)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	for _, domain := range domains {
+		record := desired[domain]
+		if existing, ok := current[domain]; ok && sameRecord(existing, record) {
+			records = append(records, existing)
+			continue
+		}
+		rh.logger.Info("Updating DNS record", "Name", record.Name, "Type", record.Type, "Targets", record.Targets)
+		err := provider.EnsureRecord(dns.Record{Name: record.Name, Type: dns.RecordType(record.Type), Targets: record.Targets})
+		if err != nil {
+			rh.logger.Error(err, "Failed to update DNS record", "Name", record.Name)
+			if existing, ok := current[domain]; ok {
+				records = append(records, existing)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	rh.status.DNSRecords = records
+	return false, firstErr
+}
+
+// finalizeDNS deletes the DNS records created for the Site. Records are left in place if the operator no longer
+// manages DNS.
+func (rh *requestHandler) finalizeDNS() error {
+	provider := rh.reconciler.dnsProvider
+	if provider == nil {
+		if len(rh.status.DNSRecords) > 0 {
+			rh.logger.Info("Not deleting the Site's DNS records, as DNS_PROVIDER isn't set")
+			rh.status.DNSRecords = nil
+		}
+		return nil
+	}
+
+	for len(rh.status.DNSRecords) > 0 {
+		record := rh.status.DNSRecords[0]
+		rh.logger.Info("Deleting DNS record", "Name", record.Name)
+		if err := provider.DeleteRecord(record.Name); err != nil {
+			return err
+		}
+		rh.status.DNSRecords = rh.status.DNSRecords[1:]
+	}
+	rh.status.DNSRecords = nil
+	return nil
+}
+
+// loadBalancerTargets returns the IPv4 addresses of a load balancer as A record targets or, if it only has hostnames,
+// its first hostname as a CNAME target
+func loadBalancerTargets(addresses []corev1.LoadBalancerIngress) (dns.RecordType, []string) {
+	var ips []string
+	hostname := ""
+	for _, lb := range addresses {
+		if ip := net.ParseIP(lb.IP); ip != nil && ip.To4() != nil {
+			ips = append(ips, lb.IP)
+		} else if lb.Hostname != "" && hostname == "" {
+			hostname = lb.Hostname
+		}
+	}
+	if len(ips) > 0 {
+		sort.Strings(ips)
+		return dns.RecordA, ips
+	}
+	if hostname != "" {
+		return dns.RecordCNAME, []string{hostname}
+	}
+	return "", nil
+}
+
+func sameRecord(a, b fn.SiteDNSRecord) bool {
+	if a.Type != b.Type || len(a.Targets) != len(b.Targets) {
+		return false
+	}
+	for i := range a.Targets {
+		if a.Targets[i] != b.Targets[i] {
+			return false
+		}
+	}
+	return true
+}
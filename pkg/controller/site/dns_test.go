@@ -0,0 +1,146 @@
+package site
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	extv1b1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+	"github.com/acquia/fn-drupal-operator/pkg/dns"
+)
+
+// newDNSTestHandler returns a requestHandler for a Site whose Ingress has the given load balancer addresses, managing
+// DNS records in the zone example.com of a FileProvider
+func newDNSTestHandler(t *testing.T, addresses ...corev1.LoadBalancerIngress) (*requestHandler, *dns.FileProvider, func()) {
+	dir, err := ioutil.TempDir("", "dns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := dns.NewFileProvider(filepath.Join(dir, "zone"), "example.com", 300)
+
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Environment: "prod",
+			Domains:     []string{"wlgore.example.com", "www.example.com", "wlgore.example.org"},
+		},
+	}
+	ing := &extv1b1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: site.Name, Namespace: testNamespace},
+		Status:     extv1b1.IngressStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: addresses}},
+	}
+	r, _ := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site, ing)
+	r.dnsProvider = provider
+
+	rh := &requestHandler{
+		reconciler:    r,
+		site:          site,
+		status:        &site.Status,
+		logger:        log,
+		recordDomains: site.Spec.Domains,
+	}
+	return rh, provider, func() { os.RemoveAll(dir) }
+}
+
+// recordNames returns the names of the records tracked in the status
+func recordNames(records []fn.SiteDNSRecord) []string {
+	var names []string
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	return names
+}
+
+func TestReconcileDNSTracksRecords(t *testing.T) {
+	rh, provider, cleanup := newDNSTestHandler(t, corev1.LoadBalancerIngress{IP: "10.0.0.2"}, corev1.LoadBalancerIngress{IP: "10.0.0.1"})
+	defer cleanup()
+
+	// A record the operator didn't create, which it must leave alone
+	if err := provider.EnsureRecord(dns.Record{Name: "static.example.com", Type: dns.RecordA, Targets: []string{"10.0.0.9"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if requeue, err := rh.reconcileDNS(); requeue || err != nil {
+		t.Fatalf("reconcileDNS returned %t, %v", requeue, err)
+	}
+	// The domain outside the zone gets no record
+	if names, want := recordNames(rh.status.DNSRecords), []string{"wlgore.example.com", "www.example.com"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("status.dnsRecords are %v, want %v", names, want)
+	}
+	records, err := provider.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := records["wlgore.example.com"].Targets, []string{"10.0.0.1", "10.0.0.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wlgore.example.com points at %v, want %v", got, want)
+	}
+
+	// A domain the Site no longer publishes loses its record
+	rh.recordDomains = []string{"wlgore.example.com"}
+	if _, err := rh.reconcileDNS(); err != nil {
+		t.Fatal(err)
+	}
+	if names, want := recordNames(rh.status.DNSRecords), []string{"wlgore.example.com"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("status.dnsRecords are %v, want %v", names, want)
+	}
+	if records, err = provider.Records(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := records["www.example.com"]; ok {
+		t.Error("the record of www.example.com was not deleted")
+	}
+
+	if err := rh.finalizeDNS(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rh.status.DNSRecords) > 0 {
+		t.Errorf("status.dnsRecords are %v after finalizing", recordNames(rh.status.DNSRecords))
+	}
+	if records, err = provider.Records(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := records["static.example.com"]; len(records) != 1 || !ok {
+		t.Errorf("records after finalizing are %v, want only static.example.com", records)
+	}
+}
+
+func TestReconcileDNSWaitsForLoadBalancer(t *testing.T) {
+	rh, provider, cleanup := newDNSTestHandler(t)
+	defer cleanup()
+
+	requeue, err := rh.reconcileDNS()
+	if !requeue || err != nil {
+		t.Fatalf("reconcileDNS returned %t, %v, want a requeue", requeue, err)
+	}
+	records, err := provider.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) > 0 || len(rh.status.DNSRecords) > 0 {
+		t.Errorf("records were created without a load balancer address: %v", records)
+	}
+}
+
+func TestReconcileDNSUsesLoadBalancerHostname(t *testing.T) {
+	rh, provider, cleanup := newDNSTestHandler(t, corev1.LoadBalancerIngress{Hostname: "lb.example.net"})
+	defer cleanup()
+
+	if _, err := rh.reconcileDNS(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := provider.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dns.Record{Name: "wlgore.example.com", Type: dns.RecordCNAME, Targets: []string{"lb.example.net"}, TTL: 300}
+	if got := records["wlgore.example.com"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("wlgore.example.com record is %+v, want %+v", got, want)
+	}
+}
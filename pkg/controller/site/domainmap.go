@@ -33,9 +33,7 @@ func (cmdata ConfigMapData) Write() (map[string]string, error) {
 // location within it that only it will write to: the value at its Id.
 // This function then ensures that the value at its Id is reconciled with
 // what the Site dictates.
-func (cmdata *ConfigMapData) EnsureDomainMapPresence(s *fn.Site) bool {
-	id := s.Id()
-	desiredMap := s.DomainMap()
+func (cmdata *ConfigMapData) EnsureDomainMapPresence(id fn.SiteId, desiredMap fn.DomainMap) bool {
 	domains, ok := (*cmdata)[id]
 	if ok && reflect.DeepEqual(desiredMap, domains) {
 		return false
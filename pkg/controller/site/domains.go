@@ -0,0 +1,136 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+const (
+	reasonDomainConflict = "DomainConflict"
+	reasonNoConflict     = "NoConflict"
+)
+
+// A path of a domain can only be served by one Site. When several Sites in the cluster route the same path of a
+// domain, the one created first keeps the domain, and the others leave it out of their domain map entries and
+// Ingresses until it is released. Sites routing different paths of a domain share it, and the first of them manages
+// its DNS record. Redirect hosts can't be shared, and conflict with the domains of other Sites in the same way.
+
+// claimedBefore returns true if a has precedence over b for the domains they share
+func claimedBefore(a, b *fn.Site) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// reconcileDomainConflicts works out which of the Site's domains and redirects it may publish, and which of their DNS
+// records it manages, and sets the DomainConflict condition. An event naming the other Site is emitted whenever the conflicts change.
+func (rh *requestHandler) reconcileDomainConflicts() error {
+	sites := &fn.SiteList{}
+	if err := rh.reconciler.client.List(context.TODO(), &client.ListOptions{}, sites); err != nil {
+		return err
+	}
+
+	rh.domains, rh.redirects, rh.recordDomains = nil, nil, nil
+	conflicts := map[string]*fn.Site{}
+	for _, domain := range rh.site.Hosts() {
+		shared := false
+		for i := range sites.Items {
+			other := &sites.Items[i]
+			if other.UID == rh.site.UID || !claimedBefore(other, rh.site) {
+				continue
+			}
+			// The DNS record of a domain is only taken over once the Site managing it has been deleted, and its
+			// finalizer has deleted the record
+			shared = shared || other.HasHost(domain)
+			if other.GetDeletionTimestamp() == nil && rh.site.Conflicts(other, domain) {
+				conflicts[domain] = other
+				break
+			}
+		}
+		if conflicts[domain] == nil {
+			if redirect := rh.site.Redirect(domain); redirect != nil {
+				rh.redirects = append(rh.redirects, *redirect)
+			} else {
+				rh.domains = append(rh.domains, domain)
+			}
+			if !shared {
+				rh.recordDomains = append(rh.recordDomains, domain)
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		fn.SetCondition(&rh.status.Conditions, fn.ConditionDomainConflict, corev1.ConditionFalse, reasonNoConflict, "")
+		return nil
+	}
+
+	domains := make([]string, 0, len(conflicts))
+	for domain := range conflicts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	messages := make([]string, len(domains))
+	for i, domain := range domains {
+		other := conflicts[domain]
+		messages[i] = fmt.Sprintf("%s is used by Site %s/%s", domain, other.Namespace, other.Name)
+	}
+
+	changed := fn.SetCondition(&rh.status.Conditions, fn.ConditionDomainConflict, corev1.ConditionTrue, reasonDomainConflict,
+		"Not published: "+strings.Join(messages, "; "))
+	if changed {
+		for _, domain := range domains {
+			other := conflicts[domain]
+			rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeWarning, reasonDomainConflict,
+				"Domain %s is already used by Site %s/%s, so it isn't published", domain, other.Namespace, other.Name)
+		}
+	}
+	return nil
+}
+
+// sitesSharingDomains maps a Site to the other Sites listing any of its domains or redirect hosts, so that they can take
+// over a host it releases
+func sitesSharingDomains(c client.Client) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		site, ok := o.Object.(*fn.Site)
+		if !ok || len(site.Hosts()) == 0 {
+			return nil
+		}
+
+		sites := &fn.SiteList{}
+		if err := c.List(context.TODO(), &client.ListOptions{}, sites); err != nil {
+			log.Error(err, "Failed to list Sites sharing domains", "Site", site.Name, "Namespace", site.Namespace)
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for i := range sites.Items {
+			other := &sites.Items[i]
+			if other.UID == site.UID {
+				continue
+			}
+			for _, host := range site.Hosts() {
+				if other.HasHost(host) {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Namespace: other.Namespace, Name: other.Name},
+					})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
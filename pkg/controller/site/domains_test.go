@@ -0,0 +1,219 @@
+package site
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+// newDomainTestSite returns a Site created the given number of minutes after the others, routing the given paths of
+// its domains
+func newDomainTestSite(namespace, name string, minutes int, domains []string, paths ...string) *fn.Site {
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			UID:               types.UID(namespace + "/" + name),
+			CreationTimestamp: metav1.NewTime(time.Date(2019, 6, 1, 0, minutes, 0, 0, time.UTC)),
+		},
+		Spec: fn.SiteSpec{Environment: "prod", Domains: domains},
+	}
+	for _, path := range paths {
+		site.Spec.Routes = append(site.Spec.Routes, fn.SiteRoute{Path: path})
+	}
+	return site
+}
+
+func TestSiteConflicts(t *testing.T) {
+	redirecting := newDomainTestSite(testNamespace, "redirects", 0, []string{"wlgore.com"})
+	redirecting.Spec.Redirects = []fn.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com"}}
+
+	cases := []struct {
+		name  string
+		site  *fn.Site
+		other *fn.Site
+		host  string
+		want  bool
+	}{
+		{"same default route", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}),
+			newDomainTestSite(testNamespace, "b", 0, []string{"wlgore.com"}), "wlgore.com", true},
+		{"host of only one Site", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}),
+			newDomainTestSite(testNamespace, "b", 0, []string{"gore.com"}), "wlgore.com", false},
+		{"different paths", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}, "/"),
+			newDomainTestSite(testNamespace, "b", 0, []string{"wlgore.com"}, "/blog"), "wlgore.com", false},
+		{"same path spelled differently", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}, "/blog/"),
+			newDomainTestSite(testNamespace, "b", 0, []string{"wlgore.com"}, "/shop", "/blog"), "wlgore.com", true},
+		{"root spelled differently", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}),
+			newDomainTestSite(testNamespace, "b", 0, []string{"wlgore.com"}, "//"), "wlgore.com", true},
+		{"other Site's domain", newDomainTestSite(testNamespace, "a", 0, []string{"wlgore.com"}, "/blog"),
+			newDomainTestSite(testNamespace, "b", 0, []string{"wlgore.com", "www.wlgore.com"}, "/blog"), "www.wlgore.com", false},
+		{"redirect of the Site", redirecting,
+			newDomainTestSite(testNamespace, "b", 0, []string{"www.wlgore.com"}, "/blog"), "www.wlgore.com", true},
+		{"redirect of the other Site", newDomainTestSite(testNamespace, "b", 0, []string{"www.wlgore.com"}, "/blog"),
+			redirecting, "www.wlgore.com", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.site.Conflicts(c.other, c.host); got != c.want {
+				t.Errorf("Conflicts(%s) = %t, want %t", c.host, got, c.want)
+			}
+			if got := c.other.Conflicts(c.site, c.host); got != c.want {
+				t.Errorf("Conflicts(%s) of the other Site = %t, want %t", c.host, got, c.want)
+			}
+		})
+	}
+}
+
+func TestReconcileDomainConflicts(t *testing.T) {
+	deleting := newDomainTestSite(testNamespace, "old", -10, []string{"wlgore.com"})
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{"dns"}
+
+	cases := []struct {
+		name          string
+		domains       []string
+		redirects     []fn.SiteRedirect
+		others        []runtime.Object
+		wantDomains   []string
+		wantRedirects []string
+		wantRecords   []string
+		wantMessage   string
+	}{
+		{
+			name:        "no other Sites",
+			domains:     []string{"wlgore.com", "www.wlgore.com"},
+			wantDomains: []string{"wlgore.com", "www.wlgore.com"},
+			wantRecords: []string{"wlgore.com", "www.wlgore.com"},
+		},
+		{
+			name:        "older Site routing the domain",
+			domains:     []string{"wlgore.com", "www.wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "old", -10, []string{"www.wlgore.com"})},
+			wantDomains: []string{"wlgore.com"},
+			wantRecords: []string{"wlgore.com"},
+			wantMessage: "Not published: www.wlgore.com is used by Site wlgore/old",
+		},
+		{
+			name:        "older Site of another namespace",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite("gore", "old", -10, []string{"wlgore.com"})},
+			wantMessage: "Not published: wlgore.com is used by Site gore/old",
+		},
+		{
+			name:        "newer Site routing the domain",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "new", 10, []string{"wlgore.com"})},
+			wantDomains: []string{"wlgore.com"},
+			wantRecords: []string{"wlgore.com"},
+		},
+		{
+			name:        "Site created at the same time with a name sorting first",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "a-site", 0, []string{"wlgore.com"})},
+			wantMessage: "Not published: wlgore.com is used by Site wlgore/a-site",
+		},
+		{
+			name:        "Site created at the same time with a name sorting last",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "z-site", 0, []string{"wlgore.com"})},
+			wantDomains: []string{"wlgore.com"},
+			wantRecords: []string{"wlgore.com"},
+		},
+		{
+			// The domain is published again, but its DNS record is left to the older Site's finalizer
+			name:        "older Site being deleted",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{deleting},
+			wantDomains: []string{"wlgore.com"},
+		},
+		{
+			name:        "older Site routing another path",
+			domains:     []string{"wlgore.com"},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "old", -10, []string{"wlgore.com"}, "/blog")},
+			wantDomains: []string{"wlgore.com"},
+		},
+		{
+			name:        "redirect host routed by an older Site",
+			domains:     []string{"wlgore.com"},
+			redirects:   []fn.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com"}},
+			others:      []runtime.Object{newDomainTestSite(testNamespace, "old", -10, []string{"www.wlgore.com"}, "/blog")},
+			wantDomains: []string{"wlgore.com"},
+			wantRecords: []string{"wlgore.com"},
+			wantMessage: "Not published: www.wlgore.com is used by Site wlgore/old",
+		},
+		{
+			name:          "redirect host of a newer Site",
+			domains:       []string{"wlgore.com"},
+			redirects:     []fn.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com"}},
+			others:        []runtime.Object{newDomainTestSite(testNamespace, "new", 10, []string{"www.wlgore.com"})},
+			wantDomains:   []string{"wlgore.com"},
+			wantRedirects: []string{"www.wlgore.com"},
+			wantRecords:   []string{"wlgore.com", "www.wlgore.com"},
+		},
+		{
+			name:    "several conflicts",
+			domains: []string{"wlgore.com", "www.wlgore.com", "shop.wlgore.com"},
+			others: []runtime.Object{
+				newDomainTestSite(testNamespace, "shop", -5, []string{"shop.wlgore.com"}),
+				newDomainTestSite(testNamespace, "old", -10, []string{"www.wlgore.com"}),
+			},
+			wantDomains: []string{"wlgore.com"},
+			wantRecords: []string{"wlgore.com"},
+			wantMessage: "Not published: shop.wlgore.com is used by Site wlgore/shop; www.wlgore.com is used by Site wlgore/old",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			site := newDomainTestSite(testNamespace, "wlgore", 0, c.domains)
+			site.Spec.Redirects = c.redirects
+			r, _ := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), append(c.others, site)...)
+			rh := &requestHandler{reconciler: r, site: site, status: &site.Status, logger: log}
+
+			if err := rh.reconcileDomainConflicts(); err != nil {
+				t.Fatal(err)
+			}
+
+			var redirects []string
+			for _, redirect := range rh.redirects {
+				redirects = append(redirects, redirect.FromHost)
+			}
+			if !reflect.DeepEqual(rh.domains, c.wantDomains) || !reflect.DeepEqual(redirects, c.wantRedirects) {
+				t.Errorf("published domains %v and redirects %v, want %v and %v", rh.domains, redirects, c.wantDomains, c.wantRedirects)
+			}
+			if !reflect.DeepEqual(rh.recordDomains, c.wantRecords) {
+				t.Errorf("manages the DNS records of %v, want %v", rh.recordDomains, c.wantRecords)
+			}
+
+			condition := fn.GetCondition(site.Status.Conditions, fn.ConditionDomainConflict)
+			wantStatus := corev1.ConditionFalse
+			if c.wantMessage != "" {
+				wantStatus = corev1.ConditionTrue
+			}
+			if condition == nil || condition.Status != wantStatus || condition.Message != c.wantMessage {
+				t.Errorf("DomainConflict condition is %+v, want %s with message %q", condition, wantStatus, c.wantMessage)
+			}
+
+			// An event is emitted for each conflicting host, once
+			events := r.recorder.(*record.FakeRecorder).Events
+			if err := rh.reconcileDomainConflicts(); err != nil {
+				t.Fatal(err)
+			}
+			wantEvents := 0
+			if c.wantMessage != "" {
+				wantEvents = len(c.domains) + len(c.redirects) - len(c.wantDomains) - len(c.wantRedirects)
+			}
+			if len(events) != wantEvents {
+				t.Errorf("%d events were emitted, want %d", len(events), wantEvents)
+			}
+		})
+	}
+}
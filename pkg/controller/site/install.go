@@ -0,0 +1,188 @@
+package site
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+const (
+	adminPasswordKey    = "password"
+	adminPasswordEnvVar = "ADMIN_PASSWORD"
+
+	reasonInstallRunning   = "InstallRunning"
+	reasonInstallSucceeded = "InstallSucceeded"
+	reasonInstallFailed    = "InstallFailed"
+	reasonAlreadyInstalled = "AlreadyInstalled"
+)
+
+func (rh *requestHandler) installRequested() bool {
+	return rh.site.Spec.Install.InstallProfile != ""
+}
+
+func (rh *requestHandler) installJobName() string {
+	return rh.site.Name + "-install"
+}
+
+func (rh *requestHandler) adminPasswordSecretName() string {
+	return rh.site.Name + "-admin-password"
+}
+
+// installJob builds the Job that runs "drush site-install" with the profile and admin account from spec.install.
+// The admin password is passed in from the admin password Secret, so that it never appears in the Job spec.
+func (rh *requestHandler) installJob() batchv1.Job {
+	install := rh.site.Spec.Install
+	backoffLimit := int32(2)
+
+	command := []string{
+		"drush", "site-install", install.InstallProfile,
+		"--yes",
+		"--account-name=" + install.AdminUsername,
+		"--account-mail=" + install.AdminEmail,
+		fmt.Sprintf("--account-pass=$(%s)", adminPasswordEnvVar),
+	}
+	// Drush picks the multisite to install from the domain map, based on the URI
+	if uri := rh.site.DrushURI(); uri != "" {
+		command = append(command, "--uri="+uri)
+	}
+
+	spec := rh.customerJobSpec(command)
+	spec.BackoffLimit = &backoffLimit
+	spec.Template.Spec.Containers[0].Env = append(spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name: adminPasswordEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: rh.adminPasswordSecretName()},
+				Key:                  adminPasswordKey,
+			},
+		},
+	})
+
+	labels := rh.site.ChildLabels()
+	labels["type"] = "install"
+
+	return batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rh.installJobName(),
+			Namespace: rh.site.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				"executable": command[0],
+			},
+		},
+		Spec: spec,
+	}
+}
+
+// reconcileAdminPasswordSecret generates the password of the Drupal admin account, if it doesn't exist yet.
+func (rh *requestHandler) reconcileAdminPasswordSecret() (requeue bool, err error) {
+	secret := &corev1.Secret{}
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.adminPasswordSecretName()}, secret)
+	if err == nil {
+		return false, nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	password, err := common.RandPassword()
+	if err != nil {
+		return false, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rh.adminPasswordSecretName(),
+			Namespace: rh.site.Namespace,
+			Labels:    rh.site.ChildLabels(),
+		},
+		StringData: map[string]string{
+			adminPasswordKey: password,
+		},
+		Type: "Opaque",
+	}
+	rh.reconciler.associateResourceWithController(rh.logger, secret, rh.site)
+
+	rh.logger.Info("Creating admin password Secret", "Name", secret.Name)
+	if err := rh.reconciler.client.Create(context.TODO(), secret); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reconcileInstall runs the install Job once the Site's database is ready, and records its outcome in status.
+// Once the install has succeeded it is never run again, because that would wipe the site's database. For the same
+// reason, a Site whose database already has tables, such as one that was running before spec.install was set, is
+// recorded as installed rather than installed again. A failed install Job is left in place so its logs can be
+// inspected; deleting it causes the install to be retried.
+func (rh *requestHandler) reconcileInstall() (requeue bool, err error) {
+	if !rh.installRequested() || rh.status.Install.Outcome == fn.JobSucceeded ||
+		fn.IsConditionTrue(rh.status.Conditions, fn.ConditionInstalled) {
+		return false, nil
+	}
+
+	job := &batchv1.Job{}
+	err = rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.installJobName()}, job)
+	if err != nil && errors.IsNotFound(err) {
+		tables, err := rh.reconciler.newProvisioner(rh.site.Namespace).ListTables(rh.site.DatabaseName())
+		if err != nil {
+			if common.IsUnavailable(err) {
+				rh.logger.Info("Database is unavailable", "Error", err.Error())
+				return true, nil
+			}
+			return false, err
+		}
+		if len(tables) > 0 {
+			rh.logger.Info("Not installing the Site, as its database isn't empty", "Database", rh.site.DatabaseName())
+			fn.SetCondition(&rh.status.Conditions, fn.ConditionInstalled, corev1.ConditionTrue, reasonAlreadyInstalled,
+				fmt.Sprintf("Database %s already has %d tables", rh.site.DatabaseName(), len(tables)))
+			return false, nil
+		}
+
+		if requeue, err := rh.reconcileAdminPasswordSecret(); requeue || err != nil {
+			return requeue, err
+		}
+
+		newJob := rh.installJob()
+		rh.reconciler.associateResourceWithController(rh.logger, &newJob, rh.site)
+
+		rh.logger.Info("Creating install Job", "Name", newJob.Name, "Profile", rh.site.Spec.Install.InstallProfile)
+		if err := rh.reconciler.client.Create(context.TODO(), &newJob); err != nil {
+			return false, err
+		}
+
+		rh.status.Install = fn.SiteInstallStatus{
+			JobName:             newJob.Name,
+			Outcome:             fn.JobRunning,
+			AdminPasswordSecret: rh.adminPasswordSecretName(),
+		}
+		fn.SetCondition(&rh.status.Conditions, fn.ConditionInstalled, corev1.ConditionFalse, reasonInstallRunning, "")
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	outcome := JobOutcome(job)
+	rh.status.Install.JobName = job.Name
+	rh.status.Install.Outcome = outcome
+	rh.status.Install.AdminPasswordSecret = rh.adminPasswordSecretName()
+	rh.status.Install.CompletionTime = job.Status.CompletionTime
+
+	switch outcome {
+	case fn.JobSucceeded:
+		rh.logger.Info("Site install succeeded", "Job", job.Name)
+		fn.SetCondition(&rh.status.Conditions, fn.ConditionInstalled, corev1.ConditionTrue, reasonInstallSucceeded, "")
+	case fn.JobFailed:
+		fn.SetCondition(&rh.status.Conditions, fn.ConditionInstalled, corev1.ConditionFalse, reasonInstallFailed,
+			fmt.Sprintf("Job %s failed; delete it to retry the install", job.Name))
+	default:
+		fn.SetCondition(&rh.status.Conditions, fn.ConditionInstalled, corev1.ConditionFalse, reasonInstallRunning, "")
+	}
+	return false, nil
+}
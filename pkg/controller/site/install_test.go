@@ -0,0 +1,84 @@
+package site
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+func newInstallTestSite() *fn.Site {
+	return &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Environment: "prod",
+			Domains:     []string{"wlgore.example.com"},
+			Install: fn.InstallSpec{
+				InstallProfile: "standard",
+				AdminUsername:  "admin",
+				AdminEmail:     "admin@example.com",
+			},
+		},
+	}
+}
+
+func TestReconcileInstallsEmptyDatabase(t *testing.T) {
+	site := newInstallTestSite()
+	r, c := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site)
+
+	reconcileUntilDone(t, r, site.Name)
+
+	job := &batchv1.Job{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name + "-install"}, job); err != nil {
+		t.Fatalf("the install Job wasn't created: %v", err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name}, site); err != nil {
+		t.Fatal(err)
+	}
+	if site.Status.Install.Outcome != fn.JobRunning {
+		t.Errorf("status.install.outcome is %q, want %q", site.Status.Install.Outcome, fn.JobRunning)
+	}
+}
+
+func TestReconcileNeverReinstallsPopulatedDatabase(t *testing.T) {
+	site := newInstallTestSite()
+	provisioner := common.NewFakeDatabaseProvisioner()
+	// A Site that was running before spec.install was added has no install status yet
+	provisioner.Databases[site.DatabaseName()] = true
+	provisioner.Tables[site.DatabaseName()] = map[string]bool{"key_value": true, "users": true}
+	r, c := newTestReconciler(t, provisioner, site)
+
+	reconcileUntilDone(t, r, site.Name)
+
+	job := &batchv1.Job{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name + "-install"}, job)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("an install Job was created for a populated database: %v", err)
+	}
+	if len(provisioner.Tables[site.DatabaseName()]) != 2 {
+		t.Errorf("the tables of the database were changed: %v", provisioner.Tables[site.DatabaseName()])
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name}, site); err != nil {
+		t.Fatal(err)
+	}
+	installed := fn.GetCondition(site.Status.Conditions, fn.ConditionInstalled)
+	if installed == nil || installed.Status != corev1.ConditionTrue || installed.Reason != reasonAlreadyInstalled {
+		t.Errorf("Installed condition is %+v, want True with reason %s", installed, reasonAlreadyInstalled)
+	}
+
+	// Later reconciles don't look at the database again, even once it is emptied
+	provisioner.Tables[site.DatabaseName()] = nil
+	reconcileUntilDone(t, r, site.Name)
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name + "-install"}, job)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("an install Job was created for an installed Site: %v", err)
+	}
+}
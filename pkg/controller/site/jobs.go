@@ -18,6 +18,8 @@ import (
 	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	environ "github.com/acquia/fn-drupal-operator/pkg/controller/drupalenvironment"
 	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
 )
 
 // The common interface for all types of Jobs that can be run on a Site
@@ -40,12 +42,17 @@ func (job *CustomerJob) Label() string { return fn.LabelPrefix + "runJob" }
 func (job *RootJob) Label() string     { return fn.LabelPrefix + "runRootJob" }
 
 func (rh *requestHandler) customerJobSpec(command []string) batchv1.JobSpec {
+	return CustomerJobSpec(rh.app, rh.env, rh.site, command)
+}
+
+// CustomerJobSpec returns the spec of a Job running the given command in the customer container of a Site
+func CustomerJobSpec(app *fn.DrupalApplication, env *fn.DrupalEnvironment, site *fn.Site, command []string) batchv1.JobSpec {
 	completions := int32(1)
 	// TODO: Needs to be able to be set by user
 	activeDeadlineSeconds := int64(3600) // job has one hour to complete or it will be killed
 	// ttlSecondsAfterFinished := int32(300)
 
-	customerContainer := customercontainer.Template(rh.app, rh.env)
+	customerContainer := customercontainer.Template(app, env)
 	customerContainer.Command = command
 	customerContainer.Name = "main"
 
@@ -58,20 +65,19 @@ func (rh *requestHandler) customerJobSpec(command []string) batchv1.JobSpec {
 
 		Template: v1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: rh.site.ChildLabels(),
+				Labels: site.ChildLabels(),
 			},
 			Spec: v1.PodSpec{
 				RestartPolicy: v1.RestartPolicyOnFailure,
 				Containers: []v1.Container{
 					customerContainer,
 				},
-				NodeSelector: map[string]string{
-					"function": "workers",
-				},
+				NodeSelector:     operatorconfig.Current().NodeSelector,
+				ImagePullSecrets: registry.Current().PullSecrets(),
 				Volumes: []v1.Volume{
 					environ.PhpConfigVolume(),
 					environ.DomainMapSecretVolume(),
-					customercontainer.FilesVolume(rh.env),
+					customercontainer.FilesVolume(env),
 				},
 				TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
 			},
@@ -170,10 +176,16 @@ func (job *RootJob) GetJob(rh *requestHandler, command []string) batchv1.Job {
 	return rootJob
 }
 
+// GetOwnedCrons returns the CronJobs of the Site's spec.crons
 func (rh *requestHandler) GetOwnedCrons() (*batchv1b1.CronJobList, error) {
+	return rh.getOwnedCronJobs("cron")
+}
+
+// getOwnedCronJobs returns the Site's CronJobs with the given "type" label
+func (rh *requestHandler) getOwnedCronJobs(cronType string) (*batchv1b1.CronJobList, error) {
 	cronList := &batchv1b1.CronJobList{}
 	listOpts := client.InNamespace(rh.site.Namespace).MatchingLabels(
-		map[string]string{fn.SiteIdLabel: string(rh.site.Id())},
+		map[string]string{fn.SiteIdLabel: string(rh.site.Id()), "type": cronType},
 	)
 	if err := rh.reconciler.client.List(context.TODO(), listOpts, cronList); err != nil {
 		return nil, err
@@ -240,6 +252,10 @@ func (rh *requestHandler) reconcileCronJobs() (requeue bool, err error) {
 			realCronSpec.Schedule = newSpec.Schedule
 			realCronSpec.ConcurrencyPolicy = newSpec.ConcurrencyPolicy
 			realCronSpec.JobTemplate.Spec.Template.Spec.Containers[0].Command = cron.Command
+			// Keeps the cron running the image of the environment's current release
+			realCronSpec.JobTemplate.Spec.Template.Spec.Containers[0].Image = newSpec.JobTemplate.Spec.Template.Spec.Containers[0].Image
+			realCronSpec.JobTemplate.Spec.Template.Spec.ImagePullSecrets = newSpec.JobTemplate.Spec.Template.Spec.ImagePullSecrets
+			realCronSpec.JobTemplate.Spec.Template.Spec.NodeSelector = newSpec.JobTemplate.Spec.Template.Spec.NodeSelector
 
 			realCronSpec.StartingDeadlineSeconds = newSpec.StartingDeadlineSeconds
 
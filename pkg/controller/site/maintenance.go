@@ -0,0 +1,395 @@
+package site
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"html"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+const (
+	maintenancePort           = 8080
+	maintenanceConfigKey      = "default.conf"
+	maintenancePageKey        = "maintenance.html"
+	maintenanceConfigHashAnno = fn.LabelPrefix + "maintenance-config-hash"
+
+	reasonMaintenanceEnabled   = "MaintenanceEnabled"
+	reasonMaintenanceDisabled  = "MaintenanceDisabled"
+	reasonMaintenanceJobFailed = "MaintenanceJobFailed"
+)
+
+// The maintenance responder is an NGINX server in front of Drupal. The client address is taken from the last entry of
+// X-Forwarded-For, which is the one the ingress controller or Gateway adds. Allowed clients are passed on to Drupal,
+// and the others get the maintenance page with a 503 status.
+const maintenanceNginxConfig = `geo $maintenance_allowed {
+    default 0;
+%s}
+
+server {
+    listen %d;
+    set_real_ip_from 0.0.0.0/0;
+    set_real_ip_from ::/0;
+    real_ip_header X-Forwarded-For;
+
+    error_page 503 /maintenance.html;
+
+    location / {
+        if ($maintenance_allowed = 0) {
+            return 503;
+        }
+        proxy_pass http://%s:%d;
+        proxy_set_header Host $host;
+        proxy_set_header X-Forwarded-For $http_x_forwarded_for;
+        proxy_set_header X-Forwarded-Proto $http_x_forwarded_proto;
+    }
+
+    location = /maintenance.html {
+        internal;
+        root /usr/share/nginx/maintenance;
+        add_header Retry-After 300 always;
+        add_header Cache-Control no-store always;
+    }
+}
+`
+
+const maintenancePage = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Under maintenance</title></head>
+<body><p>%s</p></body>
+</html>
+`
+
+const defaultMaintenanceMessage = "This site is under maintenance. Please check back shortly."
+
+// maintenance returns the maintenance mode in effect for the Site, which is its own spec.maintenance or else that of
+// its environment, and where it comes from. It returns nil if the Site isn't in maintenance mode.
+func (rh *requestHandler) maintenance() (*fn.MaintenanceSpec, fn.MaintenanceSource) {
+	if rh.site.Spec.Maintenance != nil {
+		return rh.site.Spec.Maintenance, fn.MaintenanceSourceSite
+	}
+	if rh.env.Spec.Maintenance != nil {
+		return rh.env.Spec.Maintenance, fn.MaintenanceSourceEnvironment
+	}
+	return nil, ""
+}
+
+// routes returns the routes of the Site, with those served by Drupal sent to the maintenance responder while the Site
+// is in maintenance mode
+func (rh *requestHandler) routes() []fn.SiteRoute {
+	routes := rh.site.Routes()
+	if !rh.status.Maintenance.Enabled {
+		return routes
+	}
+	for i := range routes {
+		if routes[i].Target.ServiceName == fn.DrupalServiceName {
+			routes[i].Target = fn.RouteTarget{ServiceName: rh.site.MaintenanceResponderName(), ServicePort: intstr.FromInt(80)}
+		}
+	}
+	return routes
+}
+
+// reconcileMaintenance puts the Site in or out of maintenance mode. Going in, the maintenance responder is started, the
+// Site's Drupal routes are switched to it once it is available, and Drupal's maintenance mode is turned on by a root
+// Job. Going out, Drupal's maintenance mode is turned off before the routes are switched back. It returns requeue=true
+// while waiting for the responder.
+func (rh *requestHandler) reconcileMaintenance() (requeue bool, err error) {
+	status := &rh.status.Maintenance
+	spec, source := rh.maintenance()
+	if spec != nil {
+		status.Source = source
+		available, err := rh.reconcileMaintenanceResponder(spec)
+		if err != nil {
+			return false, err
+		}
+		if !available {
+			rh.logger.Info("Waiting for the maintenance responder to become available")
+			return true, nil
+		}
+		if !status.Enabled {
+			status.Enabled = true
+			rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeNormal, reasonMaintenanceEnabled,
+				"Maintenance mode enabled by the %s", source)
+		}
+		if !status.DrupalMaintenanceMode {
+			return false, rh.setDrupalMaintenanceMode(true)
+		}
+		return false, nil
+	}
+
+	// The Site stays behind the responder until Drupal is out of maintenance mode
+	if status.DrupalMaintenanceMode {
+		return false, rh.setDrupalMaintenanceMode(false)
+	}
+	if status.Enabled {
+		rh.reconciler.recorder.Event(rh.site, corev1.EventTypeNormal, reasonMaintenanceDisabled, "Maintenance mode disabled")
+	}
+	status.Enabled = false
+	status.Source = ""
+	return false, nil
+}
+
+// setDrupalMaintenanceMode runs drush state:set system.maintenance_mode through a root Job, and records the mode once
+// the Job succeeds. The Job is watched, so this is called again when it finishes. A succeeded Job is deleted, since the
+// next transition to the same mode runs a Job of the same name.
+func (rh *requestHandler) setDrupalMaintenanceMode(enabled bool) error {
+	status := &rh.status.Maintenance
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	command := []string{"drush", "state:set", "system.maintenance_mode", value, "--input-format=integer"}
+	if uri := rh.site.DrushURI(); uri != "" {
+		command = append(command, "--uri="+uri)
+	}
+	jobObj := (&RootJob{}).GetJob(rh, command)
+
+	job := &batchv1.Job{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: jobObj.Namespace, Name: jobObj.Name}, job)
+	if err != nil && errors.IsNotFound(err) {
+		rh.reconciler.associateResourceWithController(rh.logger, &jobObj, rh.site)
+		rh.logger.Info("Creating Job setting Drupal's maintenance mode", "Name", jobObj.Name, "Mode", value)
+		if err := rh.reconciler.client.Create(context.TODO(), &jobObj); err != nil {
+			return err
+		}
+		status.JobName = jobObj.Name
+		status.JobOutcome = fn.JobRunning
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	status.JobName = job.Name
+	status.JobOutcome = JobOutcome(job)
+	switch status.JobOutcome {
+	case fn.JobSucceeded:
+		status.DrupalMaintenanceMode = enabled
+		bg := client.PropagationPolicy(metav1.DeletePropagationBackground)
+		if err := rh.reconciler.client.Delete(context.TODO(), job, bg); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	case fn.JobFailed:
+		rh.reconciler.recorder.Eventf(rh.site, corev1.EventTypeWarning, reasonMaintenanceJobFailed,
+			"Job %s failed to set Drupal's maintenance mode to %s", job.Name, value)
+		return fmt.Errorf("Job %s failed to set Drupal's maintenance mode to %s; it is retried once the Job is removed", job.Name, value)
+	}
+	return nil
+}
+
+// reconcileMaintenanceResponder creates or updates the ConfigMap, Deployment and Service of the Site's maintenance
+// responder, and returns true once the responder is available
+func (rh *requestHandler) reconcileMaintenanceResponder(spec *fn.MaintenanceSpec) (available bool, err error) {
+	name := rh.site.MaintenanceResponderName()
+	labels := rh.site.ChildLabels()
+	labels["type"] = "maintenance"
+
+	message := spec.Message
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+	var allowed bytes.Buffer
+	for _, ip := range spec.AllowedIPs {
+		fmt.Fprintf(&allowed, "    %s 1;\n", ip)
+	}
+	data := map[string]string{
+		maintenanceConfigKey: fmt.Sprintf(maintenanceNginxConfig, allowed.String(), maintenancePort, fn.DrupalServiceName, fn.DrupalServicePort),
+		maintenancePageKey:   fmt.Sprintf(maintenancePage, html.EscapeString(message)),
+	}
+	configHash := fmt.Sprintf("%x", sha256.Sum256([]byte(data[maintenanceConfigKey]+data[maintenancePageKey])))
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: rh.site.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), rh.reconciler.client, cm, func(existing runtime.Object) error {
+		realCM := existing.(*corev1.ConfigMap)
+		if realCM.CreationTimestamp.IsZero() {
+			realCM.Labels = labels
+			rh.reconciler.associateResourceWithController(rh.logger, realCM, rh.site)
+		}
+		realCM.Data = data
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: rh.site.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), rh.reconciler.client, dep, func(existing runtime.Object) error {
+		realDEP := existing.(*appsv1.Deployment)
+		desired := rh.maintenanceDeployment(name, labels, configHash)
+
+		if realDEP.CreationTimestamp.IsZero() {
+			desired.DeepCopyInto(realDEP)
+			rh.reconciler.associateResourceWithController(rh.logger, realDEP, rh.site)
+			return nil
+		}
+		realDEP.Spec.Template.Annotations = desired.Spec.Template.Annotations
+		realDEP.Spec.Template.Spec.ImagePullSecrets = desired.Spec.Template.Spec.ImagePullSecrets
+		realDEP.Spec.Template.Spec.NodeSelector = desired.Spec.Template.Spec.NodeSelector
+		realDEP.Spec.Template.Spec.Containers[0].Image = desired.Spec.Template.Spec.Containers[0].Image
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: rh.site.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(context.TODO(), rh.reconciler.client, svc, func(existing runtime.Object) error {
+		realSVC := existing.(*corev1.Service)
+		if realSVC.CreationTimestamp.IsZero() {
+			realSVC.Labels = labels
+			rh.reconciler.associateResourceWithController(rh.logger, realSVC, rh.site)
+		}
+		realSVC.Spec.Selector = labels
+		realSVC.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Port:       80,
+			TargetPort: intstr.FromInt(maintenancePort),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return dep.Status.AvailableReplicas > 0 && dep.Status.ObservedGeneration >= dep.Generation, nil
+}
+
+func (rh *requestHandler) maintenanceDeployment(name string, labels map[string]string, configHash string) *appsv1.Deployment {
+	replicas := int32(1)
+	accessMode := int32(420)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: rh.site.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						maintenanceConfigHashAnno: configHash,
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector:     operatorconfig.Current().NodeSelector,
+					ImagePullSecrets: registry.Current().PullSecrets(),
+					Containers: []corev1.Container{
+						{
+							Name:            "maintenance",
+							Image:           registry.Current().MaintenanceImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Ports: []corev1.ContainerPort{
+								{
+									ContainerPort: maintenancePort,
+									Name:          "http",
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(maintenancePort)},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "maintenance",
+									MountPath: "/etc/nginx/conf.d/" + maintenanceConfigKey,
+									SubPath:   maintenanceConfigKey,
+								},
+								{
+									Name:      "maintenance",
+									MountPath: "/usr/share/nginx/maintenance/" + maintenancePageKey,
+									SubPath:   maintenancePageKey,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "maintenance",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: name},
+									DefaultMode:          &accessMode,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// removeMaintenanceResponder deletes the maintenance responder of a Site that is out of maintenance mode, once its
+// routes have been switched back to Drupal
+func (rh *requestHandler) removeMaintenanceResponder() error {
+	if rh.status.Maintenance.Enabled {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: rh.site.Namespace, Name: rh.site.MaintenanceResponderName()}
+	for _, obj := range []runtime.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.ConfigMap{}} {
+		err := rh.reconciler.client.Get(context.TODO(), key, obj)
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if o, ok := obj.(metav1.Object); ok && !metav1.IsControlledBy(o, rh.site) {
+			continue
+		}
+		rh.logger.Info("Deleting maintenance responder", "Kind", fmt.Sprintf("%T", obj), "Name", key.Name)
+		if err := rh.reconciler.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sitesOfEnvironment maps a DrupalEnvironment to its Sites, which follow its maintenance mode
+func sitesOfEnvironment(c client.Client) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		env, ok := o.Object.(*fn.DrupalEnvironment)
+		if !ok {
+			return nil
+		}
+
+		sites := &fn.SiteList{}
+		if err := c.List(context.TODO(), client.InNamespace(env.Namespace), sites); err != nil {
+			log.Error(err, "Failed to list Sites of environment", "Environment", env.Name, "Namespace", env.Namespace)
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, site := range sites.Items {
+			if site.Spec.Environment == env.Name {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: site.Namespace, Name: site.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,294 @@
+package site
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1b1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+// newMaintenanceTestHandler returns a requestHandler for a Site of the prod environment, routing / to Drupal and
+// /static to another Service
+func newMaintenanceTestHandler(t *testing.T) (*requestHandler, client.Client) {
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Environment: "prod",
+			Domains:     []string{"wlgore.example.com"},
+			Routes: []fn.SiteRoute{
+				{Path: "/"},
+				{Path: "/static", Target: fn.RouteTarget{ServiceName: "static", ServicePort: intstr.FromInt(8080)}},
+			},
+		},
+	}
+	site.SetId("0f6e9a3c-5b1d-4c8e-9f2a-7d3b6c1e8a44")
+	r, c := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site)
+
+	rh := &requestHandler{
+		reconciler: r,
+		site:       site,
+		status:     &site.Status,
+		logger:     log,
+		app:        &fn.DrupalApplication{},
+		env:        &fn.DrupalEnvironment{},
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "prod"}, rh.env); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: rh.env.Spec.Application}, rh.app); err != nil {
+		t.Fatal(err)
+	}
+	return rh, c
+}
+
+// makeResponderAvailable sets the status of the maintenance responder's Deployment as its controller would, and its
+// creation timestamp as the API server would, which the fake client doesn't
+func makeResponderAvailable(t *testing.T, rh *requestHandler, c client.Client) {
+	dep := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: testNamespace, Name: rh.site.MaintenanceResponderName()}
+	if err := c.Get(context.TODO(), key, dep); err != nil {
+		t.Fatal(err)
+	}
+	dep.CreationTimestamp = metav1.Now()
+	dep.Status.AvailableReplicas = 1
+	dep.Status.ObservedGeneration = dep.Generation
+	if err := c.Update(context.TODO(), dep); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// completeMaintenanceJob marks the Job setting Drupal's maintenance mode as succeeded
+func completeMaintenanceJob(t *testing.T, rh *requestHandler, c client.Client) {
+	job := &batchv1.Job{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: rh.status.Maintenance.JobName}, job); err != nil {
+		t.Fatal(err)
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if err := c.Update(context.TODO(), job); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// maintenanceJobMode returns the mode set by the Job running for the Site, or "" if there is none
+func maintenanceJobMode(t *testing.T, rh *requestHandler, c client.Client) string {
+	if rh.status.Maintenance.JobName == "" {
+		return ""
+	}
+	job := &batchv1.Job{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: rh.status.Maintenance.JobName}, job)
+	if errors.IsNotFound(err) {
+		return ""
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	command := job.Spec.Template.Spec.Containers[0].Command
+	for i, arg := range command {
+		if arg == "system.maintenance_mode" {
+			return command[i+1]
+		}
+	}
+	t.Fatalf("Job %s doesn't set Drupal's maintenance mode: %v", job.Name, command)
+	return ""
+}
+
+// routeServices returns the Services the Site's routes are sent to, by path
+func routeServices(rh *requestHandler) map[string]string {
+	services := map[string]string{}
+	for _, route := range rh.routes() {
+		services[route.Path] = route.Target.ServiceName
+	}
+	return services
+}
+
+// ingressServices returns the Services the paths of the Site's Ingress are sent to, once it is updated with the Site's
+// routes
+func ingressServices(t *testing.T, rh *requestHandler, c client.Client) map[string]string {
+	if err := rh.reconciler.updateIngress(rh.logger, rh.site, rh.site.Spec.Domains, rh.routes()); err != nil {
+		t.Fatal(err)
+	}
+	ing := &extv1b1.Ingress{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: rh.site.Name}, ing); err != nil {
+		t.Fatal(err)
+	}
+	services := map[string]string{}
+	for _, rule := range ing.Spec.Rules {
+		for _, path := range rule.HTTP.Paths {
+			services[path.Path] = path.Backend.ServiceName
+		}
+	}
+	return services
+}
+
+func TestReconcileMaintenanceOrdering(t *testing.T) {
+	rh, c := newMaintenanceTestHandler(t)
+	responder := rh.site.MaintenanceResponderName()
+	rh.site.Spec.Maintenance = &fn.MaintenanceSpec{}
+
+	type state struct {
+		requeue       bool
+		enabled       bool
+		drupalMode    bool
+		jobMode       string
+		drupalService string
+	}
+	check := func(step string, want state) {
+		t.Helper()
+		requeue, err := rh.reconcileMaintenance()
+		if err != nil {
+			t.Fatalf("%s: reconcileMaintenance failed: %v", step, err)
+		}
+		status := rh.status.Maintenance
+		got := state{requeue, status.Enabled, status.DrupalMaintenanceMode, maintenanceJobMode(t, rh, c), routeServices(rh)["/"]}
+		if got != want {
+			t.Errorf("%s: state is %+v, want %+v", step, got, want)
+		}
+		ingress := ingressServices(t, rh, c)
+		if ingress["/"] != want.drupalService || ingress["/static"] != "static" {
+			t.Errorf("%s: the Ingress sends / to %s and /static to %s, want %s and static", step, ingress["/"],
+				ingress["/static"], want.drupalService)
+		}
+	}
+
+	// Going in, Drupal is only put in maintenance mode once the routes are switched to an available responder
+	check("responder starting", state{requeue: true, drupalService: fn.DrupalServiceName})
+	makeResponderAvailable(t, rh, c)
+	check("responder available", state{enabled: true, jobMode: "1", drupalService: responder})
+	check("job running", state{enabled: true, jobMode: "1", drupalService: responder})
+	completeMaintenanceJob(t, rh, c)
+	check("job succeeded", state{enabled: true, drupalMode: true, drupalService: responder})
+	check("in maintenance", state{enabled: true, drupalMode: true, drupalService: responder})
+	if rh.status.Maintenance.Source != fn.MaintenanceSourceSite {
+		t.Errorf("maintenance source is %q, want %q", rh.status.Maintenance.Source, fn.MaintenanceSourceSite)
+	}
+
+	// Going out, the routes stay on the responder until Drupal is out of maintenance mode
+	rh.site.Spec.Maintenance = nil
+	check("leaving", state{enabled: true, drupalMode: true, jobMode: "0", drupalService: responder})
+	if err := rh.removeMaintenanceResponder(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: responder}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("the responder was removed while still routed to: %v", err)
+	}
+	completeMaintenanceJob(t, rh, c)
+	check("drupal out of maintenance", state{enabled: true, drupalService: responder})
+	check("left", state{drupalService: fn.DrupalServiceName})
+
+	if err := rh.removeMaintenanceResponder(); err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range []runtime.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.ConfigMap{}} {
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: responder}, obj); !errors.IsNotFound(err) {
+			t.Errorf("%T of the responder wasn't removed: %v", obj, err)
+		}
+	}
+}
+
+func TestReconcileMaintenanceFollowsEnvironment(t *testing.T) {
+	rh, c := newMaintenanceTestHandler(t)
+	rh.env.Spec.Maintenance = &fn.MaintenanceSpec{Message: "Environment maintenance"}
+
+	if _, err := rh.reconcileMaintenance(); err != nil {
+		t.Fatal(err)
+	}
+	makeResponderAvailable(t, rh, c)
+	if _, err := rh.reconcileMaintenance(); err != nil {
+		t.Fatal(err)
+	}
+	if !rh.status.Maintenance.Enabled || rh.status.Maintenance.Source != fn.MaintenanceSourceEnvironment {
+		t.Errorf("maintenance status is %+v, want enabled by the environment", rh.status.Maintenance)
+	}
+
+	// The Site's own spec.maintenance takes precedence
+	rh.site.Spec.Maintenance = &fn.MaintenanceSpec{Message: "Site maintenance"}
+	if _, err := rh.reconcileMaintenance(); err != nil {
+		t.Fatal(err)
+	}
+	if rh.status.Maintenance.Source != fn.MaintenanceSourceSite {
+		t.Errorf("maintenance source is %q, want %q", rh.status.Maintenance.Source, fn.MaintenanceSourceSite)
+	}
+}
+
+func TestReconcileMaintenanceResponder(t *testing.T) {
+	rh, c := newMaintenanceTestHandler(t)
+	spec := &fn.MaintenanceSpec{
+		Message:    `Back at <b>5pm</b> & "soon"`,
+		AllowedIPs: []string{"203.0.113.7", "198.51.100.0/24"},
+	}
+
+	available, err := rh.reconcileMaintenanceResponder(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available {
+		t.Error("the responder is available before its Deployment has any available replica")
+	}
+
+	key := types.NamespacedName{Namespace: testNamespace, Name: rh.site.MaintenanceResponderName()}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), key, cm); err != nil {
+		t.Fatal(err)
+	}
+	config := cm.Data[maintenanceConfigKey]
+	for _, want := range []string{
+		"    default 0;\n    203.0.113.7 1;\n    198.51.100.0/24 1;\n}",
+		"listen 8080;",
+		"real_ip_header X-Forwarded-For;",
+		"return 503;",
+		"proxy_pass http://drupal:80;",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("NGINX config lacks %q:\n%s", want, config)
+		}
+	}
+	page := cm.Data[maintenancePageKey]
+	if want := "<p>Back at &lt;b&gt;5pm&lt;/b&gt; &amp; &#34;soon&#34;</p>"; !strings.Contains(page, want) {
+		t.Errorf("maintenance page lacks the escaped message %q:\n%s", want, page)
+	}
+
+	svc := &corev1.Service{}
+	if err := c.Get(context.TODO(), key, svc); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Spec.Selector["type"] != "maintenance" || svc.Spec.Ports[0].Port != 80 || svc.Spec.Ports[0].TargetPort.IntValue() != maintenancePort {
+		t.Errorf("responder Service spec is %+v", svc.Spec)
+	}
+
+	dep := &appsv1.Deployment{}
+	if err := c.Get(context.TODO(), key, dep); err != nil {
+		t.Fatal(err)
+	}
+	hash := dep.Spec.Template.Annotations[maintenanceConfigHashAnno]
+	if hash == "" {
+		t.Error("responder pods have no config hash annotation")
+	}
+
+	// A new message rolls the responder's pods, so that they serve it
+	makeResponderAvailable(t, rh, c)
+	spec.Message = "Back tomorrow"
+	if available, err = rh.reconcileMaintenanceResponder(spec); err != nil {
+		t.Fatal(err)
+	}
+	if !available {
+		t.Error("the responder isn't available once its Deployment is")
+	}
+	if err := c.Get(context.TODO(), key, dep); err != nil {
+		t.Fatal(err)
+	}
+	if dep.Spec.Template.Annotations[maintenanceConfigHashAnno] == hash {
+		t.Error("the config hash annotation didn't change with the message")
+	}
+}
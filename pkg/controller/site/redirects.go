@@ -0,0 +1,139 @@
+package site
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+)
+
+// Annotations of the ingress controllers that answer the requests of an Ingress with a redirect
+const (
+	nginxPermanentRedirect = "nginx.ingress.kubernetes.io/permanent-redirect"
+	nginxTemporalRedirect  = "nginx.ingress.kubernetes.io/temporal-redirect"
+
+	traefikRedirectRegex       = "ingress.kubernetes.io/redirect-regex"
+	traefikRedirectReplacement = "ingress.kubernetes.io/redirect-replacement"
+	traefikRedirectPermanent   = "ingress.kubernetes.io/redirect-permanent"
+)
+
+// redirectAnnotations are the annotations set from spec.redirects, which are removed from the Site's routing objects
+// when they are no longer wanted
+var redirectAnnotations = []string{
+	nginxPermanentRedirect,
+	nginxTemporalRedirect,
+	traefikRedirectRegex,
+	traefikRedirectReplacement,
+	traefikRedirectPermanent,
+}
+
+// redirectRoutes are the routes of the Ingress of a redirect host. Ingresses need a backend, but the ingress controller
+// answers with the redirect before it is reached.
+var redirectRoutes = []fn.SiteRoute{{Path: "/", PathType: fn.PathTypePrefix, Target: fn.DrupalRouteTarget()}}
+
+// ingressRedirectAnnotations returns the annotations that make the ingress controller of the given class answer with a
+// redirect. NGINX and Traefik ingress classes are recognized by their names; other classes return an error.
+func ingressRedirectAnnotations(redirect fn.SiteRedirect, ingressClass string) (map[string]string, error) {
+	location := redirect.ToURL
+	if redirect.PreservePath {
+		location = strings.TrimRight(location, "/")
+	}
+
+	switch {
+	case strings.Contains(ingressClass, "nginx"):
+		if redirect.PreservePath {
+			location += "$request_uri"
+		}
+		if redirect.StatusCode() == 301 {
+			return map[string]string{nginxPermanentRedirect: location}, nil
+		}
+		return map[string]string{nginxTemporalRedirect: location}, nil
+
+	case strings.Contains(ingressClass, "traefik"):
+		// The regular expression is matched against the whole URL of the request
+		replacement := location
+		if redirect.PreservePath {
+			replacement += "$1"
+		}
+		return map[string]string{
+			traefikRedirectRegex:       "^https?://[^/]+(.*)$",
+			traefikRedirectReplacement: replacement,
+			traefikRedirectPermanent:   fmt.Sprint(redirect.StatusCode() == 301),
+		}, nil
+	}
+	return nil, fmt.Errorf("ingress class %s doesn't support redirects, so redirect host %s isn't routed", ingressClass, redirect.FromHost)
+}
+
+// reconcileRedirects answers the host of each published redirect of the Site with its redirect, through a routing
+// object of its own, and deletes the routing objects of redirects that are no longer published. The hosts with routing
+// objects are tracked in the Site's status. Ingress classes that can't redirect leave the hosts unrouted, with a
+// warning event.
+func (rh *requestHandler) reconcileRedirects() error {
+	rt, previous, err := rh.reconciler.routers(rh.logger)
+	if err != nil {
+		return err
+	}
+
+	published := map[string]bool{}
+	for _, host := range rh.status.RedirectHosts {
+		published[host] = true
+	}
+	defer func() {
+		hosts := make([]string, 0, len(published))
+		for host := range published {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		if len(hosts) == 0 {
+			hosts = nil
+		}
+		rh.status.RedirectHosts = hosts
+	}()
+
+	config := operatorconfig.Current()
+	ingressClass := rh.site.IngressClass(config.IngressClass)
+	_, httpRoutes := rt.(*httpRouteRouter)
+
+	desired := map[string]bool{}
+	for _, redirect := range rh.redirects {
+		annotations := map[string]string{}
+		if !httpRoutes {
+			if annotations, err = ingressRedirectAnnotations(redirect, ingressClass); err != nil {
+				rh.reconciler.recorder.Event(rh.site, corev1.EventTypeWarning, "UnsupportedIngressClass", err.Error())
+				continue
+			}
+			annotations["kubernetes.io/ingress.class"] = ingressClass
+			annotations["certmanager.k8s.io/cluster-issuer"] = rh.site.IngressCertIssuer(config.CertIssuer)
+		}
+
+		name := rh.site.RedirectIngressName(redirect.FromHost)
+		if err := rt.applyRedirect(rh.site, name, redirect, annotations); err != nil {
+			return err
+		}
+		published[redirect.FromHost] = true
+		desired[redirect.FromHost] = true
+		for _, old := range previous {
+			if err := old.remove(rh.site, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for host := range published {
+		if desired[host] {
+			continue
+		}
+		name := rh.site.RedirectIngressName(host)
+		for _, router := range append([]router{rt}, previous...) {
+			if err := router.remove(rh.site, name); err != nil {
+				return err
+			}
+		}
+		delete(published, host)
+	}
+	return nil
+}
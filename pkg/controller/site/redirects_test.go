@@ -0,0 +1,270 @@
+package site
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	extv1b1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+func TestIngressRedirectAnnotations(t *testing.T) {
+	cases := []struct {
+		name         string
+		redirect     fn.SiteRedirect
+		ingressClass string
+		want         map[string]string
+	}{
+		{
+			name:         "nginx permanent",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/"},
+			ingressClass: "nginx",
+			want:         map[string]string{nginxPermanentRedirect: "https://wlgore.com/"},
+		},
+		{
+			name:         "nginx temporary",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/sale", Code: 302},
+			ingressClass: "nginx-internal",
+			want:         map[string]string{nginxTemporalRedirect: "https://wlgore.com/sale"},
+		},
+		{
+			name:         "nginx preserving the path",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/", PreservePath: true},
+			ingressClass: "nginx",
+			want:         map[string]string{nginxPermanentRedirect: "https://wlgore.com$request_uri"},
+		},
+		{
+			name:         "traefik permanent",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/"},
+			ingressClass: "traefik",
+			want: map[string]string{
+				traefikRedirectRegex:       "^https?://[^/]+(.*)$",
+				traefikRedirectReplacement: "https://wlgore.com/",
+				traefikRedirectPermanent:   "true",
+			},
+		},
+		{
+			name:         "traefik temporary preserving the path",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/en/", Code: 307, PreservePath: true},
+			ingressClass: "traefik",
+			want: map[string]string{
+				traefikRedirectRegex:       "^https?://[^/]+(.*)$",
+				traefikRedirectReplacement: "https://wlgore.com/en$1",
+				traefikRedirectPermanent:   "false",
+			},
+		},
+		{
+			name:         "unsupported class",
+			redirect:     fn.SiteRedirect{ToURL: "https://wlgore.com/"},
+			ingressClass: "haproxy",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.redirect.FromHost = "www.wlgore.com"
+			annotations, err := ingressRedirectAnnotations(c.redirect, c.ingressClass)
+			if c.want == nil {
+				if err == nil {
+					t.Errorf("ingressRedirectAnnotations returned %v, want an error", annotations)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(annotations, c.want) {
+				t.Errorf("ingressRedirectAnnotations returned %v, want %v", annotations, c.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRouteRedirectFilter(t *testing.T) {
+	cases := []struct {
+		name     string
+		redirect fn.SiteRedirect
+		want     map[string]interface{}
+	}{
+		{
+			name:     "permanent",
+			redirect: fn.SiteRedirect{ToURL: "https://wlgore.com"},
+			want: map[string]interface{}{
+				"scheme": "https", "hostname": "wlgore.com", "statusCode": int64(301),
+				"path": map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": "/"},
+			},
+		},
+		{
+			name:     "temporary with a path",
+			redirect: fn.SiteRedirect{ToURL: "http://wlgore.com/sale", Code: 302},
+			want: map[string]interface{}{
+				"scheme": "http", "hostname": "wlgore.com", "statusCode": int64(302),
+				"path": map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": "/sale"},
+			},
+		},
+		{
+			name:     "port",
+			redirect: fn.SiteRedirect{ToURL: "https://wlgore.com:8443/"},
+			want: map[string]interface{}{
+				"scheme": "https", "hostname": "wlgore.com", "statusCode": int64(301), "port": int64(8443),
+				"path": map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": "/"},
+			},
+		},
+		{
+			name:     "preserving the path",
+			redirect: fn.SiteRedirect{ToURL: "https://wlgore.com/", PreservePath: true},
+			want:     map[string]interface{}{"scheme": "https", "hostname": "wlgore.com", "statusCode": int64(301)},
+		},
+		{
+			name:     "preserving the path under a prefix",
+			redirect: fn.SiteRedirect{ToURL: "https://wlgore.com/en/", PreservePath: true},
+			want: map[string]interface{}{
+				"scheme": "https", "hostname": "wlgore.com", "statusCode": int64(301),
+				"path": map[string]interface{}{"type": "ReplacePrefixMatch", "replacePrefixMatch": "/en"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			site := &fn.Site{
+				ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+				Spec:       fn.SiteSpec{Environment: "prod"},
+			}
+			site.SetId("3f1c2a9e-5b7d-4c8e-9a6f-1d2e3f4a5b6c")
+			r, _ := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site)
+			rt := &httpRouteRouter{r: r, logger: log, version: "v1", gateway: fn.GatewayReference{Namespace: "gateways", Name: "public"}}
+
+			c.redirect.FromHost = "www.wlgore.com"
+			if err := rt.applyRedirect(site, "wlgore-redirect", c.redirect, nil); err != nil {
+				t.Fatal(err)
+			}
+			route, err := rt.objects().get(site, "wlgore-redirect")
+			if err != nil || route == nil {
+				t.Fatalf("the HTTPRoute wasn't created: %v", err)
+			}
+
+			hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+			if !reflect.DeepEqual(hostnames, []string{"www.wlgore.com"}) {
+				t.Errorf("the HTTPRoute's hostnames are %v", hostnames)
+			}
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			if len(rules) != 1 {
+				t.Fatalf("the HTTPRoute has rules %v, want one", rules)
+			}
+			filters, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "filters")
+			if len(filters) != 1 {
+				t.Fatalf("the HTTPRoute's rule has filters %v, want one", filters)
+			}
+			filter, _, _ := unstructured.NestedMap(filters[0].(map[string]interface{}), "requestRedirect")
+			if !reflect.DeepEqual(filter, c.want) {
+				t.Errorf("the RequestRedirect filter is %v, want %v", filter, c.want)
+			}
+		})
+	}
+}
+
+func TestReconcileRedirects(t *testing.T) {
+	cases := []struct {
+		name         string
+		ingressClass string
+		redirect     fn.SiteRedirect
+		annotations  map[string]string
+	}{
+		{
+			name:         "nginx",
+			ingressClass: "nginx",
+			redirect:     fn.SiteRedirect{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/"},
+			annotations: map[string]string{
+				nginxPermanentRedirect:              "https://wlgore.com/",
+				"kubernetes.io/ingress.class":       "nginx",
+				"certmanager.k8s.io/cluster-issuer": "letsencrypt-prod",
+			},
+		},
+		{
+			name:         "traefik",
+			ingressClass: "traefik",
+			redirect:     fn.SiteRedirect{FromHost: "*.wlgore.com", ToURL: "https://wlgore.com", Code: 302},
+			annotations: map[string]string{
+				traefikRedirectRegex:                "^https?://[^/]+(.*)$",
+				traefikRedirectReplacement:          "https://wlgore.com",
+				traefikRedirectPermanent:            "false",
+				"kubernetes.io/ingress.class":       "traefik",
+				"certmanager.k8s.io/cluster-issuer": "letsencrypt-prod",
+			},
+		},
+		{
+			// The host is left unrouted
+			name:         "unsupported ingress class",
+			ingressClass: "haproxy",
+			redirect:     fn.SiteRedirect{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			site := &fn.Site{
+				ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+				Spec: fn.SiteSpec{
+					Environment:  "prod",
+					Domains:      []string{"wlgore.com"},
+					Redirects:    []fn.SiteRedirect{c.redirect},
+					IngressClass: c.ingressClass,
+					CertIssuer:   "letsencrypt-prod",
+				},
+			}
+			site.SetId("3f1c2a9e-5b7d-4c8e-9a6f-1d2e3f4a5b6c")
+			r, cl := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site)
+			rh := &requestHandler{reconciler: r, site: site, status: &site.Status, logger: log, redirects: site.Spec.Redirects}
+
+			if err := rh.reconcileRedirects(); err != nil {
+				t.Fatal(err)
+			}
+
+			name := types.NamespacedName{Namespace: testNamespace, Name: site.RedirectIngressName(c.redirect.FromHost)}
+			ing := &extv1b1.Ingress{}
+			err := cl.Get(context.TODO(), name, ing)
+			if c.annotations == nil {
+				if !errors.IsNotFound(err) {
+					t.Errorf("an Ingress was created for an ingress class that can't redirect: %v", err)
+				}
+				if len(site.Status.RedirectHosts) > 0 {
+					t.Errorf("status.redirectHosts is %v, want none", site.Status.RedirectHosts)
+				}
+				if events := r.recorder.(*record.FakeRecorder).Events; len(events) != 1 {
+					t.Errorf("%d events were emitted, want a warning", len(events))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("the redirect Ingress wasn't created: %v", err)
+			}
+			if !reflect.DeepEqual(ing.Annotations, c.annotations) {
+				t.Errorf("the redirect Ingress has annotations %v, want %v", ing.Annotations, c.annotations)
+			}
+			if len(ing.Spec.Rules) != 1 || ing.Spec.Rules[0].Host != c.redirect.FromHost {
+				t.Errorf("the redirect Ingress has rules %+v, want one for %s", ing.Spec.Rules, c.redirect.FromHost)
+			}
+			if !reflect.DeepEqual(site.Status.RedirectHosts, []string{c.redirect.FromHost}) {
+				t.Errorf("status.redirectHosts is %v", site.Status.RedirectHosts)
+			}
+
+			// A redirect that is no longer published has its Ingress deleted
+			rh.redirects = nil
+			if err := rh.reconcileRedirects(); err != nil {
+				t.Fatal(err)
+			}
+			if err := cl.Get(context.TODO(), name, ing); !errors.IsNotFound(err) {
+				t.Errorf("the Ingress of an unpublished redirect wasn't deleted: %v", err)
+			}
+			if site.Status.RedirectHosts != nil {
+				t.Errorf("status.redirectHosts is %v, want none", site.Status.RedirectHosts)
+			}
+		})
+	}
+}
@@ -0,0 +1,613 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	extv1b1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
+)
+
+// The client libraries the operator is built with predate networking.k8s.io/v1 Ingresses and the Gateway API, so those
+// objects are handled as unstructured objects, through a client that reads from the API server rather than the cache.
+const gatewayGroup = "gateway.networking.k8s.io"
+
+var ingressGVK = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
+
+// routingAPIs are the routing APIs served by the cluster, discovered when the controller starts
+type routingAPIs struct {
+	legacyIngress bool
+	ingress       bool
+	// gatewayVersion is the newest served version of the Gateway API, or "" if it isn't installed
+	gatewayVersion string
+}
+
+// discoverRoutingAPIs asks the API server which of the routing APIs it serves
+func discoverRoutingAPIs(config *rest.Config) (routingAPIs, error) {
+	apis := routingAPIs{}
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return apis, err
+	}
+	serves := func(groupVersion, resource string) (bool, error) {
+		resources, err := dc.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil && errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == resource {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if apis.legacyIngress, err = serves("extensions/v1beta1", "ingresses"); err != nil {
+		return apis, err
+	}
+	if apis.ingress, err = serves(ingressGVK.GroupVersion().String(), "ingresses"); err != nil {
+		return apis, err
+	}
+	for _, version := range []string{"v1", "v1beta1"} {
+		served, err := serves(gatewayGroup+"/"+version, "httproutes")
+		if err != nil {
+			return apis, err
+		}
+		if served {
+			apis.gatewayVersion = version
+			break
+		}
+	}
+	return apis, nil
+}
+
+// selected returns the routing API configured in the DrupalOperatorConfig, or the newest Ingress API served
+func (apis routingAPIs) selected(configured fn.RoutingAPI) (fn.RoutingAPI, error) {
+	switch configured {
+	case "":
+		if apis.ingress {
+			return fn.RoutingAPIIngress, nil
+		}
+		if apis.legacyIngress {
+			return fn.RoutingAPILegacyIngress, nil
+		}
+		return "", fmt.Errorf("the cluster serves neither networking.k8s.io/v1 nor extensions/v1beta1 Ingresses")
+	case fn.RoutingAPIIngress:
+		if !apis.ingress {
+			return "", fmt.Errorf("the cluster doesn't serve networking.k8s.io/v1 Ingresses")
+		}
+	case fn.RoutingAPILegacyIngress:
+		if !apis.legacyIngress {
+			return "", fmt.Errorf("the cluster doesn't serve extensions/v1beta1 Ingresses")
+		}
+	case fn.RoutingAPIHTTPRoute:
+		if apis.gatewayVersion == "" {
+			return "", fmt.Errorf("the cluster doesn't serve Gateway API HTTPRoutes; the operator must be restarted after it is installed")
+		}
+	}
+	return configured, nil
+}
+
+// router publishes the domains of a Site through one kind of routing object. Objects are named by the caller, and are
+// in the Site's namespace.
+type router interface {
+	// apply creates or updates the named object, routing the given domains of the Site through the given routes
+	apply(s *fn.Site, name string, domains []string, routes []fn.SiteRoute, annotations map[string]string) error
+	// applyRedirect creates or updates the named object, answering the host of a redirect of the Site with the
+	// redirect. Ingresses rely on the given annotations to redirect.
+	applyRedirect(s *fn.Site, name string, redirect fn.SiteRedirect, annotations map[string]string) error
+	// observe returns what the named object publishes, or nil if it doesn't exist
+	observe(s *fn.Site, name string) (*publishedRoutes, error)
+	// remove deletes the named object, if it exists
+	remove(s *fn.Site, name string) error
+}
+
+// publishedRoutes are the hosts a routing object serves, and where their traffic arrives
+type publishedRoutes struct {
+	hosts []string
+	// tlsSecrets maps the hosts served over TLS to the Secrets with their certificates
+	tlsSecrets map[string]string
+	// addresses are those of the load balancer or Gateway receiving the traffic
+	addresses []corev1.LoadBalancerIngress
+}
+
+// routers returns the router of the routing API in effect, and those of the other kinds of routing objects the cluster
+// serves, whose objects are removed when a Site switches to the API in effect. Both Ingress APIs serve the same
+// objects, so an Ingress is migrated in place between them.
+func (r *ReconcileSite) routers(logger logr.Logger) (router, []router, error) {
+	config := operatorconfig.Current()
+	api, err := r.routingAPIs.selected(config.RoutingAPI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ingressRouter router
+	if r.routingAPIs.ingress {
+		ingressRouter = &unstructuredIngressRouter{r: r, logger: logger}
+	} else if r.routingAPIs.legacyIngress {
+		ingressRouter = &legacyIngressRouter{r: r, logger: logger}
+	}
+	var httpRouter router
+	if r.routingAPIs.gatewayVersion != "" {
+		httpRouter = &httpRouteRouter{r: r, logger: logger, version: r.routingAPIs.gatewayVersion, gateway: config.Gateway}
+	}
+
+	switch api {
+	case fn.RoutingAPIHTTPRoute:
+		if ingressRouter != nil {
+			return httpRouter, []router{ingressRouter}, nil
+		}
+		return httpRouter, nil, nil
+	case fn.RoutingAPILegacyIngress:
+		ingressRouter = &legacyIngressRouter{r: r, logger: logger}
+	}
+	if httpRouter != nil {
+		return ingressRouter, []router{httpRouter}, nil
+	}
+	return ingressRouter, nil, nil
+}
+
+// applyAnnotations sets the desired annotations on an object, and removes the TLS and redirect annotations that are no
+// longer desired. It returns true if the annotations changed.
+func applyAnnotations(o metav1.Object, desired map[string]string) bool {
+	annotations := o.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	changed := false
+	for k, v := range desired {
+		if current, ok := annotations[k]; !ok || current != v {
+			annotations[k] = v
+			changed = true
+		}
+	}
+	for _, managed := range [][]string{tlsAnnotations, redirectAnnotations} {
+		for _, k := range managed {
+			if _, ok := desired[k]; !ok && annotations[k] != "" {
+				delete(annotations, k)
+				changed = true
+			}
+		}
+	}
+	o.SetAnnotations(annotations)
+	return changed
+}
+
+// legacyIngressRouter routes through extensions/v1beta1 Ingresses, which have no path types: how their paths are
+// matched is up to the ingress controller, which usually matches them as prefixes
+type legacyIngressRouter struct {
+	r      *ReconcileSite
+	logger logr.Logger
+}
+
+func (rt *legacyIngressRouter) apply(s *fn.Site, name string, domains []string, routes []fn.SiteRoute, annotations map[string]string) error {
+	return rt.applyIngress(s, name, fn.IngressRules(domains, routes), s.IngressTLS(domains), annotations)
+}
+
+func (rt *legacyIngressRouter) applyRedirect(s *fn.Site, name string, redirect fn.SiteRedirect, annotations map[string]string) error {
+	hosts := []string{redirect.FromHost}
+	return rt.applyIngress(s, name, fn.IngressRules(hosts, redirectRoutes), s.IngressTLS(hosts), annotations)
+}
+
+func (rt *legacyIngressRouter) applyIngress(s *fn.Site, name string, rules []extv1b1.IngressRule, tls []extv1b1.IngressTLS, annotations map[string]string) error {
+	ing := &extv1b1.Ingress{}
+	err := rt.r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: s.Namespace}, ing)
+	if err != nil && errors.IsNotFound(err) {
+		ing = &extv1b1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   s.Namespace,
+				Labels:      s.ChildLabels(),
+				Annotations: annotations,
+			},
+			Spec: extv1b1.IngressSpec{
+				Rules: rules,
+				TLS:   tls,
+			},
+		}
+		rt.r.associateResourceWithController(rt.logger, ing, s)
+
+		rt.logger.Info("Creating new Ingress", "name", ing.Name, "namespace", ing.Namespace)
+		return rt.r.client.Create(context.TODO(), ing)
+	} else if err != nil {
+		return err
+	}
+
+	// ensure ingress is up to date
+	update := false
+	if !reflect.DeepEqual(ing.Spec.Rules, rules) {
+		rt.logger.Info("Ingress rules out of date. Updating...")
+		update = true
+		ing.Spec.Rules = rules
+	}
+	if !reflect.DeepEqual(ing.Spec.TLS, tls) {
+		rt.logger.Info("Ingress tls out of date. Updating...")
+		update = true
+		ing.Spec.TLS = tls
+	}
+	if applyAnnotations(ing, annotations) {
+		rt.logger.Info("Ingress annotations out of date. Updating...")
+		update = true
+	}
+	if update {
+		return rt.r.client.Update(context.TODO(), ing)
+	}
+	return nil
+}
+
+func (rt *legacyIngressRouter) observe(s *fn.Site, name string) (*publishedRoutes, error) {
+	ing := &extv1b1.Ingress{}
+	err := rt.r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: s.Namespace}, ing)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	routes := &publishedRoutes{tlsSecrets: map[string]string{}, addresses: ing.Status.LoadBalancer.Ingress}
+	for _, rule := range ing.Spec.Rules {
+		routes.hosts = append(routes.hosts, rule.Host)
+	}
+	for _, tls := range ing.Spec.TLS {
+		for _, host := range tls.Hosts {
+			routes.tlsSecrets[host] = tls.SecretName
+		}
+	}
+	return routes, nil
+}
+
+func (rt *legacyIngressRouter) remove(s *fn.Site, name string) error {
+	ing := &extv1b1.Ingress{}
+	err := rt.r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: s.Namespace}, ing)
+	if err == nil {
+		rt.logger.Info("Deleting Ingress", "name", ing.Name, "namespace", ing.Namespace)
+		err = rt.r.client.Delete(context.TODO(), ing)
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// unstructuredRouter creates, updates and deletes the unstructured routing objects of one kind. The spec fields it
+// sets are replaced when they differ; others, such as those defaulted by the API server, are left alone.
+type unstructuredRouter struct {
+	r      *ReconcileSite
+	logger logr.Logger
+	gvk    schema.GroupVersionKind
+}
+
+func (rt *unstructuredRouter) get(s *fn.Site, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(rt.gvk)
+	err := rt.r.apiClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: s.Namespace}, obj)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (rt *unstructuredRouter) applySpec(s *fn.Site, name string, spec map[string]interface{}, annotations map[string]string) error {
+	obj, err := rt.get(s, name)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		obj = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		for field, desired := range spec {
+			if !isEmpty(desired) {
+				if err := unstructured.SetNestedField(obj.Object, desired, "spec", field); err != nil {
+					return err
+				}
+			}
+		}
+		obj.SetGroupVersionKind(rt.gvk)
+		obj.SetName(name)
+		obj.SetNamespace(s.Namespace)
+		obj.SetLabels(s.ChildLabels())
+		obj.SetAnnotations(annotations)
+		rt.r.associateResourceWithController(rt.logger, obj, s)
+
+		rt.logger.Info("Creating new "+rt.gvk.Kind, "name", name, "namespace", s.Namespace)
+		return rt.r.apiClient.Create(context.TODO(), obj)
+	}
+
+	update := false
+	for field, desired := range spec {
+		current, _, err := unstructured.NestedFieldNoCopy(obj.Object, "spec", field)
+		if err != nil {
+			return err
+		}
+		if isEmpty(current) && isEmpty(desired) || equality.Semantic.DeepEqual(current, desired) {
+			continue
+		}
+		rt.logger.Info(rt.gvk.Kind + " " + field + " out of date. Updating...")
+		update = true
+		if isEmpty(desired) {
+			unstructured.RemoveNestedField(obj.Object, "spec", field)
+		} else if err := unstructured.SetNestedField(obj.Object, desired, "spec", field); err != nil {
+			return err
+		}
+	}
+	if applyAnnotations(obj, annotations) {
+		rt.logger.Info(rt.gvk.Kind + " annotations out of date. Updating...")
+		update = true
+	}
+	if update {
+		return rt.r.apiClient.Update(context.TODO(), obj)
+	}
+	return nil
+}
+
+// isEmpty returns true for a missing field or an empty list, which are equivalent in a spec
+func isEmpty(value interface{}) bool {
+	list, ok := value.([]interface{})
+	return value == nil || ok && len(list) == 0
+}
+
+func (rt *unstructuredRouter) remove(s *fn.Site, name string) error {
+	obj, err := rt.get(s, name)
+	if err != nil || obj == nil {
+		return err
+	}
+	rt.logger.Info("Deleting "+rt.gvk.Kind, "name", name, "namespace", s.Namespace)
+	if err := rt.r.apiClient.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// unstructuredIngressRouter routes through networking.k8s.io/v1 Ingresses. Reading an extensions/v1beta1 Ingress
+// through this API and updating it migrates it in place.
+type unstructuredIngressRouter struct {
+	r      *ReconcileSite
+	logger logr.Logger
+}
+
+func (rt *unstructuredIngressRouter) objects() *unstructuredRouter {
+	return &unstructuredRouter{r: rt.r, logger: rt.logger, gvk: ingressGVK}
+}
+
+func (rt *unstructuredIngressRouter) apply(s *fn.Site, name string, domains []string, routes []fn.SiteRoute, annotations map[string]string) error {
+	return rt.applyIngress(s, name, domains, routes, s.IngressTLS(domains), annotations)
+}
+
+func (rt *unstructuredIngressRouter) applyRedirect(s *fn.Site, name string, redirect fn.SiteRedirect, annotations map[string]string) error {
+	hosts := []string{redirect.FromHost}
+	return rt.applyIngress(s, name, hosts, redirectRoutes, s.IngressTLS(hosts), annotations)
+}
+
+func (rt *unstructuredIngressRouter) applyIngress(s *fn.Site, name string, hosts []string, routes []fn.SiteRoute, ingressTLS []extv1b1.IngressTLS, annotations map[string]string) error {
+	var paths []interface{}
+	for _, route := range routes {
+		port := map[string]interface{}{"number": int64(route.Target.ServicePort.IntValue())}
+		if route.Target.ServicePort.Type == intstr.String {
+			port = map[string]interface{}{"name": route.Target.ServicePort.StrVal}
+		}
+		paths = append(paths, map[string]interface{}{
+			"path":     route.Path,
+			"pathType": string(route.PathType),
+			"backend": map[string]interface{}{
+				"service": map[string]interface{}{"name": route.Target.ServiceName, "port": port},
+			},
+		})
+	}
+	var rules []interface{}
+	for _, host := range hosts {
+		rules = append(rules, map[string]interface{}{
+			"host": host,
+			"http": map[string]interface{}{"paths": paths},
+		})
+	}
+
+	var tls []interface{}
+	for _, t := range ingressTLS {
+		hosts := make([]interface{}, len(t.Hosts))
+		for i, host := range t.Hosts {
+			hosts[i] = host
+		}
+		tls = append(tls, map[string]interface{}{"hosts": hosts, "secretName": t.SecretName})
+	}
+
+	return rt.objects().applySpec(s, name, map[string]interface{}{"rules": rules, "tls": tls}, annotations)
+}
+
+func (rt *unstructuredIngressRouter) observe(s *fn.Site, name string) (*publishedRoutes, error) {
+	obj, err := rt.objects().get(s, name)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+
+	routes := &publishedRoutes{tlsSecrets: map[string]string{}}
+	rules, _, _ := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	for _, rule := range rules {
+		if host, ok := rule.(map[string]interface{})["host"].(string); ok {
+			routes.hosts = append(routes.hosts, host)
+		}
+	}
+	tls, _, _ := unstructured.NestedSlice(obj.Object, "spec", "tls")
+	for _, t := range tls {
+		entry, _ := t.(map[string]interface{})
+		secretName, _ := entry["secretName"].(string)
+		hosts, _ := entry["hosts"].([]interface{})
+		for _, host := range hosts {
+			if h, ok := host.(string); ok {
+				routes.tlsSecrets[h] = secretName
+			}
+		}
+	}
+	lbs, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	for _, lb := range lbs {
+		entry, _ := lb.(map[string]interface{})
+		ip, _ := entry["ip"].(string)
+		hostname, _ := entry["hostname"].(string)
+		routes.addresses = append(routes.addresses, corev1.LoadBalancerIngress{IP: ip, Hostname: hostname})
+	}
+	return routes, nil
+}
+
+func (rt *unstructuredIngressRouter) remove(s *fn.Site, name string) error {
+	return rt.objects().remove(s, name)
+}
+
+// httpRouteRouter routes through Gateway API HTTPRoutes attached to a Gateway. TLS is terminated by the listeners of
+// the Gateway, so the TLS settings of Sites don't apply.
+type httpRouteRouter struct {
+	r       *ReconcileSite
+	logger  logr.Logger
+	version string
+	gateway fn.GatewayReference
+}
+
+func (rt *httpRouteRouter) objects() *unstructuredRouter {
+	return &unstructuredRouter{r: rt.r, logger: rt.logger, gvk: schema.GroupVersionKind{Group: gatewayGroup, Version: rt.version, Kind: "HTTPRoute"}}
+}
+
+func (rt *httpRouteRouter) apply(s *fn.Site, name string, domains []string, routes []fn.SiteRoute, annotations map[string]string) error {
+	// An HTTPRoute's rules apply to all of its hostnames, and every host of a Site has the same paths. Defaulted
+	// fields are set, so that they don't differ from the HTTPRoute read back.
+	var rules []interface{}
+	for _, route := range routes {
+		if route.Target.ServicePort.Type == intstr.String {
+			return fmt.Errorf("the route of %s targets port %s of Service %s by name, but HTTPRoutes need port numbers",
+				route.Path, route.Target.ServicePort.StrVal, route.Target.ServiceName)
+		}
+		matchType := "PathPrefix"
+		if route.PathType == fn.PathTypeExact {
+			matchType = "Exact"
+		}
+		rules = append(rules, map[string]interface{}{
+			"matches": []interface{}{map[string]interface{}{
+				"path": map[string]interface{}{"type": matchType, "value": route.Path},
+			}},
+			"backendRefs": []interface{}{map[string]interface{}{
+				"group":  "",
+				"kind":   "Service",
+				"name":   route.Target.ServiceName,
+				"port":   int64(route.Target.ServicePort.IntValue()),
+				"weight": int64(1),
+			}},
+		})
+	}
+
+	return rt.applyRoute(s, name, domains, rules, annotations)
+}
+
+// applyRedirect answers the host of the redirect with a RequestRedirect filter. The path of the URL replaces the path of
+// the request, or is prepended to it when the path is preserved.
+func (rt *httpRouteRouter) applyRedirect(s *fn.Site, name string, redirect fn.SiteRedirect, annotations map[string]string) error {
+	location, err := url.Parse(redirect.ToURL)
+	if err != nil {
+		return err
+	}
+	filter := map[string]interface{}{
+		"scheme":     location.Scheme,
+		"hostname":   location.Hostname(),
+		"statusCode": int64(redirect.StatusCode()),
+	}
+	if port := location.Port(); port != "" {
+		number, err := strconv.ParseInt(port, 10, 32)
+		if err != nil {
+			return err
+		}
+		filter["port"] = number
+	}
+	switch prefix := strings.TrimRight(location.Path, "/"); {
+	case !redirect.PreservePath:
+		filter["path"] = map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": "/" + strings.TrimPrefix(location.Path, "/")}
+	case prefix != "":
+		filter["path"] = map[string]interface{}{"type": "ReplacePrefixMatch", "replacePrefixMatch": prefix}
+	}
+
+	rules := []interface{}{map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{
+			"path": map[string]interface{}{"type": "PathPrefix", "value": "/"},
+		}},
+		"filters": []interface{}{map[string]interface{}{
+			"type":            "RequestRedirect",
+			"requestRedirect": filter,
+		}},
+	}}
+	return rt.applyRoute(s, name, []string{redirect.FromHost}, rules, annotations)
+}
+
+func (rt *httpRouteRouter) applyRoute(s *fn.Site, name string, hosts []string, rules []interface{}, annotations map[string]string) error {
+	parentRef := map[string]interface{}{
+		"group":     gatewayGroup,
+		"kind":      "Gateway",
+		"namespace": rt.gateway.Namespace,
+		"name":      rt.gateway.Name,
+	}
+	if rt.gateway.SectionName != "" {
+		parentRef["sectionName"] = rt.gateway.SectionName
+	}
+
+	hostnames := make([]interface{}, len(hosts))
+	for i, host := range hosts {
+		hostnames[i] = host
+	}
+
+	return rt.objects().applySpec(s, name, map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  hostnames,
+		"rules":      rules,
+	}, annotations)
+}
+
+// observe returns the hostnames of the HTTPRoute, and the addresses of its Gateway
+func (rt *httpRouteRouter) observe(s *fn.Site, name string) (*publishedRoutes, error) {
+	obj, err := rt.objects().get(s, name)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+
+	routes := &publishedRoutes{}
+	hostnames, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+	routes.hosts = hostnames
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(schema.GroupVersionKind{Group: gatewayGroup, Version: rt.version, Kind: "Gateway"})
+	err = rt.r.apiClient.Get(context.TODO(), types.NamespacedName{Namespace: rt.gateway.Namespace, Name: rt.gateway.Name}, gateway)
+	if err != nil && errors.IsNotFound(err) {
+		return routes, nil
+	} else if err != nil {
+		return nil, err
+	}
+	addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+	for _, address := range addresses {
+		entry, _ := address.(map[string]interface{})
+		value, _ := entry["value"].(string)
+		switch entry["type"] {
+		case "Hostname":
+			routes.addresses = append(routes.addresses, corev1.LoadBalancerIngress{Hostname: value})
+		case "IPAddress", nil:
+			routes.addresses = append(routes.addresses, corev1.LoadBalancerIngress{IP: value})
+		}
+	}
+	return routes, nil
+}
+
+func (rt *httpRouteRouter) remove(s *fn.Site, name string) error {
+	return rt.objects().remove(s, name)
+}
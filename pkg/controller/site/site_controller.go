@@ -3,12 +3,12 @@ package site
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1b1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	extv1b1 "k8s.io/api/extensions/v1beta1"
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -27,6 +28,8 @@ import (
 
 	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
 	"github.com/acquia/fn-drupal-operator/pkg/common"
+	"github.com/acquia/fn-drupal-operator/pkg/dns"
+	"github.com/acquia/fn-drupal-operator/pkg/operatorconfig"
 )
 
 // siteCleanupFinalizer defines the site finalizer.
@@ -40,16 +43,45 @@ var log = logf.Log.WithName("controller_site")
 // Add creates a new Site Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r, r.routingAPIs)
 }
 
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileSite{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+// newReconciler returns a new ReconcileSite, with the DNS provider configured by the environment and the routing APIs
+// served by the cluster
+func newReconciler(mgr manager.Manager) (*ReconcileSite, error) {
+	dnsProvider, err := dns.FromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	apis, err := discoverRoutingAPIs(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+
+	c := mgr.GetClient()
+	return &ReconcileSite{
+		client:      c,
+		apiClient:   apiClient,
+		scheme:      mgr.GetScheme(),
+		recorder:    mgr.GetRecorder("site-controller"),
+		dnsProvider: dnsProvider,
+		routingAPIs: apis,
+		newProvisioner: func(namespace string) common.DatabaseProvisioner {
+			return common.NewMySQLProvisioner(c, namespace)
+		},
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, apis routingAPIs) error {
 	// Create a new controller
 	c, err := controller.New("site-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 30})
 	if err != nil {
@@ -61,6 +93,25 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err := c.Watch(&source.Kind{Type: &fn.Site{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
 	}
+	// Sites listing the same domains depend on each other: see reconcileDomainConflicts
+	if err := c.Watch(&source.Kind{Type: &fn.Site{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: sitesSharingDomains(mgr.GetClient()),
+	}); err != nil {
+		return err
+	}
+	// A Site whose database is used by another Site is provisioned once that Site is deleted: see validateDatabaseNames
+	if err := c.Watch(&source.Kind{Type: &fn.Site{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: sitesSharingDatabase(mgr.GetClient()),
+	}); err != nil {
+		return err
+	}
+
+	// Sites follow the maintenance mode of their environment
+	if err := c.Watch(&source.Kind{Type: &fn.DrupalEnvironment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: sitesOfEnvironment(mgr.GetClient()),
+	}); err != nil {
+		return err
+	}
 
 	// Watch for secondary resources created by and owned exclusively by a Site
 	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
@@ -69,7 +120,17 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}); err != nil {
 		return err
 	}
-	if err := c.Watch(&source.Kind{Type: &extv1b1.Ingress{}}, &handler.EnqueueRequestForOwner{
+	// Routing objects of newer APIs are unstructured, which the cache doesn't support, so only extensions/v1beta1
+	// Ingresses are watched. Watching an API the cluster doesn't serve would keep the cache from syncing.
+	if apis.legacyIngress {
+		if err := c.Watch(&source.Kind{Type: &extv1b1.Ingress{}}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &fn.Site{},
+		}); err != nil {
+			return err
+		}
+	}
+	if err := c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &fn.Site{},
 	}); err != nil {
@@ -81,8 +142,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}); err != nil {
 		return err
 	}
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &fn.Site{},
+	}); err != nil {
+		return err
+	}
 
-	return nil
+	// Reconcile every Site when the operator configuration changes
+	return operatorconfig.Watch(c, mgr.GetClient(), &fn.SiteList{})
 }
 
 // blank assignment to verify that ReconcileSite implements reconcile.Reconciler
@@ -92,8 +160,20 @@ var _ reconcile.Reconciler = &ReconcileSite{}
 type ReconcileSite struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	// apiClient reads from the API server rather than the cache, for unstructured objects
+	apiClient client.Client
+
+	// newProvisioner returns the DatabaseProvisioner for Sites in a namespace. It can be replaced to test the controller
+	// without a database server.
+	newProvisioner func(namespace string) common.DatabaseProvisioner
+
+	// dnsProvider manages the DNS records of Site domains. It is nil when the operator doesn't manage DNS.
+	dnsProvider dns.DNSProvider
+	// routingAPIs are the APIs the Site's domains can be routed through
+	routingAPIs routingAPIs
 }
 
 // requestHandler gets initialized per request to have thread-safe code.
@@ -103,7 +183,16 @@ type requestHandler struct {
 	app    *fn.DrupalApplication
 	env    *fn.DrupalEnvironment
 	site   *fn.Site
+	status *fn.SiteStatus
 	logger logr.Logger
+
+	// domains are the domains of the Site that no other Site claimed first, which are published
+	domains []string
+	// redirects are the redirects of the Site whose hosts no other Site claimed first, which are published
+	redirects []fn.SiteRedirect
+	// recordDomains are the published domains and redirect hosts that no other Site shares, whose DNS records the Site
+	// manages
+	recordDomains []string
 }
 
 // Reconcile reads that state of the cluster for a Site object and makes changes based on the state read
@@ -114,6 +203,10 @@ type requestHandler struct {
 func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Request.Name", request.Name, "Request.Namespace", request.Namespace)
 
+	if err := operatorconfig.Load(r.client); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Fetch the Site instance
 	site := &fn.Site{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, site)
@@ -141,15 +234,27 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 		app:        &fn.DrupalApplication{},
 		env:        &fn.DrupalEnvironment{},
 		site:       site,
+		status:     site.Status.DeepCopy(),
 		logger:     reqLogger,
 	}
 
+	// Report the Site's progress, however far this reconcile gets
+	defer rh.updateStatus()
+
 	// TODO - factor-out "site" below as part of FN-255
 	// TODO - factor-out "reqLogger" below as part of FN-255
 
 	isSiteMarkedToBeDeleted := site.GetDeletionTimestamp() != nil
 	if isSiteMarkedToBeDeleted {
-		// Clean up unowned and external Resources
+		// Clean up unowned and external Resources. A final backup needs the Site's domain map entry, so it is taken first.
+		if requeue, err := rh.finalizeSnapshot(); err != nil {
+			return reconcile.Result{}, err
+		} else if requeue {
+			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		if err := rh.finalizeDNS(); err != nil {
+			return reconcile.Result{}, err
+		}
 		if err := r.finalizeDomainDbMapSecret(reqLogger, site); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -180,6 +285,8 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 	if err != nil {
 		if errors.IsNotFound(err) {
 			reqLogger.Info("Parent Environment doesn't exist", "Environment Name", rh.env.Name)
+			fn.SetCondition(&rh.status.Conditions, fn.ConditionEnvironmentAvailable, corev1.ConditionFalse, "EnvironmentNotFound",
+				fmt.Sprintf("DrupalEnvironment %s not found", site.Spec.Environment))
 			// Delay the requeue rather than returning an error, to avoid exponential error backoff
 			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
 		} else {
@@ -193,6 +300,8 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 	if err != nil {
 		if errors.IsNotFound(err) {
 			reqLogger.Info("Parent Application doesn't exist", "Application Name", rh.app.Name)
+			fn.SetCondition(&rh.status.Conditions, fn.ConditionEnvironmentAvailable, corev1.ConditionFalse, "ApplicationNotFound",
+				fmt.Sprintf("DrupalApplication %s not found", rh.env.Spec.Application))
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
 		} else {
 			reqLogger.Error(err, "Failed to get Application", "Application Name", rh.app.Name)
@@ -201,6 +310,7 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 	}
 
 	requeue, err := rh.linkToEnvironment()
+	rh.recordStep(fn.ConditionEnvironmentAvailable, requeue, err)
 	if err != nil {
 		reqLogger.Error(err, "Failed to link to parent Environment")
 		return reconcile.Result{Requeue: requeue}, err
@@ -209,26 +319,49 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 		return reconcile.Result{Requeue: requeue}, nil
 	}
 
-	if requeue, err := r.reconcileDbPwdSecret(reqLogger, site); requeue || err != nil {
-		return reconcile.Result{Requeue: requeue}, err
+	// A Site whose name can't be used for its database will never be provisioned, so it isn't retried
+	if err := rh.validateDatabaseNames(); err != nil {
+		reqLogger.Error(err, "Invalid Site name")
+		rh.recordStep(fn.ConditionDatabaseReady, false, err)
+		return reconcile.Result{}, nil
 	}
 
-	if requeue, err := r.reconcileDomainMap(reqLogger, site); requeue || err != nil {
+	if requeue, err := r.reconcileDbPwdSecret(reqLogger, site); requeue || err != nil {
+		rh.recordStep(fn.ConditionDatabaseReady, requeue, err)
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
-	if requeue, err := rh.reconcileDomainDbMapSecret(); requeue || err != nil {
+	err = rh.reconcileDomainConflicts()
+	if err == nil {
+		requeue, err = r.reconcileDomainMap(reqLogger, site, rh.domains)
+	}
+	if !requeue && err == nil {
+		requeue, err = rh.reconcileDomainDbMapSecret()
+	}
+	rh.recordStep(fn.ConditionDomainMapReady, requeue, err)
+	if requeue || err != nil {
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
-	if requeue, err := rh.reconcileDatabase(); requeue || err != nil {
+	requeue, err = rh.reconcileDatabase()
+	rh.recordStep(fn.ConditionDatabaseReady, requeue, err)
+	if requeue || err != nil {
 		if requeue {
 			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
 		}
 		return reconcile.Result{}, err
 	}
 
-	if requeue, err := rh.reconcileCronJobs(); requeue || err != nil {
+	if requeue, err := rh.reconcileInstall(); requeue || err != nil {
+		return reconcile.Result{Requeue: requeue}, err
+	}
+
+	requeue, err = rh.reconcileCronJobs()
+	if !requeue && err == nil {
+		requeue, err = rh.reconcileBackupCronJob()
+	}
+	rh.recordStep(fn.ConditionCronJobsReady, requeue, err)
+	if requeue || err != nil {
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
@@ -236,10 +369,62 @@ func (r *ReconcileSite) Reconcile(request reconcile.Request) (reconcile.Result,
 		return reconcile.Result{Requeue: requeue}, err
 	}
 
-	if err := r.updateIngress(reqLogger, site); err != nil {
+	if err := rh.observeJobs(); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	if err := rh.observeBackups(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := rh.reconcileDeployHook(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The routes are switched to the maintenance responder even if Drupal's maintenance mode failed to be set
+	requeueMaintenance, maintenanceErr := rh.reconcileMaintenance()
+	err = rh.seedTLSSecrets()
+	if err == nil {
+		err = r.updateIngress(reqLogger, site, rh.domains, rh.routes())
+	}
+	if err == nil {
+		err = rh.reconcileRedirects()
+	}
+	if err == nil {
+		err = rh.removeMaintenanceResponder()
+	}
+	if err == nil {
+		err = maintenanceErr
+	}
+	rh.recordStep(fn.ConditionIngressReady, false, err)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := rh.observeIngress(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if r.dnsProvider != nil {
+		requeue, err = rh.reconcileDNS()
+		rh.recordStep(fn.ConditionDNSReady, requeue, err)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if requeue {
+			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+		}
+	}
+
+	if requeueMaintenance {
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	// Certificates are issued out-of-band by cert-manager, so check back until they are in place
+	if rh.tlsPending() {
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -275,90 +460,32 @@ func (rh *requestHandler) linkToEnvironment() (requeue bool, err error) {
 }
 
 func (rh *requestHandler) reconcileDatabase() (requeue bool, err error) {
-	adminDB, err := common.GetAdminConnection(rh.reconciler.client)
-	if err != nil {
-		return false, err
-	}
-	defer func() {
-		err = adminDB.Close()
-		if err != nil {
-			rh.logger.Error(err, "adminDB.Close() failed")
-		}
-	}()
-
-	if err := adminDB.Ping(); err != nil {
-		rh.logger.Error(err, "adminDB.Ping() failed")
-		return true, nil
-	}
-
-	proxysqlAdminConn, err := common.GetProxySqlAdminConnection(rh.reconciler.client, rh.site.Namespace)
-	if err != nil {
-		return false, err
-	}
-	defer func() {
-		err = proxysqlAdminConn.Close()
-		if err != nil {
-			rh.logger.Error(err, "proxysqlAdminConn.Close() failed")
-		}
-	}()
-
-	if err := proxysqlAdminConn.Ping(); err != nil {
-		rh.logger.Error(err, "proxysqlAdminConn.Ping() failed")
-		return true, nil
-	}
-
 	siteDB, err := rh.getDB()
 	if err != nil {
 		return false, err
 	}
 
-	_, err = adminDB.Exec("CREATE DATABASE IF NOT EXISTS " + siteDB.Name)
-	if err != nil {
-		return false, err
-	}
-
-	if _, err := adminDB.Exec(fmt.Sprintf("CREATE USER '%s'@'%%'", siteDB.User)); err != nil {
-		// 1396 is ERR_CANNOT_USER in mysql5.6. In this case, it means the user already
-		// exists in the system and cannot be created again.  This is the only error we
-		// are happy to see, so we just log that there is nothing to do and move on.  All
-		// other errors are failure cases.
-		if driverError, ok := err.(*mysql.MySQLError); !ok || driverError.Number != 1396 {
-			return false, err
-		}
-		rh.logger.Info(fmt.Sprintf("User '%s' already exists.", siteDB.User))
+	provisioner := rh.reconciler.newProvisioner(rh.site.Namespace)
+	err = provisioner.EnsureDatabase(siteDB.Name)
+	if err == nil {
+		err = provisioner.EnsureUser(siteDB.User, siteDB.Password)
 	}
-
-	if _, err := adminDB.Exec(fmt.Sprintf("SET PASSWORD FOR '%s'@'%%' = PASSWORD('%s')", siteDB.User, siteDB.Password)); err != nil {
-		return false, err
+	if err == nil {
+		// The password Secret is the source of truth, so the user's password is converged on every reconcile
+		err = provisioner.RotatePassword(siteDB.User, siteDB.Password)
 	}
-
-	_, err = adminDB.Exec(fmt.Sprintf("GRANT ALL PRIVILEGES ON %s.* TO '%s'", siteDB.Name, siteDB.User))
-	if err != nil {
-		return false, err
+	if err == nil {
+		err = provisioner.Grant(siteDB.Name, siteDB.User)
 	}
-
-	_, err = adminDB.Exec(fmt.Sprintf("FLUSH PRIVILEGES"))
 	if err != nil {
-		return false, err
-	}
-
-	if _, err := proxysqlAdminConn.Exec(fmt.Sprintf(`INSERT INTO mysql_users(username,password,default_hostgroup) VALUES ('%s','%s',1)`, siteDB.User, siteDB.Password)); err != nil {
-		if driverError, ok := err.(*mysql.MySQLError); !ok || driverError.Number != 1045 {
-			return false, err
+		if common.IsUnavailable(err) {
+			rh.logger.Info("Database is unavailable", "Error", err.Error())
+			return true, nil
 		}
-		rh.logger.Info(fmt.Sprintf("User '%s' already exists.", siteDB.User))
-	}
-
-	_, err = proxysqlAdminConn.Exec(`LOAD MYSQL USERS TO RUNTIME`)
-	if err != nil {
-		return false, err
-	}
-
-	_, err = proxysqlAdminConn.Exec(`SAVE MYSQL USERS TO DISK`)
-	if err != nil {
 		return false, err
 	}
 
+	rh.status.Database = fn.SiteDatabaseStatus{Name: siteDB.Name, User: siteDB.User}
 	return false, nil
 }
 
@@ -398,7 +525,9 @@ func (r *ReconcileSite) reconcileDbPwdSecret(reqLogger logr.Logger, s *fn.Site)
 	return false, nil
 }
 
-func (r *ReconcileSite) reconcileDomainMap(reqLogger logr.Logger, s *fn.Site) (requeue bool, err error) {
+// reconcileDomainMap maps the given domains of the Site, with the paths Drupal serves it under, to its database in the
+// domain map ConfigMap
+func (r *ReconcileSite) reconcileDomainMap(reqLogger logr.Logger, s *fn.Site, domains []string) (requeue bool, err error) {
 	targetName := fn.DomainMapName
 	targetNamespace := s.Namespace
 	domainMap := &corev1.ConfigMap{}
@@ -411,7 +540,7 @@ func (r *ReconcileSite) reconcileDomainMap(reqLogger logr.Logger, s *fn.Site) (r
 
 	// ensure domainMap is up to date
 	cmdata.Parse(domainMap.Data)
-	if cmdata.EnsureDomainMapPresence(s) {
+	if cmdata.EnsureDomainMapPresence(s.Id(), s.DomainMap(domains)) {
 		reqLogger.Info(fmt.Sprintf("ConfigMap %s out of date. Updating...", fn.DomainMapName))
 		domainMap.Data, err = cmdata.Write()
 		if err != nil {
@@ -457,69 +586,92 @@ func (rh *requestHandler) reconcileDomainDbMapSecret() (requeue bool, err error)
 	return false, nil
 }
 
-func (r *ReconcileSite) updateIngress(reqLogger logr.Logger, s *fn.Site) error {
-	targetName := s.Name
-	targetNamespace := s.Namespace
-	desiredIngAnnotations := map[string]string{
-		"certmanager.k8s.io/cluster-issuer": s.IngressCertIssuer(),
-		"kubernetes.io/ingress.class":       s.IngressClass(),
+// updateIngress routes the given domains of the Site through the given routes, using the routing API in effect. Domains
+// served with supplied certificates are routed by a separate Ingress, without the cert-manager annotation. An Ingress
+// needs at least one rule, so routing objects are deleted when there are no domains to route. Routing objects of other
+// APIs, left from before the routing API changed, are deleted once the new ones are in place.
+func (r *ReconcileSite) updateIngress(reqLogger logr.Logger, s *fn.Site, domains []string, routes []fn.SiteRoute) error {
+	rt, previous, err := r.routers(reqLogger)
+	if err != nil {
+		return err
 	}
-	ing := &extv1b1.Ingress{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: targetName, Namespace: targetNamespace}, ing)
-	if err != nil && errors.IsNotFound(err) {
-		ing = &extv1b1.Ingress{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:        targetName,
-				Namespace:   targetNamespace,
-				Labels:      s.ChildLabels(),
-				Annotations: desiredIngAnnotations,
-			},
-			Spec: extv1b1.IngressSpec{
-				Rules: s.IngressRules(),
-				TLS:   s.IngressTLS(),
-			},
-		}
-		r.associateResourceWithController(reqLogger, ing, s)
 
-		reqLogger.Info("Creating new Ingress", "name", ing.Name, "namespace", ing.Namespace)
-		if err := r.client.Create(context.TODO(), ing); err != nil {
-			return err
-		}
-		return nil
-	} else if err != nil {
-		return err
+	config := operatorconfig.Current()
+	ingressClass := s.IngressClass(config.IngressClass)
+	annotations, err := ingressTLSAnnotations(s, ingressClass)
+	if err != nil {
+		r.recorder.Event(s, corev1.EventTypeWarning, "UnsupportedIngressClass", err.Error())
 	}
+	annotations["kubernetes.io/ingress.class"] = ingressClass
 
-	// ensure ingress is up to date
-	rules, tls, ingAnnotations := ing.Spec.Rules, ing.Spec.TLS, ing.ObjectMeta.Annotations
-	desiredRules, desiredTLS := s.IngressRules(), s.IngressTLS()
-	update := false
-	if !reflect.DeepEqual(rules, desiredRules) {
-		reqLogger.Info("Ingress rules out of date. Updating...")
-		update = true
-		ing.Spec.Rules = desiredRules
+	others, supplied := s.SplitSuppliedCerts(domains)
+	if _, ok := rt.(*httpRouteRouter); ok {
+		if len(s.IngressTLS(domains)) > 0 {
+			r.recorder.Event(s, corev1.EventTypeWarning, "TLSNotApplied",
+				"TLS is terminated by the Gateway HTTPRoutes are attached to, so the TLS settings of the Site don't apply")
+		}
+		others, supplied = domains, nil
 	}
-	if !reflect.DeepEqual(tls, desiredTLS) {
-		reqLogger.Info("Ingress tls out of date. Updating...")
-		update = true
-		ing.Spec.TLS = desiredTLS
+	suppliedAnnotations := map[string]string{}
+	for k, v := range annotations {
+		suppliedAnnotations[k] = v
 	}
-	for anno := range desiredIngAnnotations {
-		if ingAnnotations[anno] != desiredIngAnnotations[anno] {
-			reqLogger.Info("Ingress annotation out of date. Updating...")
-			update = true
-			ing.ObjectMeta.Annotations[anno] = desiredIngAnnotations[anno]
-		}
+	annotations["certmanager.k8s.io/cluster-issuer"] = s.IngressCertIssuer(config.CertIssuer)
+
+	objects := []struct {
+		name        string
+		domains     []string
+		annotations map[string]string
+	}{
+		{s.Name, others, annotations},
+		{s.SuppliedCertIngressName(), supplied, suppliedAnnotations},
 	}
-	if update {
-		if err := r.client.Update(context.TODO(), ing); err != nil {
+	for _, object := range objects {
+		if len(object.domains) == 0 {
+			err = rt.remove(s, object.name)
+		} else {
+			err = rt.apply(s, object.name, object.domains, routes, object.annotations)
+		}
+		if err != nil {
 			return err
 		}
 	}
 
+	for _, old := range previous {
+		for _, object := range objects {
+			if err := old.remove(s, object.name); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// observeRoutes returns what the Site's routing objects publish, including those of the given redirect hosts
+func (r *ReconcileSite) observeRoutes(reqLogger logr.Logger, s *fn.Site, redirectHosts []string) ([]publishedRoutes, error) {
+	rt, _, err := r.routers(reqLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{s.Name, s.SuppliedCertIngressName()}
+	for _, host := range redirectHosts {
+		names = append(names, s.RedirectIngressName(host))
+	}
+
+	var published []publishedRoutes
+	for _, name := range names {
+		routes, err := rt.observe(s, name)
+		if err != nil {
+			return nil, err
+		}
+		if routes != nil {
+			published = append(published, *routes)
+		}
+	}
+	return published, nil
+}
+
 // Removes this site's section from the configmap while leaving the rest of the configmap intact
 func (r *ReconcileSite) finalizeConfigMap(reqLogger logr.Logger, s *fn.Site) error {
 	reqLogger.Info("Cleaning up ConfigMap " + fn.DomainMapName)
@@ -579,16 +731,19 @@ func (r *ReconcileSite) finalizeDomainDbMapSecret(reqLogger logr.Logger, s *fn.S
 	return nil
 }
 
-// For now, this drops the database and removes the user from the cluster.
-// Eventually, this could be used to take a final backup or something similar
+// Drops the database and removes the user from the cluster, unless the Site's deletion policy is Retain or the database
+// belongs to another Site. With the Snapshot policy, finalizeSnapshot has already taken a final backup by the time this
+// runs.
 func (rh *requestHandler) finalizeDatabase() error {
-	adminDB, err := common.GetAdminConnection(rh.reconciler.client)
-	if err != nil {
-		return err
+	if rh.retainDatabase() {
+		return nil
 	}
-	defer adminDB.Close()
-	if err := adminDB.Ping(); err != nil {
+	// A Site that was refused its database because another Site uses it must not drop it
+	if owner, err := rh.databaseOwner(); err != nil {
 		return err
+	} else if owner != nil {
+		rh.logger.Info("Not dropping database used by another Site", "Database", rh.site.DatabaseName(), "Site", owner.Name)
+		return nil
 	}
 
 	siteDB, err := rh.getDB()
@@ -596,57 +751,15 @@ func (rh *requestHandler) finalizeDatabase() error {
 		return err
 	}
 
-	// Cleanup admin DB
-	_, err = adminDB.Exec("DROP DATABASE IF EXISTS " + siteDB.Name)
-	if err != nil {
-		return err
-	}
-
-	if _, err = adminDB.Exec(fmt.Sprintf("DROP USER '%s'@'%%'", siteDB.User)); err != nil {
-		if driverError, ok := err.(*mysql.MySQLError); !ok || driverError.Number != 1396 {
-			return err
-		}
-		rh.logger.Info(fmt.Sprintf("User '%s' already dropped.", siteDB.User))
-	}
-
-	// Cleanup ProxySQL
-	proxysqlAdminConn, err := common.GetProxySqlAdminConnection(rh.reconciler.client, rh.site.Namespace)
-	if err != nil {
-		// Service is not found
-		if errors.IsNotFound(err) {
-			rh.logger.Info("ProxySQL service not found, nothing to do.")
-			return nil
-		}
+	provisioner := rh.reconciler.newProvisioner(rh.site.Namespace)
+	if err := provisioner.DropDatabase(siteDB.Name); err != nil {
 		return err
 	}
-
-	defer proxysqlAdminConn.Close()
-	if err := proxysqlAdminConn.Ping(); err != nil {
-		return err
-	}
-
-	// ERROR 1045 - error thrown by proxysql for almost all cases
-	if _, err := proxysqlAdminConn.Exec(fmt.Sprintf(`DELETE FROM mysql_users WHERE username='%s'`, siteDB.User)); err != nil {
-		if driverError, ok := err.(*mysql.MySQLError); !ok || driverError.Number != 1045 {
-			return err
-		}
-		rh.logger.Info(fmt.Sprintf("User '%s' deleted", siteDB.User))
-	}
-
-	_, err = proxysqlAdminConn.Exec(`LOAD MYSQL USERS TO RUNTIME`)
-	if err != nil {
-		return err
-	}
-
-	_, err = proxysqlAdminConn.Exec(`SAVE MYSQL USERS TO DISK`)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return provisioner.DropUser(siteDB.User)
 }
 
-// Removes the database password secret finalizer.
+// Removes the database password secret finalizer. When the Site's deletion policy is Retain, the secret keeps its
+// finalizer and is orphaned instead, so that the password of the retained database user isn't garbage-collected.
 func (r *ReconcileSite) finalizeDbPwdSecret(reqLogger logr.Logger, s *fn.Site) (requeue bool, err error) {
 	dbPwdSecret := &corev1.Secret{}
 	pwdNameSpace := types.NamespacedName{Namespace: s.Namespace, Name: s.Name + "-password"}
@@ -654,6 +767,23 @@ func (r *ReconcileSite) finalizeDbPwdSecret(reqLogger logr.Logger, s *fn.Site) (
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info(fmt.Sprintf("Database password Secret on %s not found, nothing to do.", pwdNameSpace))
 		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if s.DeletionPolicy() == fn.DeletionPolicyRetain {
+		var owners []metav1.OwnerReference
+		for _, ref := range dbPwdSecret.GetOwnerReferences() {
+			if ref.UID != s.UID {
+				owners = append(owners, ref)
+			}
+		}
+		if len(owners) == len(dbPwdSecret.GetOwnerReferences()) {
+			return false, nil
+		}
+		reqLogger.Info(fmt.Sprintf("Orphaning the database password secret %s of the retained database.", pwdNameSpace))
+		dbPwdSecret.SetOwnerReferences(owners)
+		return false, r.client.Update(context.TODO(), dbPwdSecret)
 	}
 
 	removed := common.RemoveFinalizer(dbPwdSecretFinalizer, dbPwdSecret)
@@ -0,0 +1,210 @@
+package site
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/acquia/fn-drupal-operator/pkg/apis"
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+const testNamespace = "wlgore"
+
+// newTestReconciler returns a ReconcileSite backed by the fake client, provisioning databases with the given
+// FakeDatabaseProvisioner, and the objects a Site depends on
+func newTestReconciler(t *testing.T, provisioner *common.FakeDatabaseProvisioner, objs ...runtime.Object) (*ReconcileSite, client.Client) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objs = append(objs,
+		&fn.DrupalApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "wlgore-app"},
+			Spec:       fn.DrupalApplicationSpec{ImageRepo: "registry.example.com/wlgore"},
+		},
+		&fn.DrupalEnvironment{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+			Spec:       fn.DrupalEnvironmentSpec{Application: "wlgore-app"},
+		},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: fn.DomainMapName, Namespace: testNamespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fn.DomainMapName, Namespace: testNamespace}},
+	)
+	c := &secretDataClient{fake.NewFakeClient(objs...)}
+	return &ReconcileSite{
+		client:      c,
+		apiClient:   c,
+		scheme:      scheme.Scheme,
+		recorder:    record.NewFakeRecorder(100),
+		routingAPIs: routingAPIs{legacyIngress: true},
+		newProvisioner: func(namespace string) common.DatabaseProvisioner {
+			return provisioner
+		},
+	}, c
+}
+
+// secretDataClient merges the stringData of the Secrets it writes into their data, as the API server does, which the
+// fake client doesn't
+type secretDataClient struct {
+	client.Client
+}
+
+func (c *secretDataClient) Create(ctx context.Context, obj runtime.Object) error {
+	mergeStringData(obj)
+	return c.Client.Create(ctx, obj)
+}
+
+func (c *secretDataClient) Update(ctx context.Context, obj runtime.Object) error {
+	mergeStringData(obj)
+	return c.Client.Update(ctx, obj)
+}
+
+func mergeStringData(obj runtime.Object) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok || secret.StringData == nil {
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range secret.StringData {
+		secret.Data[k] = []byte(v)
+	}
+	secret.StringData = nil
+}
+
+// reconcileUntilDone reconciles the Site until the controller neither requeues it nor fails
+func reconcileUntilDone(t *testing.T, r *ReconcileSite, name string) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}}
+	for i := 0; i < 20; i++ {
+		result, err := r.Reconcile(request)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if !result.Requeue && result.RequeueAfter == 0 {
+			return
+		}
+	}
+	t.Fatalf("Site %s still requeued after 20 reconciles", name)
+}
+
+// reconcileDeletion marks the Site deleted, and reconciles it until the controller has removed its finalizers, when the
+// API server would delete it. The fake client deletes objects outright, whatever their finalizers.
+func reconcileDeletion(t *testing.T, r *ReconcileSite, c client.Client, site *fn.Site) {
+	now := metav1.NewTime(time.Now())
+	site.SetDeletionTimestamp(&now)
+	if err := c.Update(context.TODO(), site); err != nil {
+		t.Fatal(err)
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: site.Namespace, Name: site.Name}}
+	for i := 0; i < 20; i++ {
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		found := &fn.Site{}
+		if err := c.Get(context.TODO(), request.NamespacedName, found); err != nil {
+			t.Fatal(err)
+		}
+		if len(found.GetFinalizers()) == 0 {
+			return
+		}
+	}
+	t.Fatalf("Site %s still has finalizers after 20 reconciles", site.Name)
+}
+
+func TestReconcileProvisionsAndDropsDatabase(t *testing.T) {
+	provisioner := common.NewFakeDatabaseProvisioner()
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Environment: "prod",
+			Domains:     []string{"wlgore.example.com"},
+		},
+	}
+	r, c := newTestReconciler(t, provisioner, site)
+
+	reconcileUntilDone(t, r, site.Name)
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name}, site); err != nil {
+		t.Fatal(err)
+	}
+	database, user := site.DatabaseName(), site.DatabaseUser()
+	if !provisioner.Databases[database] {
+		t.Errorf("database %s was not created", database)
+	}
+	if _, ok := provisioner.Users[user]; !ok {
+		t.Errorf("user %s was not created", user)
+	}
+	if !provisioner.Grants[database][user] {
+		t.Errorf("user %s was not granted privileges on database %s", user, database)
+	}
+	if site.Status.Database.Name != database || site.Status.Database.User != user {
+		t.Errorf("status.database is %+v, want %s and %s", site.Status.Database, database, user)
+	}
+
+	reconcileDeletion(t, r, c, site)
+
+	if provisioner.Databases[database] {
+		t.Errorf("database %s was not dropped", database)
+	}
+	if _, ok := provisioner.Users[user]; ok {
+		t.Errorf("user %s was not dropped", user)
+	}
+	if len(provisioner.Grants[database]) > 0 {
+		t.Errorf("grants on database %s were not revoked", database)
+	}
+}
+
+func TestReconcileRetainsDatabaseAndPasswordSecret(t *testing.T) {
+	provisioner := common.NewFakeDatabaseProvisioner()
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace, UID: "wlgore-uid"},
+		Spec: fn.SiteSpec{
+			Environment:    "prod",
+			Domains:        []string{"wlgore.example.com"},
+			DeletionPolicy: fn.DeletionPolicyRetain,
+		},
+	}
+	r, c := newTestReconciler(t, provisioner, site)
+
+	reconcileUntilDone(t, r, site.Name)
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name}, site); err != nil {
+		t.Fatal(err)
+	}
+	database, user := site.DatabaseName(), site.DatabaseUser()
+
+	reconcileDeletion(t, r, c, site)
+
+	if !provisioner.Databases[database] {
+		t.Errorf("database %s was dropped", database)
+	}
+	if _, ok := provisioner.Users[user]; !ok {
+		t.Errorf("user %s was dropped", user)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: site.Name + "-password"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range secret.GetOwnerReferences() {
+		if ref.UID == site.UID {
+			t.Errorf("password Secret is still owned by Site %s", site.Name)
+		}
+	}
+	if !common.HasFinalizer(dbPwdSecretFinalizer, secret) {
+		t.Errorf("password Secret lost its %s finalizer", dbPwdSecretFinalizer)
+	}
+}
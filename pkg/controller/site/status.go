@@ -0,0 +1,235 @@
+package site
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// jobHistoryLimit is the number of on-demand Jobs kept in a Site's status
+const jobHistoryLimit = 10
+
+const (
+	reasonReconciled = "Reconciled"
+	reasonInProgress = "InProgress"
+	reasonFailed     = "ReconcileFailed"
+)
+
+// siteSteps are the conditions that must all be True for a Site to be Ready, in the order they are reconciled
+var siteSteps = []fn.ConditionType{
+	fn.ConditionEnvironmentAvailable,
+	fn.ConditionDomainMapReady,
+	fn.ConditionDatabaseReady,
+	fn.ConditionCronJobsReady,
+	fn.ConditionIngressReady,
+}
+
+// steps returns the reconcile steps of the Site, which include DNS when the operator manages DNS records
+func (rh *requestHandler) steps() []fn.ConditionType {
+	if rh.reconciler.dnsProvider == nil {
+		return siteSteps
+	}
+	return append(append([]fn.ConditionType{}, siteSteps...), fn.ConditionDNSReady)
+}
+
+// recordStep sets the Condition tracking a reconcile step from the result of that step.
+func (rh *requestHandler) recordStep(t fn.ConditionType, requeue bool, err error) {
+	switch {
+	case err != nil:
+		fn.SetCondition(&rh.status.Conditions, t, corev1.ConditionFalse, reasonFailed, err.Error())
+	case requeue:
+		fn.SetCondition(&rh.status.Conditions, t, corev1.ConditionFalse, reasonInProgress, "")
+	default:
+		fn.SetCondition(&rh.status.Conditions, t, corev1.ConditionTrue, reasonReconciled, "")
+	}
+}
+
+// updateStatus derives the Site's phase from its conditions and writes the status subresource if anything changed.
+// It is deferred by Reconcile, so errors are logged rather than returned.
+func (rh *requestHandler) updateStatus() {
+	rh.status.ObservedGeneration = rh.site.Generation
+	rh.status.Phase = rh.phase()
+	fn.SetCondition(&rh.status.Conditions, fn.ConditionReady, fn.ConditionStatusFor(rh.status.Phase == fn.SitePhaseReady),
+		string(rh.status.Phase), "")
+
+	if cmp.Equal(*rh.status, rh.site.Status) {
+		return
+	}
+
+	rh.site.Status = *rh.status
+	if err := rh.reconciler.client.Status().Update(context.TODO(), rh.site); err != nil && !errors.IsNotFound(err) {
+		rh.logger.Error(err, "Failed to update Site status")
+	}
+}
+
+func (rh *requestHandler) phase() fn.SitePhase {
+	if rh.site.GetDeletionTimestamp() != nil {
+		return fn.SitePhaseDeleting
+	}
+
+	for _, t := range rh.steps() {
+		if c := fn.GetCondition(rh.status.Conditions, t); c != nil && c.Reason == reasonFailed {
+			return fn.SitePhaseFailed
+		}
+	}
+	if c := fn.GetCondition(rh.status.Conditions, fn.ConditionInstalled); c != nil && c.Reason == reasonInstallFailed {
+		return fn.SitePhaseFailed
+	}
+
+	if !fn.IsConditionTrue(rh.status.Conditions, fn.ConditionEnvironmentAvailable) {
+		return fn.SitePhaseWaitingForEnvironment
+	}
+	if !fn.IsConditionTrue(rh.status.Conditions, fn.ConditionDatabaseReady) {
+		return fn.SitePhaseProvisioningDatabase
+	}
+	if rh.installRequested() && !fn.IsConditionTrue(rh.status.Conditions, fn.ConditionInstalled) {
+		return fn.SitePhaseInstalling
+	}
+	for _, t := range rh.steps() {
+		if !fn.IsConditionTrue(rh.status.Conditions, t) {
+			return fn.SitePhasePending
+		}
+	}
+	return fn.SitePhaseReady
+}
+
+// observeJobs records the on-demand Jobs of this Site in its status. Finished Jobs are removed by their TTL shortly
+// after completing, so entries are kept until they are pushed out by newer Jobs.
+func (rh *requestHandler) observeJobs() error {
+	jobList := &batchv1.JobList{}
+	listOpts := client.InNamespace(rh.site.Namespace).MatchingLabels(map[string]string{
+		fn.SiteIdLabel: string(rh.site.Id()),
+		"type":         "on-demand",
+	})
+	if err := rh.reconciler.client.List(context.TODO(), listOpts, jobList); err != nil {
+		return err
+	}
+
+	for _, job := range jobList.Items {
+		entry := fn.SiteJobStatus{
+			Name:           job.Name,
+			UID:            job.UID,
+			Executable:     job.Annotations["executable"],
+			Outcome:        JobOutcome(&job),
+			StartTime:      job.Status.StartTime,
+			CompletionTime: job.Status.CompletionTime,
+		}
+		if entry.StartTime == nil {
+			entry.StartTime = job.CreationTimestamp.DeepCopy()
+		}
+
+		found := false
+		for i := range rh.status.Jobs {
+			if rh.status.Jobs[i].UID == entry.UID {
+				rh.status.Jobs[i] = entry
+				found = true
+				break
+			}
+		}
+		if !found {
+			rh.status.Jobs = append(rh.status.Jobs, entry)
+		}
+	}
+
+	// Most recent first
+	sort.SliceStable(rh.status.Jobs, func(i, j int) bool {
+		return rh.status.Jobs[j].StartTime.Before(rh.status.Jobs[i].StartTime)
+	})
+	if len(rh.status.Jobs) > jobHistoryLimit {
+		rh.status.Jobs = rh.status.Jobs[:jobHistoryLimit]
+	}
+
+	return nil
+}
+
+// JobOutcome returns the outcome of a Job from its conditions
+func JobOutcome(job *batchv1.Job) fn.JobOutcome {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return fn.JobSucceeded
+		case batchv1.JobFailed:
+			return fn.JobFailed
+		}
+	}
+	return fn.JobRunning
+}
+
+// observeIngress records the hosts served by the Site's routing objects, whether a certificate is in place for each, and
+// when it expires.
+func (rh *requestHandler) observeIngress() error {
+	type certificate struct {
+		present bool
+		expiry  *metav1.Time
+	}
+	certificates := map[string]certificate{}
+
+	published, err := rh.reconciler.observeRoutes(rh.logger, rh.site, rh.status.RedirectHosts)
+	if err != nil {
+		return err
+	}
+
+	var hosts []fn.SiteHostStatus
+	for _, routes := range published {
+		for _, host := range routes.hosts {
+			hostStatus := fn.SiteHostStatus{Host: host, TLS: fn.TLSDisabled}
+
+			if secretName, ok := routes.tlsSecrets[host]; ok {
+				cert, checked := certificates[secretName]
+				if !checked {
+					secret := &corev1.Secret{}
+					err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: rh.site.Namespace}, secret)
+					if err != nil && !errors.IsNotFound(err) {
+						return err
+					}
+					cert.present = err == nil && len(secret.Data[corev1.TLSCertKey]) > 0
+					if cert.present {
+						if cert.expiry, err = certificateExpiry(secret.Data[corev1.TLSCertKey]); err != nil {
+							rh.logger.Info("Failed to read the certificate of a TLS Secret", "Secret", secretName, "Error", err.Error())
+						}
+					}
+					certificates[secretName] = cert
+				}
+
+				hostStatus.TLSSecretName = secretName
+				hostStatus.CertificateExpiry = cert.expiry
+				switch {
+				case !cert.present:
+					hostStatus.TLS = fn.TLSPending
+				case cert.expiry != nil && cert.expiry.Time.Before(time.Now()):
+					hostStatus.TLS = fn.TLSExpired
+				default:
+					hostStatus.TLS = fn.TLSIssued
+				}
+			}
+
+			hosts = append(hosts, hostStatus)
+		}
+	}
+
+	rh.status.Hosts = hosts
+	return nil
+}
+
+// tlsPending returns true if any of the Site's hosts are still waiting for a certificate to be issued
+func (rh *requestHandler) tlsPending() bool {
+	for _, h := range rh.status.Hosts {
+		if h.TLS == fn.TLSPending {
+			return true
+		}
+	}
+	return false
+}
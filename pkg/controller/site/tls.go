@@ -0,0 +1,163 @@
+package site
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// Annotations of the ingress controllers that redirect HTTP to HTTPS and send HSTS headers
+const (
+	nginxForceSSLRedirect     = "nginx.ingress.kubernetes.io/force-ssl-redirect"
+	nginxConfigurationSnippet = "nginx.ingress.kubernetes.io/configuration-snippet"
+
+	traefikSSLRedirect           = "ingress.kubernetes.io/ssl-redirect"
+	traefikHSTSMaxAge            = "ingress.kubernetes.io/hsts-max-age"
+	traefikHSTSIncludeSubdomains = "ingress.kubernetes.io/hsts-include-subdomains"
+	traefikHSTSPreload           = "ingress.kubernetes.io/hsts-preload"
+)
+
+// tlsAnnotations are the annotations set from spec.httpsRedirect and spec.hsts, which are removed from the Site's
+// Ingresses when they are no longer wanted
+var tlsAnnotations = []string{
+	nginxForceSSLRedirect,
+	nginxConfigurationSnippet,
+	traefikSSLRedirect,
+	traefikHSTSMaxAge,
+	traefikHSTSIncludeSubdomains,
+	traefikHSTSPreload,
+}
+
+// ingressTLSAnnotations returns the annotations that make the ingress controller of the given class redirect HTTP to
+// HTTPS and send the HSTS header, as the Site asks. NGINX and Traefik ingress classes are recognized by their names. For
+// other classes, an error is returned along with an empty map.
+func ingressTLSAnnotations(s *fn.Site, ingressClass string) (map[string]string, error) {
+	annotations := map[string]string{}
+	if !s.Spec.HTTPSRedirect && s.Spec.HSTS == nil {
+		return annotations, nil
+	}
+
+	hsts := s.Spec.HSTS
+	switch {
+	case strings.Contains(ingressClass, "nginx"):
+		if s.Spec.HTTPSRedirect {
+			annotations[nginxForceSSLRedirect] = "true"
+		}
+		if hsts != nil {
+			// more_set_headers replaces the header the controller sends when HSTS is enabled in its ConfigMap
+			annotations[nginxConfigurationSnippet] = fmt.Sprintf("more_set_headers \"Strict-Transport-Security: %s\";\n", hstsValue(hsts))
+		}
+
+	case strings.Contains(ingressClass, "traefik"):
+		if s.Spec.HTTPSRedirect {
+			annotations[traefikSSLRedirect] = "true"
+		}
+		if hsts != nil {
+			annotations[traefikHSTSMaxAge] = strconv.FormatInt(hsts.MaxAge, 10)
+			annotations[traefikHSTSIncludeSubdomains] = strconv.FormatBool(hsts.IncludeSubdomains)
+			annotations[traefikHSTSPreload] = strconv.FormatBool(hsts.Preload)
+		}
+
+	default:
+		return annotations, fmt.Errorf("ingress class %s doesn't support httpsRedirect and hsts, which are ignored", ingressClass)
+	}
+	return annotations, nil
+}
+
+// hstsValue returns the value of the Strict-Transport-Security header
+func hstsValue(hsts *fn.HSTSSpec) string {
+	value := fmt.Sprintf("max-age=%d", hsts.MaxAge)
+	if hsts.IncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if hsts.Preload {
+		value += "; preload"
+	}
+	return value
+}
+
+// certificateExpiry returns the end of the validity of the first certificate in PEM data, which is the certificate of
+// the host in a TLS Secret
+func certificateExpiry(data []byte) (*metav1.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	notAfter := metav1.NewTime(cert.NotAfter)
+	return &notAfter, nil
+}
+
+// seedTLSSecrets copies the certificate of the Site's legacy TLS Secret, which covered all of its domains, into the
+// Secrets of the hosts it covers that don't have one yet. Upgraded Sites keep serving their hosts with it until
+// cert-manager has issued their own certificates.
+func (rh *requestHandler) seedTLSSecrets() error {
+	s := rh.site
+	legacy := &corev1.Secret{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.LegacyTLSSecretName()}, legacy)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(legacy.Data[corev1.TLSCertKey])
+	if block == nil || len(legacy.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+
+	for _, host := range s.Hosts() {
+		name, supplied := s.TLSSecretName(host)
+		if name == "" || supplied || name == legacy.Name || !certificateCovers(cert, host) {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: name}, secret)
+		if err == nil {
+			continue
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+
+		// Like the Secrets cert-manager creates, the copy isn't owned by the Site
+		rh.logger.Info("Copying legacy TLS Secret", "From", legacy.Name, "To", name, "Host", host)
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       legacy.Data[corev1.TLSCertKey],
+				corev1.TLSPrivateKeyKey: legacy.Data[corev1.TLSPrivateKeyKey],
+			},
+		}
+		if err := rh.reconciler.client.Create(context.TODO(), secret); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// certificateCovers returns true if one of the DNS names of the certificate is the host, which may be a wildcard
+func certificateCovers(cert *x509.Certificate, host string) bool {
+	for _, name := range cert.DNSNames {
+		if strings.EqualFold(name, host) {
+			return true
+		}
+	}
+	return !strings.HasPrefix(host, "*") && cert.VerifyHostname(host) == nil
+}
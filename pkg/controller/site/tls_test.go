@@ -0,0 +1,155 @@
+package site
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+// newTestCertificate returns the PEM certificate and key of a self-signed certificate for the given DNS names
+func newTestCertificate(t *testing.T, dnsNames ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTLSSecretName(t *testing.T) {
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Domains: []string{"wlgore.example.com", "www.example.com", "*.example.org", "shop.example.com", "plain.example.com"},
+			Tls:     true,
+			DomainTLS: []fn.DomainTLS{
+				{Domain: "shop.example.com", SecretName: "shop-cert"},
+				{Domain: "plain.example.com", Disabled: true},
+			},
+		},
+	}
+	cases := []struct {
+		domain   string
+		name     string
+		supplied bool
+	}{
+		// The first domain keeps the Secret of Sites created before certificates were per domain
+		{"wlgore.example.com", "wlgore-tls-secret", false},
+		{"www.example.com", "wlgore-www.example.com-tls", false},
+		{"*.example.org", "wlgore-wildcard.example.org-tls", false},
+		{"shop.example.com", "shop-cert", true},
+		{"plain.example.com", "", false},
+	}
+	for _, c := range cases {
+		name, supplied := site.TLSSecretName(c.domain)
+		if name != c.name || supplied != c.supplied {
+			t.Errorf("TLSSecretName(%q) = %q, %t, want %q, %t", c.domain, name, supplied, c.name, c.supplied)
+		}
+	}
+
+	site.Spec.Tls = false
+	if name, _ := site.TLSSecretName("wlgore.example.com"); name != "" {
+		t.Errorf("TLSSecretName of a Site without spec.tls is %q, want none", name)
+	}
+}
+
+func TestSeedTLSSecretsFromLegacySecret(t *testing.T) {
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec: fn.SiteSpec{
+			Environment: "prod",
+			Domains:     []string{"wlgore.example.com", "www.example.com", "new.example.com", "own.example.com"},
+			Tls:         true,
+		},
+	}
+	// The certificate cert-manager issued for all of the Site's domains before it had per-domain certificates
+	certPEM, keyPEM := newTestCertificate(t, "wlgore.example.com", "www.example.com", "own.example.com")
+	legacy := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore-tls-secret", Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}
+	// A certificate cert-manager has already issued for its domain
+	issued := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore-own.example.com-tls", Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("issued"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	r, c := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site, legacy, issued)
+	rh := &requestHandler{reconciler: r, site: site, status: &site.Status, logger: log}
+
+	if err := rh.seedTLSSecrets(); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "wlgore-www.example.com-tls"}, secret); err != nil {
+		t.Fatalf("the Secret of www.example.com wasn't seeded: %v", err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != string(certPEM) || string(secret.Data[corev1.TLSPrivateKeyKey]) != string(keyPEM) {
+		t.Error("the Secret of www.example.com doesn't hold the legacy certificate")
+	}
+	if len(secret.GetOwnerReferences()) > 0 {
+		t.Errorf("the seeded Secret is owned by %v", secret.GetOwnerReferences())
+	}
+
+	// A domain the legacy certificate doesn't cover is left to cert-manager
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "wlgore-new.example.com-tls"}, secret)
+	if !errors.IsNotFound(err) {
+		t.Errorf("the Secret of new.example.com was seeded with a certificate that doesn't cover it: %v", err)
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: issued.Name}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != "issued" {
+		t.Error("an issued certificate was replaced with the legacy one")
+	}
+}
+
+func TestSeedTLSSecretsWithoutLegacySecret(t *testing.T) {
+	site := &fn.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: "wlgore", Namespace: testNamespace},
+		Spec:       fn.SiteSpec{Environment: "prod", Domains: []string{"wlgore.example.com", "www.example.com"}, Tls: true},
+	}
+	r, c := newTestReconciler(t, common.NewFakeDatabaseProvisioner(), site)
+	rh := &requestHandler{reconciler: r, site: site, status: &site.Status, logger: log}
+
+	if err := rh.seedTLSSecrets(); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "wlgore-www.example.com-tls"}, &corev1.Secret{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("a Secret was created without a legacy certificate: %v", err)
+	}
+}
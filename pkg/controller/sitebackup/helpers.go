@@ -0,0 +1,94 @@
+package sitebackup
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// siteObjects is a Site along with the DrupalEnvironment and DrupalApplication it belongs to
+type siteObjects struct {
+	site *fn.Site
+	env  *fn.DrupalEnvironment
+	app  *fn.DrupalApplication
+}
+
+// siteNotReadyError is returned by getSite when the Site exists, but its database can't be backed up or restored yet
+type siteNotReadyError struct {
+	reason string
+}
+
+func (e *siteNotReadyError) Error() string {
+	return e.reason
+}
+
+// getSite returns a Site whose database is ready, with its parents. A NotFound error is returned if the Site doesn't
+// exist, and a siteNotReadyError if it, or its parents, aren't ready.
+func getSite(c client.Client, namespace, name string) (*siteObjects, error) {
+	objs := &siteObjects{
+		site: &fn.Site{},
+		env:  &fn.DrupalEnvironment{},
+		app:  &fn.DrupalApplication{},
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, objs.site); err != nil {
+		return nil, err
+	}
+	if objs.site.GetDeletionTimestamp() != nil {
+		return nil, &siteNotReadyError{reason: fmt.Sprintf("Site %s is being deleted", name)}
+	}
+	if !fn.IsConditionTrue(objs.site.Status.Conditions, fn.ConditionDatabaseReady) {
+		return nil, &siteNotReadyError{reason: fmt.Sprintf("Waiting for the database of Site %s to be ready", name)}
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: objs.site.Spec.Environment}, objs.env); err != nil {
+		return nil, &siteNotReadyError{reason: fmt.Sprintf("Failed to get DrupalEnvironment %s: %v", objs.site.Spec.Environment, err)}
+	}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: objs.env.Spec.Application}, objs.app); err != nil {
+		return nil, &siteNotReadyError{reason: fmt.Sprintf("Failed to get DrupalApplication %s: %v", objs.env.Spec.Application, err)}
+	}
+
+	return objs, nil
+}
+
+// finished returns true if an operation has reached a final phase
+func finished(phase fn.OperationPhase) bool {
+	return phase == fn.OperationSucceeded || phase == fn.OperationFailed
+}
+
+// recordTimes copies the start and completion times of a Job, and how long it took, into the status of an operation
+func recordTimes(job *batchv1.Job, start, completion **metav1.Time, duration **metav1.Duration) {
+	*start = job.Status.StartTime
+	*completion = job.Status.CompletionTime
+
+	end := job.Status.CompletionTime
+	if end == nil {
+		// Failed Jobs have no completion time, so they are timed up to the Failed condition
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				failed := c.LastTransitionTime
+				end = &failed
+			}
+		}
+	}
+	if job.Status.StartTime != nil && end != nil {
+		*duration = &metav1.Duration{Duration: end.Sub(job.Status.StartTime.Time)}
+	}
+}
+
+// failureMessage returns the message of a failed Job's Failed condition
+func failureMessage(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return fmt.Sprintf("Job %s failed: %s", job.Name, c.Message)
+		}
+	}
+	return fmt.Sprintf("Job %s failed", job.Name)
+}
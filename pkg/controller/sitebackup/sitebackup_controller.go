@@ -0,0 +1,260 @@
+package sitebackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/backup"
+	"github.com/acquia/fn-drupal-operator/pkg/controller/site"
+)
+
+var log = logf.Log.WithName("controller_sitebackup")
+
+// Add creates a new SiteBackup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	apiClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileSiteBackup{client: mgr.GetClient(), apiClient: apiClient, scheme: mgr.GetScheme()}, nil
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("sitebackup-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource SiteBackup
+	if err := c.Watch(&source.Kind{Type: &fn.SiteBackup{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to the backup Job owned by a SiteBackup
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &fn.SiteBackup{},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileSiteBackup implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSiteBackup{}
+
+// ReconcileSiteBackup reconciles a SiteBackup object
+type ReconcileSiteBackup struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client client.Client
+	// apiClient reads from the API server rather than the cache, for the Pods of backup Jobs, which would otherwise
+	// need a cluster-wide Pod informer
+	apiClient client.Client
+	scheme    *runtime.Scheme
+}
+
+// backupHandler gets initialized per request to have thread-safe code.
+type backupHandler struct {
+	reconciler *ReconcileSiteBackup
+
+	backup *fn.SiteBackup
+	status *fn.SiteBackupStatus
+	logger logr.Logger
+}
+
+// Reconcile runs a backup Job for a SiteBackup, and records its outcome in the SiteBackup's status. A SiteBackup is
+// only run once; once it has succeeded or failed, it is left alone.
+func (r *ReconcileSiteBackup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Request.Name", request.Name, "Request.Namespace", request.Namespace)
+
+	sb := &fn.SiteBackup{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, sb)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Failed to Get SiteBackup")
+		return reconcile.Result{}, err
+	}
+
+	if finished(sb.Status.Phase) {
+		return reconcile.Result{}, nil
+	}
+
+	bh := backupHandler{
+		reconciler: r,
+		backup:     sb,
+		status:     sb.Status.DeepCopy(),
+		logger:     reqLogger,
+	}
+	defer bh.updateStatus()
+
+	if bh.status.Phase == "" {
+		bh.status.Phase = fn.OperationPending
+	}
+
+	if err := sb.Spec.Target.Validate(); err != nil {
+		bh.status.Phase = fn.OperationFailed
+		bh.status.Message = err.Error()
+		return reconcile.Result{}, nil
+	}
+
+	job := &batchv1.Job{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: sb.Namespace, Name: bh.jobName()}, job)
+	if err != nil && errors.IsNotFound(err) {
+		return bh.createJob()
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, bh.observeJob(job)
+}
+
+func (bh *backupHandler) jobName() string {
+	return bh.backup.Name + "-backup"
+}
+
+// createJob starts the backup Job once the Site's database is ready
+func (bh *backupHandler) createJob() (reconcile.Result, error) {
+	sb := bh.backup
+	target, err := getSite(bh.reconciler.client, sb.Namespace, sb.Spec.Site)
+	if err != nil {
+		return bh.waitForSite(err)
+	}
+
+	archive := backup.Archive{
+		Site:     target.site,
+		SiteURI:  target.site.DrushURI(),
+		Env:      target.env,
+		Method:   sb.BackupMethod(),
+		Target:   sb.Spec.Target,
+		Location: backup.Location(target.site, sb.Name),
+	}
+
+	labels := target.site.ChildLabels()
+	labels["type"] = "backup"
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bh.jobName(),
+			Namespace: sb.Namespace,
+			Labels:    labels,
+		},
+		Spec: archive.BackupJobSpec(site.CustomerJobSpec(target.app, target.env, target.site, nil)),
+	}
+	// The Job is owned by the SiteBackup rather than the Site, so backups can outlive the Site they were taken of
+	if err := controllerutil.SetControllerReference(sb, job, bh.reconciler.scheme); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	bh.logger.Info("Creating backup Job", "Name", job.Name, "Site", target.site.Name, "Location", archive.Location)
+	if err := bh.reconciler.client.Create(context.TODO(), job); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	bh.status.Phase = fn.OperationRunning
+	bh.status.JobName = job.Name
+	bh.status.Location = archive.Location
+	bh.status.Message = ""
+	return reconcile.Result{}, nil
+}
+
+// waitForSite fails the SiteBackup if its Site doesn't exist, and checks back later if it isn't ready yet
+func (bh *backupHandler) waitForSite(err error) (reconcile.Result, error) {
+	if notReady, ok := err.(*siteNotReadyError); ok {
+		bh.status.Message = notReady.Error()
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	if errors.IsNotFound(err) {
+		bh.status.Phase = fn.OperationFailed
+		bh.status.Message = fmt.Sprintf("Site %s not found", bh.backup.Spec.Site)
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, err
+}
+
+// observeJob records the progress of the backup Job. Once it has succeeded, the size and checksum of the archive are
+// read from the termination message of the Job's store container.
+func (bh *backupHandler) observeJob(job *batchv1.Job) error {
+	bh.status.JobName = job.Name
+	recordTimes(job, &bh.status.StartTime, &bh.status.CompletionTime, &bh.status.Duration)
+
+	switch site.JobOutcome(job) {
+	case fn.JobRunning:
+		bh.status.Phase = fn.OperationRunning
+		return nil
+	case fn.JobFailed:
+		bh.status.Phase = fn.OperationFailed
+		bh.status.Message = failureMessage(job)
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	listOpts := client.InNamespace(job.Namespace).MatchingLabels(map[string]string{"job-name": job.Name})
+	if err := bh.reconciler.apiClient.List(context.TODO(), listOpts, pods); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		result, err := backup.ResultFromPod(&pods.Items[i])
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			bh.status.SizeBytes = result.Size
+			bh.status.Checksum = result.Checksum
+			break
+		}
+	}
+	if bh.status.Checksum == "" {
+		bh.status.Message = "The size and checksum of the backup could not be read from its pod"
+	}
+
+	bh.logger.Info("Backup succeeded", "Job", job.Name, "Size", bh.status.SizeBytes)
+	bh.status.Phase = fn.OperationSucceeded
+	return nil
+}
+
+// updateStatus writes the status subresource if anything changed. It is deferred by Reconcile, so errors are logged
+// rather than returned.
+func (bh *backupHandler) updateStatus() {
+	if cmp.Equal(*bh.status, bh.backup.Status) {
+		return
+	}
+
+	bh.backup.Status = *bh.status
+	if err := bh.reconciler.client.Status().Update(context.TODO(), bh.backup); err != nil && !errors.IsNotFound(err) {
+		bh.logger.Error(err, "Failed to update SiteBackup status")
+	}
+}
@@ -0,0 +1,328 @@
+package sitebackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+	"github.com/acquia/fn-drupal-operator/pkg/controller/site"
+)
+
+// cloneFinalizer makes sure the PersistentVolume giving a SiteClone access to the files of another namespace is
+// deleted, since it can't be owned by the SiteClone.
+const cloneFinalizer = "siteclones.fnresources.acquia.io"
+
+var cloneLog = logf.Log.WithName("controller_siteclone")
+
+// AddClone creates a new SiteClone Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func AddClone(mgr manager.Manager) error {
+	c := mgr.GetClient()
+	return addClone(mgr, &ReconcileSiteClone{
+		client: c,
+		scheme: mgr.GetScheme(),
+		newProvisioner: func(namespace string) common.DatabaseProvisioner {
+			return common.NewMySQLProvisioner(c, namespace)
+		},
+	})
+}
+
+// addClone adds a new Controller to mgr with r as the reconcile.Reconciler
+func addClone(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("siteclone-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource SiteClone
+	if err := c.Watch(&source.Kind{Type: &fn.SiteClone{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to the files and sanitize Jobs owned by a SiteClone
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &fn.SiteClone{},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileSiteClone implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSiteClone{}
+
+// ReconcileSiteClone reconciles a SiteClone object
+type ReconcileSiteClone struct {
+	client client.Client
+	scheme *runtime.Scheme
+
+	// newProvisioner returns the DatabaseProvisioner used to copy the database. It can be replaced to test the
+	// controller without a database server.
+	newProvisioner func(namespace string) common.DatabaseProvisioner
+}
+
+// cloneHandler gets initialized per request to have thread-safe code.
+type cloneHandler struct {
+	reconciler *ReconcileSiteClone
+
+	clone  *fn.SiteClone
+	status *fn.SiteCloneStatus
+	logger logr.Logger
+
+	source *siteObjects
+	target *siteObjects
+}
+
+// Reconcile copies the database and files of the source Site of a SiteClone into its target Site, then runs the
+// sanitize command, if any. Each step is recorded in the SiteClone's status. The database is copied one table per
+// reconcile, so its progress can be reported. A SiteClone is only run once; once it has succeeded or failed, it is left
+// alone.
+func (r *ReconcileSiteClone) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := cloneLog.WithValues("Request.Name", request.Name, "Request.Namespace", request.Namespace)
+
+	sc := &fn.SiteClone{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, sc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Failed to Get SiteClone")
+		return reconcile.Result{}, err
+	}
+
+	ch := cloneHandler{
+		reconciler: r,
+		clone:      sc,
+		status:     sc.Status.DeepCopy(),
+		logger:     reqLogger,
+	}
+
+	if sc.GetDeletionTimestamp() != nil || finished(sc.Status.Phase) {
+		return reconcile.Result{}, ch.cleanUp()
+	}
+
+	defer ch.updateStatus()
+
+	if ch.status.Phase == "" {
+		ch.status.Phase = fn.OperationPending
+	}
+
+	if sc.SourceNamespace() == sc.Namespace && sc.Spec.Source.Name == sc.Spec.Site {
+		return ch.fail("The source and target Site must differ")
+	}
+
+	if ch.target, err = getSite(r.client, sc.Namespace, sc.Spec.Site); err != nil {
+		return ch.waitForSite(err, sc.Spec.Site)
+	}
+	if ch.source, err = getSite(r.client, sc.SourceNamespace(), sc.Spec.Source.Name); err != nil {
+		return ch.waitForSite(err, sc.SourceNamespace()+"/"+sc.Spec.Source.Name)
+	}
+
+	// Database names are derived from Site names only, so Sites with the same name in different namespaces share one
+	if ch.source.site.DatabaseName() == ch.target.site.DatabaseName() {
+		return ch.fail(fmt.Sprintf("The source and target Site share the database %s", ch.target.site.DatabaseName()))
+	}
+
+	if common.AddFinalizer(cloneFinalizer, sc) {
+		return reconcile.Result{Requeue: true}, r.client.Update(context.TODO(), sc)
+	}
+
+	if ch.status.Phase == fn.OperationPending {
+		ch.logger.Info("Starting clone", "Source", sc.SourceNamespace()+"/"+sc.Spec.Source.Name, "Site", sc.Spec.Site)
+		now := metav1.Now()
+		ch.status.Phase = fn.OperationRunning
+		ch.status.StartTime = &now
+		ch.status.Message = ""
+	}
+
+	steps := []struct {
+		step fn.CloneStep
+		run  func() (reconcile.Result, bool, error)
+	}{
+		{fn.CloneStepCopyingDatabase, ch.copyDatabase},
+		{fn.CloneStepCopyingFiles, ch.copyFiles},
+		{fn.CloneStepSanitizing, ch.sanitize},
+	}
+	for _, s := range steps {
+		if ch.done(s.step) {
+			continue
+		}
+		ch.status.Step = s.step
+		if result, done, err := s.run(); !done || err != nil {
+			return result, err
+		}
+	}
+
+	ch.logger.Info("Clone succeeded")
+	now := metav1.Now()
+	ch.status.Phase = fn.OperationSucceeded
+	ch.status.Step = ""
+	ch.status.CompletionTime = &now
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// done returns true if a step was completed by an earlier reconcile
+func (ch *cloneHandler) done(step fn.CloneStep) bool {
+	order := map[fn.CloneStep]int{
+		fn.CloneStepCopyingDatabase: 1,
+		fn.CloneStepCopyingFiles:    2,
+		fn.CloneStepSanitizing:      3,
+	}
+	return order[step] < order[ch.status.Step]
+}
+
+func (ch *cloneHandler) fail(message string) (reconcile.Result, error) {
+	ch.status.Phase = fn.OperationFailed
+	ch.status.Message = message
+	now := metav1.Now()
+	ch.status.CompletionTime = &now
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// waitForSite fails the SiteClone if one of its Sites doesn't exist, and checks back later if it isn't ready yet
+func (ch *cloneHandler) waitForSite(err error, name string) (reconcile.Result, error) {
+	if notReady, ok := err.(*siteNotReadyError); ok {
+		ch.status.Message = notReady.Error()
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+	if errors.IsNotFound(err) {
+		return ch.fail(fmt.Sprintf("Site %s not found", name))
+	}
+	return reconcile.Result{}, err
+}
+
+// copyDatabase copies the next table of the source Site's database into the target Site's database. The target
+// database is emptied first, so tables that aren't in the source are removed. Returns done=true once all tables have
+// been copied.
+func (ch *cloneHandler) copyDatabase() (result reconcile.Result, done bool, err error) {
+	provisioner := ch.reconciler.newProvisioner(ch.clone.Namespace)
+	sourceDB := ch.source.site.DatabaseName()
+	targetDB := ch.target.site.DatabaseName()
+
+	defer func() {
+		if common.IsUnavailable(err) {
+			ch.status.Message = err.Error()
+			result, err = reconcile.Result{RequeueAfter: time.Second * 10}, nil
+		}
+	}()
+
+	tables, err := provisioner.ListTables(sourceDB)
+	if err != nil {
+		return reconcile.Result{}, false, err
+	}
+
+	if ch.status.TablesTotal == 0 && ch.status.TablesCopied == 0 {
+		ch.logger.Info("Emptying database", "Database", targetDB)
+		if err := provisioner.DropTables(targetDB); err != nil {
+			return reconcile.Result{}, false, err
+		}
+	}
+	ch.status.TablesTotal = int32(len(tables))
+
+	if int(ch.status.TablesCopied) >= len(tables) {
+		return reconcile.Result{}, true, nil
+	}
+
+	table := tables[ch.status.TablesCopied]
+	if err := provisioner.CopyTable(sourceDB, targetDB, table); err != nil {
+		return reconcile.Result{}, false, fmt.Errorf("failed to copy table %s: %v", table, err)
+	}
+	ch.status.TablesCopied++
+	ch.status.Message = ""
+	return reconcile.Result{Requeue: true}, false, nil
+}
+
+// sanitize runs the sanitize command against the target Site, if one is given. Returns done=true once it has
+// succeeded.
+func (ch *cloneHandler) sanitize() (reconcile.Result, bool, error) {
+	if ch.clone.Spec.Sanitize == nil || len(ch.clone.Spec.Sanitize.Command) == 0 {
+		return reconcile.Result{}, true, nil
+	}
+
+	t := ch.target
+	labels := t.site.ChildLabels()
+	labels["type"] = "sanitize"
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ch.clone.Name + "-sanitize",
+			Namespace: ch.clone.Namespace,
+			Labels:    labels,
+		},
+		Spec: site.CustomerJobSpec(t.app, t.env, t.site, ch.clone.Spec.Sanitize.Command),
+	}
+	ch.status.SanitizeJobName = job.Name
+	return ch.runJob(job)
+}
+
+// runJob creates the Job if it doesn't exist yet, and returns done=true once it has succeeded. The SiteClone fails if
+// the Job does.
+func (ch *cloneHandler) runJob(job *batchv1.Job) (reconcile.Result, bool, error) {
+	found := &batchv1.Job{}
+	err := ch.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(ch.clone, job, ch.reconciler.scheme); err != nil {
+			return reconcile.Result{}, false, err
+		}
+		ch.logger.Info("Creating Job", "Name", job.Name)
+		return reconcile.Result{}, false, ch.reconciler.client.Create(context.TODO(), job)
+	} else if err != nil {
+		return reconcile.Result{}, false, err
+	}
+
+	switch site.JobOutcome(found) {
+	case fn.JobSucceeded:
+		return reconcile.Result{}, true, nil
+	case fn.JobFailed:
+		result, err := ch.fail(failureMessage(found))
+		return result, false, err
+	}
+	return reconcile.Result{}, false, nil
+}
+
+// cleanUp deletes the PersistentVolume used to copy files from another namespace, then removes the SiteClone's
+// finalizer
+func (ch *cloneHandler) cleanUp() error {
+	if !common.HasFinalizer(cloneFinalizer, ch.clone) {
+		return nil
+	}
+	if err := ch.deleteSourceFilesVolume(); err != nil {
+		return err
+	}
+
+	common.RemoveFinalizer(cloneFinalizer, ch.clone)
+	return ch.reconciler.client.Update(context.TODO(), ch.clone)
+}
+
+// updateStatus writes the status subresource if anything changed. It is deferred by Reconcile, so errors are logged
+// rather than returned.
+func (ch *cloneHandler) updateStatus() {
+	if cmp.Equal(*ch.status, ch.clone.Status) {
+		return
+	}
+
+	ch.clone.Status = *ch.status
+	if err := ch.reconciler.client.Status().Update(context.TODO(), ch.clone); err != nil && !errors.IsNotFound(err) {
+		ch.logger.Error(err, "Failed to update SiteClone status")
+	}
+}
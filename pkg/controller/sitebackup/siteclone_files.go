@@ -0,0 +1,215 @@
+package sitebackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/acquia/fn-drupal-operator/pkg/controller/site"
+	"github.com/acquia/fn-drupal-operator/pkg/customercontainer"
+)
+
+// The files of a Site belong to its DrupalEnvironment: they are a subpath of the environment's EFS volume. A pod can
+// only mount PersistentVolumeClaims in its own namespace, so to copy files from another namespace, the source
+// environment's PersistentVolume is duplicated into a read-only PersistentVolume pre-bound to a claim in the
+// SiteClone's namespace. It has the Retain reclaim policy, so deleting it leaves the files in place.
+
+const (
+	sourceFilesVolumeName = "source-files"
+	sourceFilesDir        = "/source"
+)
+
+const copyFilesScript = `set -eu
+find "$FILES_DIR" -mindepth 1 -delete
+cp -a /source/. "$FILES_DIR/"
+`
+
+func (ch *cloneHandler) sourceFilesClaimName() string {
+	return ch.clone.Name + "-source-files"
+}
+
+// sourceFilesPVName is cluster-wide, so it includes the SiteClone's namespace
+func (ch *cloneHandler) sourceFilesPVName() string {
+	return ch.clone.Namespace + "-" + ch.clone.Name + "-source-files"
+}
+
+// copyFiles runs a Job replacing the files of the target Site's environment with those of the source Site's
+// environment. Returns done=true once it has succeeded.
+func (ch *cloneHandler) copyFiles() (reconcile.Result, bool, error) {
+	if ch.clone.Spec.SkipFiles {
+		return reconcile.Result{}, true, nil
+	}
+
+	s, t := ch.source, ch.target
+	sourceClaim := customercontainer.FilesVolume(s.env).PersistentVolumeClaim.ClaimName
+	sourceMount := customercontainer.FilesVolumeMount(s.env)
+	targetMount := customercontainer.FilesVolumeMount(t.env)
+
+	if s.env.Namespace == t.env.Namespace {
+		if sourceClaim == customercontainer.FilesVolume(t.env).PersistentVolumeClaim.ClaimName && sourceMount.SubPath == targetMount.SubPath {
+			ch.status.Message = "The source and target Site are in the same environment, so they share their files"
+			return reconcile.Result{}, true, nil
+		}
+	} else {
+		if requeue, err := ch.ensureSourceFilesVolume(sourceClaim); requeue || err != nil {
+			return reconcile.Result{RequeueAfter: time.Second * 10}, false, err
+		}
+		sourceClaim = ch.sourceFilesClaimName()
+	}
+
+	labels := t.site.ChildLabels()
+	labels["type"] = "clone"
+
+	spec := site.CustomerJobSpec(t.app, t.env, t.site, []string{"sh", "-c", copyFilesScript})
+	container := &spec.Template.Spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{Name: "FILES_DIR", Value: targetMount.MountPath})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      sourceFilesVolumeName,
+		MountPath: sourceFilesDir,
+		SubPath:   sourceMount.SubPath,
+		ReadOnly:  true,
+	})
+	spec.Template.Spec.Volumes = append(spec.Template.Spec.Volumes, corev1.Volume{
+		Name: sourceFilesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: sourceClaim, ReadOnly: true},
+		},
+	})
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ch.clone.Name + "-files",
+			Namespace: ch.clone.Namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+	ch.status.FilesJobName = job.Name
+
+	result, done, err := ch.runJob(job)
+	if done {
+		err = ch.deleteSourceFilesVolume()
+	}
+	return result, done, err
+}
+
+// ensureSourceFilesVolume creates the PersistentVolume and claim giving access to the files of the source Site's
+// environment, in the SiteClone's namespace. Returns requeue=true while the source claim isn't bound.
+func (ch *cloneHandler) ensureSourceFilesVolume(sourceClaim string) (requeue bool, err error) {
+	c := ch.reconciler.client
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: ch.source.env.Namespace, Name: sourceClaim}, pvc)
+	if err != nil {
+		return false, fmt.Errorf("failed to get PersistentVolumeClaim %s/%s: %v", ch.source.env.Namespace, sourceClaim, err)
+	}
+	if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+		ch.status.Message = fmt.Sprintf("Waiting for PersistentVolumeClaim %s/%s to be bound", pvc.Namespace, pvc.Name)
+		return true, nil
+	}
+	sourcePV := &corev1.PersistentVolume{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: pvc.Spec.VolumeName}, sourcePV); err != nil {
+		return false, err
+	}
+
+	readOnly := []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}
+
+	pv := &corev1.PersistentVolume{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: ch.sourceFilesPVName()}, pv)
+	if err != nil && errors.IsNotFound(err) {
+		pv = &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ch.sourceFilesPVName(),
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:                      sourcePV.Spec.Capacity,
+				PersistentVolumeSource:        *sourcePV.Spec.PersistentVolumeSource.DeepCopy(),
+				AccessModes:                   readOnly,
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				StorageClassName:              sourcePV.Spec.StorageClassName,
+				MountOptions:                  sourcePV.Spec.MountOptions,
+				VolumeMode:                    sourcePV.Spec.VolumeMode,
+				ClaimRef: &corev1.ObjectReference{
+					Namespace: ch.clone.Namespace,
+					Name:      ch.sourceFilesClaimName(),
+				},
+			},
+		}
+		ch.logger.Info("Creating PV", "Name", pv.Name, "Source", sourcePV.Name)
+		if err := c.Create(context.TODO(), pv); err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	}
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: ch.clone.Namespace, Name: ch.sourceFilesClaimName()}, claim)
+	if err != nil && errors.IsNotFound(err) {
+		storageClass := sourcePV.Spec.StorageClassName
+		claim = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ch.sourceFilesClaimName(),
+				Namespace: ch.clone.Namespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      readOnly,
+				StorageClassName: &storageClass,
+				VolumeName:       pv.Name,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: sourcePV.Spec.Capacity[corev1.ResourceStorage],
+					},
+				},
+			},
+		}
+		if err := controllerutil.SetControllerReference(ch.clone, claim, ch.reconciler.scheme); err != nil {
+			return false, err
+		}
+		ch.logger.Info("Creating PVC", "Namespace", claim.Namespace, "Name", claim.Name)
+		if err := c.Create(context.TODO(), claim); err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// deleteSourceFilesVolume deletes the PersistentVolume and claim created by ensureSourceFilesVolume, if they exist
+func (ch *cloneHandler) deleteSourceFilesVolume() error {
+	c := ch.reconciler.client
+
+	claim := &corev1.PersistentVolumeClaim{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: ch.clone.Namespace, Name: ch.sourceFilesClaimName()}, claim)
+	if err == nil && claim.DeletionTimestamp == nil {
+		ch.logger.Info("Deleting PVC", "Namespace", claim.Namespace, "Name", claim.Name)
+		if err := c.Delete(context.TODO(), claim); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	pv := &corev1.PersistentVolume{}
+	err = c.Get(context.TODO(), types.NamespacedName{Name: ch.sourceFilesPVName()}, pv)
+	if err == nil && pv.DeletionTimestamp == nil {
+		ch.logger.Info("Deleting PV", "Name", pv.Name)
+		if err := c.Delete(context.TODO(), pv); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
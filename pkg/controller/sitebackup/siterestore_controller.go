@@ -0,0 +1,228 @@
+package sitebackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fn "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/backup"
+	"github.com/acquia/fn-drupal-operator/pkg/controller/site"
+)
+
+var restoreLog = logf.Log.WithName("controller_siterestore")
+
+// AddRestore creates a new SiteRestore Controller and adds it to the Manager. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func AddRestore(mgr manager.Manager) error {
+	return addRestore(mgr, &ReconcileSiteRestore{client: mgr.GetClient(), scheme: mgr.GetScheme()})
+}
+
+// addRestore adds a new Controller to mgr with r as the reconcile.Reconciler
+func addRestore(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("siterestore-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource SiteRestore
+	if err := c.Watch(&source.Kind{Type: &fn.SiteRestore{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch for changes to the restore Job owned by a SiteRestore
+	if err := c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &fn.SiteRestore{},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileSiteRestore implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileSiteRestore{}
+
+// ReconcileSiteRestore reconciles a SiteRestore object
+type ReconcileSiteRestore struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// restoreHandler gets initialized per request to have thread-safe code.
+type restoreHandler struct {
+	reconciler *ReconcileSiteRestore
+
+	restore *fn.SiteRestore
+	status  *fn.SiteRestoreStatus
+	logger  logr.Logger
+}
+
+// Reconcile runs a restore Job for a SiteRestore once its SiteBackup has succeeded, and records its outcome in the
+// SiteRestore's status. A SiteRestore is only run once; once it has succeeded or failed, it is left alone.
+func (r *ReconcileSiteRestore) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	reqLogger := restoreLog.WithValues("Request.Name", request.Name, "Request.Namespace", request.Namespace)
+
+	sr := &fn.SiteRestore{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, sr)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		reqLogger.Error(err, "Failed to Get SiteRestore")
+		return reconcile.Result{}, err
+	}
+
+	if finished(sr.Status.Phase) {
+		return reconcile.Result{}, nil
+	}
+
+	rh := restoreHandler{
+		reconciler: r,
+		restore:    sr,
+		status:     sr.Status.DeepCopy(),
+		logger:     reqLogger,
+	}
+	defer rh.updateStatus()
+
+	if rh.status.Phase == "" {
+		rh.status.Phase = fn.OperationPending
+	}
+
+	job := &batchv1.Job{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: sr.Namespace, Name: rh.jobName()}, job)
+	if err != nil && errors.IsNotFound(err) {
+		return rh.createJob()
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	rh.observeJob(job)
+	return reconcile.Result{}, nil
+}
+
+func (rh *restoreHandler) jobName() string {
+	return rh.restore.Name + "-restore"
+}
+
+func (rh *restoreHandler) fail(message string) (reconcile.Result, error) {
+	rh.status.Phase = fn.OperationFailed
+	rh.status.Message = message
+	return reconcile.Result{}, nil
+}
+
+// createJob starts the restore Job once the SiteBackup has succeeded and the target Site's database is ready
+func (rh *restoreHandler) createJob() (reconcile.Result, error) {
+	sr := rh.restore
+
+	sb := &fn.SiteBackup{}
+	err := rh.reconciler.client.Get(context.TODO(), types.NamespacedName{Namespace: sr.Namespace, Name: sr.Spec.Backup}, sb)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return rh.fail(fmt.Sprintf("SiteBackup %s not found", sr.Spec.Backup))
+		}
+		return reconcile.Result{}, err
+	}
+	switch sb.Status.Phase {
+	case fn.OperationSucceeded:
+	case fn.OperationFailed:
+		return rh.fail(fmt.Sprintf("SiteBackup %s failed", sb.Name))
+	default:
+		rh.status.Message = fmt.Sprintf("Waiting for SiteBackup %s to succeed", sb.Name)
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	target, err := getSite(rh.reconciler.client, sr.Namespace, sr.Spec.Site)
+	if err != nil {
+		if notReady, ok := err.(*siteNotReadyError); ok {
+			rh.status.Message = notReady.Error()
+			return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+		}
+		if errors.IsNotFound(err) {
+			return rh.fail(fmt.Sprintf("Site %s not found", sr.Spec.Site))
+		}
+		return reconcile.Result{}, err
+	}
+
+	archive := backup.Archive{
+		Site:     target.site,
+		SiteURI:  target.site.DrushURI(),
+		Env:      target.env,
+		Method:   sb.BackupMethod(),
+		Target:   sb.Spec.Target,
+		Location: sb.Status.Location,
+		Checksum: sb.Status.Checksum,
+	}
+
+	labels := target.site.ChildLabels()
+	labels["type"] = "restore"
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rh.jobName(),
+			Namespace: sr.Namespace,
+			Labels:    labels,
+		},
+		Spec: archive.RestoreJobSpec(site.CustomerJobSpec(target.app, target.env, target.site, nil)),
+	}
+	if err := controllerutil.SetControllerReference(sr, job, rh.reconciler.scheme); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	rh.logger.Info("Creating restore Job", "Name", job.Name, "Site", target.site.Name, "Backup", sb.Name)
+	if err := rh.reconciler.client.Create(context.TODO(), job); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	rh.status.Phase = fn.OperationRunning
+	rh.status.JobName = job.Name
+	rh.status.Message = ""
+	return reconcile.Result{}, nil
+}
+
+// observeJob records the progress of the restore Job
+func (rh *restoreHandler) observeJob(job *batchv1.Job) {
+	rh.status.JobName = job.Name
+	recordTimes(job, &rh.status.StartTime, &rh.status.CompletionTime, &rh.status.Duration)
+
+	switch site.JobOutcome(job) {
+	case fn.JobSucceeded:
+		rh.logger.Info("Restore succeeded", "Job", job.Name)
+		rh.status.Phase = fn.OperationSucceeded
+	case fn.JobFailed:
+		rh.status.Phase = fn.OperationFailed
+		rh.status.Message = failureMessage(job)
+	default:
+		rh.status.Phase = fn.OperationRunning
+	}
+}
+
+// updateStatus writes the status subresource if anything changed. It is deferred by Reconcile, so errors are logged
+// rather than returned.
+func (rh *restoreHandler) updateStatus() {
+	if cmp.Equal(*rh.status, rh.restore.Status) {
+		return
+	}
+
+	rh.restore.Status = *rh.status
+	if err := rh.reconciler.client.Status().Update(context.TODO(), rh.restore); err != nil && !errors.IsNotFound(err) {
+		rh.logger.Error(err, "Failed to update SiteRestore status")
+	}
+}
@@ -7,13 +7,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
 )
 
 const (
-	ECRRepoRoot           = "881217801864.dkr.ecr.us-east-1.amazonaws.com/" // TODO: pull from environment variable
-	CustomerECRRepoPrefix = "customer/"
-	CustomerECRRepoRoot   = ECRRepoRoot + CustomerECRRepoPrefix
-
 	sharedFilesName = "shared-files"
 )
 
@@ -21,15 +18,35 @@ func prefix(e *fnv1alpha1.DrupalEnvironment) string {
 	return string(e.Id())
 }
 
-func ImageName(a *fnv1alpha1.DrupalApplication, e *fnv1alpha1.DrupalEnvironment) (imageName string) {
+// ImageName returns the customer image of an environment: the one its current release pinned to a digest, or, until
+// the tag of the spec has been resolved, the image at that tag
+func ImageName(a *fnv1alpha1.DrupalApplication, e *fnv1alpha1.DrupalEnvironment) string {
+	image := TagImageName(a, e)
+	if e.Status.Images != nil {
+		return PinnedImage(image, e.Spec.RollbackTo != nil, e.Status.Images.Drupal)
+	}
+	return image
+}
+
+// TagImageName returns the customer image at the tag of the environment's spec
+func TagImageName(a *fnv1alpha1.DrupalApplication, e *fnv1alpha1.DrupalEnvironment) (imageName string) {
 	if a.Spec.ImageRepo == "" {
-		imageName = fmt.Sprintf(CustomerECRRepoRoot+"%v:%v", a.Spec.GitRepo, e.Spec.Drupal.Tag)
+		imageName = registry.Current().CustomerImage(a.Spec.GitRepo, e.Spec.Drupal.Tag)
 	} else {
 		imageName = fmt.Sprintf("%v:%v", a.Spec.ImageRepo, e.Spec.Drupal.Tag)
 	}
 	return
 }
 
+// PinnedImage returns the image resolved for the current release of an environment, if it was resolved from the image
+// at the tag of the spec or a rollback is in effect, and the image at the tag otherwise
+func PinnedImage(tagImage string, rollback bool, resolved fnv1alpha1.ResolvedImage) string {
+	if resolved.Digest == "" || (resolved.Image != tagImage && !rollback) {
+		return tagImage
+	}
+	return resolved.Pinned()
+}
+
 func FilesVolume(e *fnv1alpha1.DrupalEnvironment) v1.Volume {
 	return v1.Volume{
 		Name: sharedFilesName,
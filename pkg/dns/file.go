@@ -0,0 +1,143 @@
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileProvider is a fake DNSProvider that keeps the records in a file, in zone file syntax with one record per line,
+// for developing the operator without a DNS server. Lines it doesn't understand are dropped when the file is written.
+type FileProvider struct {
+	mu sync.Mutex
+
+	// Path is the file holding the records. It is created by the first update.
+	Path string
+	// Zone limits the domains the provider manages, when set
+	Zone string
+	// TTL is the TTL of records that don't set one
+	TTL uint32
+}
+
+var _ DNSProvider = &FileProvider{}
+
+// NewFileProvider returns a FileProvider writing to the given file
+func NewFileProvider(path, zone string, ttl uint32) *FileProvider {
+	return &FileProvider{Path: path, Zone: normalize(zone), TTL: ttl}
+}
+
+func (f *FileProvider) Manages(domain string) bool {
+	return inZone(domain, f.Zone)
+}
+
+func (f *FileProvider) EnsureRecord(r Record) error {
+	if !f.Manages(r.Name) {
+		return fmt.Errorf("%s isn't in zone %s", r.Name, f.Zone)
+	}
+	switch {
+	case r.Type != RecordA && r.Type != RecordCNAME:
+		return fmt.Errorf("unsupported record type %q", r.Type)
+	case len(r.Targets) == 0:
+		return fmt.Errorf("the %s record of %s has no targets", r.Type, r.Name)
+	case r.Type == RecordCNAME && len(r.Targets) != 1:
+		return fmt.Errorf("a CNAME record needs exactly one target, found %d", len(r.Targets))
+	}
+	if r.TTL == 0 {
+		r.TTL = f.TTL
+	}
+
+	return f.update(func(records map[string]Record) {
+		r.Name = normalize(r.Name)
+		records[r.Name] = r
+	})
+}
+
+func (f *FileProvider) DeleteRecord(domain string) error {
+	if !f.Manages(domain) {
+		return fmt.Errorf("%s isn't in zone %s", domain, f.Zone)
+	}
+
+	return f.update(func(records map[string]Record) {
+		delete(records, normalize(domain))
+	})
+}
+
+// Records returns the records in the file, by domain
+func (f *FileProvider) Records() (map[string]Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.read()
+}
+
+func (f *FileProvider) update(change func(map[string]Record)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.read()
+	if err != nil {
+		return err
+	}
+	change(records)
+	return f.write(records)
+}
+
+func (f *FileProvider) read() (map[string]Record, error) {
+	records := map[string]Record{}
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil && os.IsNotExist(err) {
+		return records, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// <name>. <ttl> IN <type> <target>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || fields[2] != "IN" {
+			continue
+		}
+		ttl, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		name, rrtype, target := normalize(fields[0]), RecordType(fields[3]), fields[4]
+		if rrtype == RecordCNAME {
+			target = normalize(target)
+		} else if rrtype != RecordA {
+			continue
+		}
+
+		r := records[name]
+		r.Name, r.Type, r.TTL = name, rrtype, uint32(ttl)
+		r.Targets = append(r.Targets, target)
+		records[name] = r
+	}
+	return records, scanner.Err()
+}
+
+func (f *FileProvider) write(records map[string]Record) error {
+	names := make([]string, 0, len(records))
+	for name := range records {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		r := records[name]
+		for _, target := range r.Targets {
+			if r.Type == RecordCNAME {
+				target = normalize(target) + "."
+			}
+			fmt.Fprintf(&b, "%s. %d IN %s %s\n", name, r.TTL, r.Type, target)
+		}
+	}
+	return ioutil.WriteFile(f.Path, b.Bytes(), 0644)
+}
@@ -0,0 +1,138 @@
+package dns
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestFileProvider returns a FileProvider for the zone example.com, writing to a file in a temporary directory
+func newTestFileProvider(t *testing.T) (*FileProvider, func()) {
+	dir, err := ioutil.TempDir("", "dns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewFileProvider(filepath.Join(dir, "zone"), "Example.com.", 300), func() { os.RemoveAll(dir) }
+}
+
+func TestFileProviderManages(t *testing.T) {
+	f, cleanup := newTestFileProvider(t)
+	defer cleanup()
+
+	for domain, want := range map[string]bool{
+		"example.com":          true,
+		"wlgore.example.com.":  true,
+		"WLGORE.Example.com":   true,
+		"example.org":          false,
+		"wlgoreexample.com":    false,
+		"example.com.evil.org": false,
+	} {
+		if got := f.Manages(domain); got != want {
+			t.Errorf("Manages(%q) = %t, want %t", domain, got, want)
+		}
+	}
+}
+
+func TestFileProviderRecords(t *testing.T) {
+	f, cleanup := newTestFileProvider(t)
+	defer cleanup()
+
+	if err := f.EnsureRecord(Record{Name: "WLGORE.example.com.", Type: RecordA, Targets: []string{"10.0.0.1", "10.0.0.2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.EnsureRecord(Record{Name: "www.example.com", Type: RecordCNAME, Targets: []string{"LB.example.net."}, TTL: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFile := `wlgore.example.com. 300 IN A 10.0.0.1
+wlgore.example.com. 300 IN A 10.0.0.2
+www.example.com. 60 IN CNAME lb.example.net.
+`
+	if string(data) != wantFile {
+		t.Errorf("zone file is\n%s\nwant\n%s", data, wantFile)
+	}
+
+	// A record of another type replaces the existing one
+	if err := f.EnsureRecord(Record{Name: "www.example.com", Type: RecordA, Targets: []string{"10.0.0.3"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DeleteRecord("wlgore.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting a missing record succeeds
+	if err := f.DeleteRecord("missing.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := f.Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Record{
+		"www.example.com": {Name: "www.example.com", Type: RecordA, Targets: []string{"10.0.0.3"}, TTL: 300},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records are %+v, want %+v", records, want)
+	}
+}
+
+func TestFileProviderDropsUnknownLines(t *testing.T) {
+	f, cleanup := newTestFileProvider(t)
+	defer cleanup()
+
+	existing := `$ORIGIN example.com.
+example.com. 300 IN MX 10 mail.example.com.
+example.com. 300 IN TXT hello
+static.example.com. 600 IN A 10.0.0.9
+`
+	if err := ioutil.WriteFile(f.Path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.EnsureRecord(Record{Name: "wlgore.example.com", Type: RecordA, Targets: []string{"10.0.0.1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFile := `static.example.com. 600 IN A 10.0.0.9
+wlgore.example.com. 300 IN A 10.0.0.1
+`
+	if string(data) != wantFile {
+		t.Errorf("zone file is\n%s\nwant\n%s", data, wantFile)
+	}
+}
+
+func TestFileProviderRejectsInvalidRecords(t *testing.T) {
+	f, cleanup := newTestFileProvider(t)
+	defer cleanup()
+
+	for _, tc := range []struct {
+		record Record
+		err    string
+	}{
+		{Record{Name: "wlgore.example.org", Type: RecordA, Targets: []string{"10.0.0.1"}}, "isn't in zone"},
+		{Record{Name: "wlgore.example.com", Type: "TXT", Targets: []string{"hello"}}, "unsupported record type"},
+		{Record{Name: "wlgore.example.com", Type: RecordA}, "has no targets"},
+		{Record{Name: "wlgore.example.com", Type: RecordCNAME, Targets: []string{"a.example.net", "b.example.net"}}, "exactly one target"},
+	} {
+		err := f.EnsureRecord(tc.record)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("EnsureRecord(%+v) returned %v, want an error containing %q", tc.record, err, tc.err)
+		}
+	}
+	if err := f.DeleteRecord("wlgore.example.org"); err == nil {
+		t.Error("DeleteRecord outside the zone succeeded")
+	}
+	if _, err := os.Stat(f.Path); !os.IsNotExist(err) {
+		t.Errorf("zone file was written for invalid records: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package dns
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordType is the type of the records pointing a domain at the ingress load balancer
+type RecordType string
+
+const (
+	// RecordA points a domain at the IP addresses of a load balancer
+	RecordA RecordType = "A"
+	// RecordCNAME points a domain at the hostname of a load balancer
+	RecordCNAME RecordType = "CNAME"
+)
+
+// DefaultTTL is the TTL of the records, in seconds, when DNS_TTL isn't set
+const DefaultTTL = 300
+
+// Record is the set of A records, or the CNAME record, of a domain
+type Record struct {
+	// Name is the domain, without a trailing dot
+	Name string
+	Type RecordType
+	// Targets are IP addresses for an A record, or a single hostname for a CNAME record
+	Targets []string
+	TTL     uint32
+}
+
+// DNSProvider manages the records of Site domains in a DNS zone. All operations are idempotent, so they can be
+// repeated on every reconcile.
+type DNSProvider interface {
+	// Manages returns true if the domain is in the zone of the provider
+	Manages(domain string) bool
+	// EnsureRecord replaces any A and CNAME records of the record's domain with the record
+	EnsureRecord(r Record) error
+	// DeleteRecord removes the A and CNAME records of a domain, if any. Other types of records are left in place.
+	DeleteRecord(domain string) error
+}
+
+// FromEnvironment returns the DNSProvider configured by the operator's environment, or nil if DNS_PROVIDER isn't set.
+//
+// DNS_PROVIDER is "rfc2136", to send dynamic updates to DNS_SERVER (host:port) for the zone DNS_ZONE, signed with the
+// TSIG key DNS_TSIG_KEY_NAME if it is set. DNS_TSIG_SECRET is the base64-encoded key and DNS_TSIG_ALGORITHM defaults to
+// hmac-sha256. DNS_PROVIDER can also be "file", to write the records to the zone file DNS_FILE instead, for local
+// development. The records of both have a TTL of DNS_TTL seconds.
+func FromEnvironment() (DNSProvider, error) {
+	ttl := uint32(DefaultTTL)
+	if value := os.Getenv("DNS_TTL"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DNS_TTL %q: %v", value, err)
+		}
+		ttl = uint32(parsed)
+	}
+	zone := normalize(os.Getenv("DNS_ZONE"))
+
+	switch provider := os.Getenv("DNS_PROVIDER"); provider {
+	case "":
+		return nil, nil
+
+	case "rfc2136":
+		p := &RFC2136Provider{
+			Server:  os.Getenv("DNS_SERVER"),
+			Zone:    zone,
+			TTL:     ttl,
+			Timeout: 10 * time.Second,
+		}
+		if p.Server == "" || p.Zone == "" {
+			return nil, fmt.Errorf("DNS_SERVER and DNS_ZONE are required by the rfc2136 DNS provider")
+		}
+		if keyName := os.Getenv("DNS_TSIG_KEY_NAME"); keyName != "" {
+			secret, err := base64.StdEncoding.DecodeString(os.Getenv("DNS_TSIG_SECRET"))
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("DNS_TSIG_SECRET must be a base64-encoded key when DNS_TSIG_KEY_NAME is set")
+			}
+			algorithm := TSIGAlgorithm(strings.ToLower(os.Getenv("DNS_TSIG_ALGORITHM")))
+			if algorithm == "" {
+				algorithm = HMACSHA256
+			}
+			if algorithm.hash() == nil {
+				return nil, fmt.Errorf("unsupported DNS_TSIG_ALGORITHM %q", algorithm)
+			}
+			p.TSIG = &TSIGKey{Name: normalize(keyName), Algorithm: algorithm, Secret: secret}
+		}
+		return p, nil
+
+	case "file":
+		path := os.Getenv("DNS_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("DNS_FILE is required by the file DNS provider")
+		}
+		return NewFileProvider(path, zone, ttl), nil
+
+	default:
+		return nil, fmt.Errorf("unknown DNS_PROVIDER %q", provider)
+	}
+}
+
+// normalize returns a domain in lower case, without a trailing dot
+func normalize(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// inZone returns true if domain is zone, or a subdomain of it. Every domain is in the empty zone.
+func inZone(domain, zone string) bool {
+	domain = normalize(domain)
+	return zone == "" || domain == zone || strings.HasSuffix(domain, "."+zone)
+}
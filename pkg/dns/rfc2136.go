@@ -0,0 +1,343 @@
+package dns
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Values from RFC 1035 (messages), RFC 2136 (dynamic updates) and RFC 8945 (TSIG)
+const (
+	opcodeUpdate = 5
+
+	typeA     uint16 = 1
+	typeCNAME uint16 = 5
+	typeSOA   uint16 = 6
+	typeTSIG  uint16 = 250
+
+	classIN  uint16 = 1
+	classANY uint16 = 255
+
+	headerLength = 12
+	// tsigFudge is the number of seconds the clocks of the operator and the DNS server may differ by
+	tsigFudge = 300
+)
+
+var rcodeNames = map[byte]string{
+	1:  "FORMERR",
+	2:  "SERVFAIL",
+	3:  "NXDOMAIN",
+	4:  "NOTIMP",
+	5:  "REFUSED",
+	6:  "YXDOMAIN",
+	7:  "YXRRSET",
+	8:  "NXRRSET",
+	9:  "NOTAUTH",
+	10: "NOTZONE",
+}
+
+// TSIGAlgorithm is the name of the HMAC algorithm of a TSIG key, as in BIND's key statements
+type TSIGAlgorithm string
+
+const (
+	HMACSHA256 TSIGAlgorithm = "hmac-sha256"
+	HMACSHA512 TSIGAlgorithm = "hmac-sha512"
+)
+
+func (a TSIGAlgorithm) hash() func() hash.Hash {
+	switch a {
+	case HMACSHA256:
+		return sha256.New
+	case HMACSHA512:
+		return sha512.New
+	}
+	return nil
+}
+
+// TSIGKey is a shared secret used to sign updates, which the DNS server must allow to update the zone
+type TSIGKey struct {
+	Name      string
+	Algorithm TSIGAlgorithm
+	Secret    []byte
+}
+
+// RFC2136Provider manages records by sending dynamic updates (RFC 2136) to the primary server of a zone, such as BIND
+// with an update-policy or allow-update for the TSIG key. Updates are sent over TCP.
+type RFC2136Provider struct {
+	// Server is the host:port of the primary server of the zone
+	Server string
+	// Zone is the zone that records are updated in
+	Zone string
+	// TTL is the TTL of records that don't set one
+	TTL uint32
+	// TSIG signs the updates when set
+	TSIG *TSIGKey
+	// Timeout bounds each update, from connecting to reading the response
+	Timeout time.Duration
+}
+
+var _ DNSProvider = &RFC2136Provider{}
+
+func (p *RFC2136Provider) Manages(domain string) bool {
+	return inZone(domain, p.Zone)
+}
+
+func (p *RFC2136Provider) EnsureRecord(r Record) error {
+	if !p.Manages(r.Name) {
+		return fmt.Errorf("%s isn't in zone %s", r.Name, p.Zone)
+	}
+
+	var rrtype uint16
+	var rdata [][]byte
+	switch r.Type {
+	case RecordA:
+		rrtype = typeA
+		for _, target := range r.Targets {
+			ip := net.ParseIP(target).To4()
+			if ip == nil {
+				return fmt.Errorf("%q isn't an IPv4 address", target)
+			}
+			rdata = append(rdata, ip)
+		}
+	case RecordCNAME:
+		rrtype = typeCNAME
+		if len(r.Targets) != 1 {
+			return fmt.Errorf("a CNAME record needs exactly one target, found %d", len(r.Targets))
+		}
+		target, err := encodeName(r.Targets[0])
+		if err != nil {
+			return err
+		}
+		rdata = append(rdata, target)
+	default:
+		return fmt.Errorf("unsupported record type %q", r.Type)
+	}
+	if len(rdata) == 0 {
+		return fmt.Errorf("the %s record of %s has no targets", r.Type, r.Name)
+	}
+
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = p.TTL
+	}
+
+	// The server applies the updates in order, so a CNAME can replace A records and vice versa
+	u := &update{zone: p.Zone}
+	u.deleteRRset(r.Name, typeCNAME)
+	u.deleteRRset(r.Name, typeA)
+	for _, data := range rdata {
+		u.add(r.Name, rrtype, ttl, data)
+	}
+	return p.send(u)
+}
+
+func (p *RFC2136Provider) DeleteRecord(domain string) error {
+	if !p.Manages(domain) {
+		return fmt.Errorf("%s isn't in zone %s", domain, p.Zone)
+	}
+
+	u := &update{zone: p.Zone}
+	u.deleteRRset(domain, typeCNAME)
+	u.deleteRRset(domain, typeA)
+	return p.send(u)
+}
+
+// send sends an update and checks the response code. The TSIG signature of the response isn't verified.
+func (p *RFC2136Provider) send(u *update) error {
+	msg, err := u.pack()
+	if err != nil {
+		return err
+	}
+	if p.TSIG != nil {
+		if msg, err = p.TSIG.sign(msg, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	conn, err := net.DialTimeout("tcp", p.Server, p.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(p.Timeout)); err != nil {
+		return err
+	}
+
+	// Over TCP, messages are preceded by their length
+	if _, err := conn.Write(append(put16(nil, uint16(len(msg))), msg...)); err != nil {
+		return err
+	}
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(conn, length); err != nil {
+		return fmt.Errorf("failed to read the response of %s: %v", p.Server, err)
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("failed to read the response of %s: %v", p.Server, err)
+	}
+
+	if len(resp) < headerLength || resp[0] != msg[0] || resp[1] != msg[1] {
+		return fmt.Errorf("invalid response from %s", p.Server)
+	}
+	if rcode := resp[3] & 0x0f; rcode != 0 {
+		name, ok := rcodeNames[rcode]
+		if !ok {
+			name = fmt.Sprintf("RCODE%d", rcode)
+		}
+		return fmt.Errorf("update of zone %s refused by %s: %s", p.Zone, p.Server, name)
+	}
+	return nil
+}
+
+// update is a dynamic update message for one zone, without prerequisites
+type update struct {
+	zone    string
+	records []byte
+	count   uint16
+	err     error
+}
+
+// add adds a record to an RRset
+func (u *update) add(name string, rrtype uint16, ttl uint32, rdata []byte) {
+	u.record(name, rrtype, classIN, ttl, rdata)
+}
+
+// deleteRRset deletes all the records of a type for a name, if there are any
+func (u *update) deleteRRset(name string, rrtype uint16) {
+	u.record(name, rrtype, classANY, 0, nil)
+}
+
+func (u *update) record(name string, rrtype, class uint16, ttl uint32, rdata []byte) {
+	if u.err != nil {
+		return
+	}
+	encoded, err := encodeName(name)
+	if err != nil {
+		u.err = err
+		return
+	}
+	u.records = append(u.records, encoded...)
+	u.records = put16(u.records, rrtype)
+	u.records = put16(u.records, class)
+	u.records = put32(u.records, ttl)
+	u.records = put16(u.records, uint16(len(rdata)))
+	u.records = append(u.records, rdata...)
+	u.count++
+}
+
+// pack returns the message in wire format
+func (u *update) pack() ([]byte, error) {
+	if u.err != nil {
+		return nil, u.err
+	}
+	zone, err := encodeName(u.zone)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 2)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	msg := append(make([]byte, 0, headerLength+len(zone)+4+len(u.records)), id...)
+	msg = put16(msg, opcodeUpdate<<11)
+	msg = put16(msg, 1)       // ZOCOUNT
+	msg = put16(msg, 0)       // PRCOUNT
+	msg = put16(msg, u.count) // UPCOUNT
+	msg = put16(msg, 0)       // ADCOUNT
+	msg = append(msg, zone...)
+	msg = put16(msg, typeSOA)
+	msg = put16(msg, classIN)
+	return append(msg, u.records...), nil
+}
+
+// sign returns the message with a TSIG record appended to its additional section (RFC 8945 section 4)
+func (k *TSIGKey) sign(msg []byte, now time.Time) ([]byte, error) {
+	keyName, err := encodeName(k.Name)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := encodeName(string(k.Algorithm))
+	if err != nil {
+		return nil, err
+	}
+	newHash := k.Algorithm.hash()
+	if newHash == nil {
+		return nil, fmt.Errorf("unsupported TSIG algorithm %q", k.Algorithm)
+	}
+	timeSigned := uint64(now.Unix())
+
+	// The MAC covers the message and the TSIG variables
+	variables := append([]byte{}, keyName...)
+	variables = put16(variables, classANY)
+	variables = put32(variables, 0) // TTL
+	variables = append(variables, algorithm...)
+	variables = put48(variables, timeSigned)
+	variables = put16(variables, tsigFudge)
+	variables = put16(variables, 0) // Error
+	variables = put16(variables, 0) // Other Len
+	mac := hmac.New(newHash, k.Secret)
+	mac.Write(msg)
+	mac.Write(variables)
+	sum := mac.Sum(nil)
+
+	rdata := append([]byte{}, algorithm...)
+	rdata = put48(rdata, timeSigned)
+	rdata = put16(rdata, tsigFudge)
+	rdata = put16(rdata, uint16(len(sum)))
+	rdata = append(rdata, sum...)
+	rdata = append(rdata, msg[0], msg[1]) // Original ID
+	rdata = put16(rdata, 0)               // Error
+	rdata = put16(rdata, 0)               // Other Len
+
+	signed := append([]byte{}, msg...)
+	signed = append(signed, keyName...)
+	signed = put16(signed, typeTSIG)
+	signed = put16(signed, classANY)
+	signed = put32(signed, 0)
+	signed = put16(signed, uint16(len(rdata)))
+	signed = append(signed, rdata...)
+	binary.BigEndian.PutUint16(signed[10:], binary.BigEndian.Uint16(signed[10:])+1) // ADCOUNT
+	return signed, nil
+}
+
+// encodeName returns a domain name in uncompressed wire format, in lower case as TSIG requires
+func encodeName(name string) ([]byte, error) {
+	name = normalize(name)
+	var encoded []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid domain name %q", name)
+			}
+			encoded = append(encoded, byte(len(label)))
+			encoded = append(encoded, label...)
+		}
+	}
+	encoded = append(encoded, 0)
+	if len(encoded) > 255 {
+		return nil, fmt.Errorf("domain name %q is too long", name)
+	}
+	return encoded, nil
+}
+
+func put16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func put32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func put48(b []byte, v uint64) []byte {
+	return append(b, byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,201 @@
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/registry"
+)
+
+// Defaults used when the DrupalOperatorConfig doesn't exist or doesn't set a field
+const (
+	DefaultIngressClass      = "nginx"
+	DefaultCertIssuer        = "letsencrypt-staging"
+	DefaultFilesStorageClass = "efs"
+	DefaultAdminSecretName   = "default-cluster-creds"
+	DefaultAdminSecretNs     = "default"
+)
+
+// DefaultNodeSelector returns the node selector of the pods created by the operator, unless it is overridden
+func DefaultNodeSelector() map[string]string {
+	return map[string]string{"function": "workers"}
+}
+
+// Config holds the operator-wide settings read from the DrupalOperatorConfig named "default"
+type Config struct {
+	IngressClass string
+	CertIssuer   string
+	// RoutingAPI is empty when it should be discovered from the API server
+	RoutingAPI fnv1alpha1.RoutingAPI
+	// Gateway is the parent of HTTPRoutes, when RoutingAPI is HTTPRoute
+	Gateway           fnv1alpha1.GatewayReference
+	FilesStorageClass string
+	NodeSelector      map[string]string
+	// AdminSecret holds the credentials of the cluster DB
+	AdminSecret types.NamespacedName
+	// DatabaseHost and DatabasePort override the host and port in AdminSecret when they aren't empty
+	DatabaseHost string
+	DatabasePort string
+	Registry     registry.Config
+}
+
+var (
+	mu      sync.RWMutex
+	current = Default()
+	// resourceVersion of the DrupalOperatorConfig current was resolved from, or "" if it is the default
+	loadedVersion string
+)
+
+// Current returns the configuration in effect. Its NodeSelector is a copy, so it can be set on objects directly.
+func Current() Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	c := current
+	if c.NodeSelector != nil {
+		c.NodeSelector = make(map[string]string, len(current.NodeSelector))
+		for k, v := range current.NodeSelector {
+			c.NodeSelector[k] = v
+		}
+	}
+	return c
+}
+
+// Default returns the configuration used when there is no DrupalOperatorConfig. The DB_HOST_OVERRIDE and
+// DB_PORT_OVERRIDE environment variables, and those read by registry.FromEnvironment, take effect here.
+func Default() Config {
+	return Config{
+		IngressClass:      DefaultIngressClass,
+		CertIssuer:        DefaultCertIssuer,
+		FilesStorageClass: DefaultFilesStorageClass,
+		NodeSelector:      DefaultNodeSelector(),
+		AdminSecret:       types.NamespacedName{Namespace: DefaultAdminSecretNs, Name: DefaultAdminSecretName},
+		DatabaseHost:      os.Getenv("DB_HOST_OVERRIDE"),
+		DatabasePort:      os.Getenv("DB_PORT_OVERRIDE"),
+		Registry:          registry.FromEnvironment(),
+	}
+}
+
+// Resolve applies a DrupalOperatorConfig spec over the defaults. The returned errors describe the fields that are
+// invalid; the Config must not be used if there are any.
+func Resolve(spec fnv1alpha1.DrupalOperatorConfigSpec) (Config, []string) {
+	c := Default()
+	var errs []string
+	invalid := func(field string, value string, problems []string) {
+		for _, p := range problems {
+			errs = append(errs, fmt.Sprintf("spec.%s: invalid value %q: %s", field, value, p))
+		}
+	}
+	override := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+
+	override(&c.IngressClass, spec.Ingress.Class)
+	override(&c.CertIssuer, spec.Ingress.CertIssuer)
+	if spec.Ingress.CertIssuer != "" {
+		invalid("ingress.certIssuer", spec.Ingress.CertIssuer, validation.IsDNS1123Subdomain(spec.Ingress.CertIssuer))
+	}
+	c.RoutingAPI = spec.Ingress.RoutingAPI
+	switch c.RoutingAPI {
+	case "", fnv1alpha1.RoutingAPIIngress, fnv1alpha1.RoutingAPILegacyIngress, fnv1alpha1.RoutingAPIHTTPRoute:
+	default:
+		invalid("ingress.routingAPI", string(c.RoutingAPI), []string{fmt.Sprintf("must be %s, %s or %s",
+			fnv1alpha1.RoutingAPIIngress, fnv1alpha1.RoutingAPILegacyIngress, fnv1alpha1.RoutingAPIHTTPRoute)})
+	}
+	if gw := spec.Ingress.Gateway; gw != nil {
+		c.Gateway = *gw
+		invalid("ingress.gateway.namespace", gw.Namespace, validation.IsDNS1123Label(gw.Namespace))
+		invalid("ingress.gateway.name", gw.Name, validation.IsDNS1123Subdomain(gw.Name))
+		if gw.SectionName != "" {
+			invalid("ingress.gateway.sectionName", gw.SectionName, validation.IsDNS1123Subdomain(gw.SectionName))
+		}
+	} else if c.RoutingAPI == fnv1alpha1.RoutingAPIHTTPRoute {
+		errs = append(errs, "spec.ingress.gateway: required with the HTTPRoute routing API")
+	}
+
+	override(&c.FilesStorageClass, spec.Storage.FilesStorageClass)
+	if spec.Storage.FilesStorageClass != "" {
+		invalid("storage.filesStorageClass", spec.Storage.FilesStorageClass, validation.IsDNS1123Subdomain(spec.Storage.FilesStorageClass))
+	}
+
+	if spec.Scheduling.NodeSelector != nil {
+		// An empty selector is nil, as it is in the objects read back from the API server
+		c.NodeSelector = nil
+		for k, v := range spec.Scheduling.NodeSelector {
+			invalid("scheduling.nodeSelector", k, validation.IsQualifiedName(k))
+			invalid("scheduling.nodeSelector["+k+"]", v, validation.IsValidLabelValue(v))
+			if c.NodeSelector == nil {
+				c.NodeSelector = map[string]string{}
+			}
+			c.NodeSelector[k] = v
+		}
+	}
+
+	db := spec.Database
+	if db.AdminSecret != nil {
+		c.AdminSecret = types.NamespacedName{Namespace: db.AdminSecret.Namespace, Name: db.AdminSecret.Name}
+		invalid("database.adminSecret.namespace", db.AdminSecret.Namespace, validation.IsDNS1123Label(db.AdminSecret.Namespace))
+		invalid("database.adminSecret.name", db.AdminSecret.Name, validation.IsDNS1123Subdomain(db.AdminSecret.Name))
+	}
+	override(&c.DatabaseHost, db.Host)
+	override(&c.DatabasePort, db.Port)
+	if db.Port != "" {
+		if port, err := strconv.Atoi(db.Port); err != nil {
+			invalid("database.port", db.Port, []string{"must be a number"})
+		} else {
+			invalid("database.port", db.Port, validation.IsValidPortNum(port))
+		}
+	}
+
+	reg := spec.Registry
+	override(&c.Registry.CustomerImageRoot, reg.CustomerImageRoot)
+	override(&c.Registry.ApacheImage, reg.ApacheImage)
+	override(&c.Registry.PhpFpmImage, reg.PhpFpmImage)
+	override(&c.Registry.ProxySQLImage, reg.ProxySQLImage)
+	override(&c.Registry.S3Image, reg.S3Image)
+	override(&c.Registry.MaintenanceImage, reg.MaintenanceImage)
+	if reg.ImagePullSecrets != nil {
+		c.Registry.ImagePullSecrets = reg.ImagePullSecrets
+		for i, name := range reg.ImagePullSecrets {
+			invalid(fmt.Sprintf("registry.imagePullSecrets[%d]", i), name, validation.IsDNS1123Subdomain(name))
+		}
+	}
+
+	return c, errs
+}
+
+// Load makes the DrupalOperatorConfig named "default" the configuration in effect, reading it through the given client.
+// If it doesn't exist, the defaults are used. If it is invalid, the configuration in effect is kept; the
+// DrupalOperatorConfig controller reports the problems in its status.
+func Load(c client.Client) error {
+	config := &fnv1alpha1.DrupalOperatorConfig{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: fnv1alpha1.OperatorConfigName}, config)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if config.ResourceVersion == loadedVersion {
+		return nil
+	}
+	loadedVersion = config.ResourceVersion
+
+	resolved, errs := Resolve(config.Spec)
+	if len(errs) > 0 {
+		return nil
+	}
+	current = resolved
+	registry.Set(resolved.Registry)
+	return nil
+}
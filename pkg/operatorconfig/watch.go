@@ -0,0 +1,68 @@
+package operatorconfig
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+var log = logf.Log.WithName("operatorconfig")
+
+// Watch makes a controller reconcile all of its objects when the spec of the DrupalOperatorConfig named "default"
+// changes, or it is created or deleted. list is an empty list of the controller's kind, for example
+// &fnv1alpha1.SiteList{}.
+func Watch(c controller.Controller, reader client.Client, list runtime.Object) error {
+	return c.Watch(&source.Kind{Type: &fnv1alpha1.DrupalOperatorConfig{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			if o.Meta.GetName() != fnv1alpha1.OperatorConfigName {
+				return nil
+			}
+			return allObjects(reader, list.DeepCopyObject())
+		}),
+	}, specChanged)
+}
+
+// specChanged filters out updates of the DrupalOperatorConfig that leave its spec, and so its generation, unchanged.
+// controller-runtime v0.1 has no GenerationChangedPredicate.
+var specChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration()
+	},
+}
+
+// allObjects returns a reconcile request for each object of the given list's kind
+func allObjects(reader client.Client, list runtime.Object) []reconcile.Request {
+	if err := reader.List(context.TODO(), &client.ListOptions{}, list); err != nil {
+		log.Error(err, "Failed to list objects to reconcile after a DrupalOperatorConfig change")
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		log.Error(err, "Failed to list objects to reconcile after a DrupalOperatorConfig change")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Defaults used when the operator's environment doesn't configure a registry
+const (
+	DefaultRegistry          = "881217801864.dkr.ecr.us-east-1.amazonaws.com/"
+	DefaultCustomerImageRoot = DefaultRegistry + "customer/"
+	DefaultApacheImage       = DefaultRegistry + "apache/default"
+	DefaultPhpFpmImage       = DefaultRegistry + "php-fpm/default"
+	DefaultProxySQLImage     = "severalnines/proxysql"
+	DefaultS3Image           = "amazon/aws-cli:2.0.6"
+	DefaultMaintenanceImage  = "nginx:1.17-alpine"
+)
+
+// Config holds the images of the containers the operator runs, and the pull secrets given to every pod it creates.
+// Images other than S3Image and MaintenanceImage are repositories, without a tag.
+type Config struct {
+	// CustomerImageRoot is prefixed to the git repo of a DrupalApplication that doesn't set imageRepo
+	CustomerImageRoot string
+	ApacheImage       string
+	PhpFpmImage       string
+	ProxySQLImage     string
+	// S3Image transfers backup archives to and from S3-compatible targets
+	S3Image string
+	// MaintenanceImage is an NGINX image serving the maintenance page of Sites in maintenance mode
+	MaintenanceImage string
+	// ImagePullSecrets are the names of Secrets in each namespace the operator creates pods in
+	ImagePullSecrets []string
+	// ResolveDigests pins the Drupal, Apache and PHP-FPM images of each release to the digests of their tags. It is off
+	// by default, since the operator needs credentials for the registry that the nodes may not.
+	ResolveDigests bool
+	// InsecureRegistries are the registry hosts whose v2 API is queried over plain HTTP when resolving digests
+	InsecureRegistries []string
+}
+
+var (
+	mu      sync.RWMutex
+	current = FromEnvironment()
+)
+
+// Current returns the registry configuration in effect
+func Current() Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Set replaces the registry configuration in effect
+func Set(c Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = c
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		CustomerImageRoot: DefaultCustomerImageRoot,
+		ApacheImage:       DefaultApacheImage,
+		PhpFpmImage:       DefaultPhpFpmImage,
+		ProxySQLImage:     DefaultProxySQLImage,
+		S3Image:           DefaultS3Image,
+		MaintenanceImage:  DefaultMaintenanceImage,
+	}
+}
+
+// FromEnvironment returns the default configuration, overridden by the CUSTOMER_IMAGE_ROOT, APACHE_IMAGE,
+// PHP_FPM_IMAGE, PROXYSQL_IMAGE, S3_IMAGE and MAINTENANCE_IMAGE environment variables. IMAGE_PULL_SECRETS is a comma-separated list of
+// Secret names, and INSECURE_REGISTRIES one of registry hosts. RESOLVE_IMAGE_DIGESTS=true pins images to digests.
+func FromEnvironment() Config {
+	c := Default()
+	override := func(field *string, name string) {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+	override(&c.CustomerImageRoot, "CUSTOMER_IMAGE_ROOT")
+	override(&c.ApacheImage, "APACHE_IMAGE")
+	override(&c.PhpFpmImage, "PHP_FPM_IMAGE")
+	override(&c.ProxySQLImage, "PROXYSQL_IMAGE")
+	override(&c.S3Image, "S3_IMAGE")
+	override(&c.MaintenanceImage, "MAINTENANCE_IMAGE")
+
+	for _, name := range strings.Split(os.Getenv("IMAGE_PULL_SECRETS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			c.ImagePullSecrets = append(c.ImagePullSecrets, name)
+		}
+	}
+	for _, host := range strings.Split(os.Getenv("INSECURE_REGISTRIES"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			c.InsecureRegistries = append(c.InsecureRegistries, host)
+		}
+	}
+	if resolve, err := strconv.ParseBool(os.Getenv("RESOLVE_IMAGE_DIGESTS")); err == nil {
+		c.ResolveDigests = resolve
+	}
+	return c
+}
+
+// CustomerImage returns the image of a customer git repo at the given tag
+func (c Config) CustomerImage(gitRepo, tag string) string {
+	root := c.CustomerImageRoot
+	if root != "" && !strings.HasSuffix(root, "/") {
+		root += "/"
+	}
+	return root + gitRepo + ":" + tag
+}
+
+// PullSecrets returns the ImagePullSecrets of a pod spec
+func (c Config) PullSecrets() []corev1.LocalObjectReference {
+	if len(c.ImagePullSecrets) == 0 {
+		return nil
+	}
+	refs := make([]corev1.LocalObjectReference, len(c.ImagePullSecrets))
+	for i, name := range c.ImagePullSecrets {
+		refs[i] = corev1.LocalObjectReference{Name: name}
+	}
+	return refs
+}
@@ -0,0 +1,25 @@
+package registry
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFromEnvironmentResolveDigests(t *testing.T) {
+	cases := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"true", true},
+		{"false", false},
+		{"yes", false},
+	}
+	defer os.Unsetenv("RESOLVE_IMAGE_DIGESTS")
+	for _, c := range cases {
+		os.Setenv("RESOLVE_IMAGE_DIGESTS", c.value)
+		if got := FromEnvironment().ResolveDigests; got != c.want {
+			t.Errorf("RESOLVE_IMAGE_DIGESTS=%q resolves digests: %t, want %t", c.value, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DockerHubHost is the registry of image references without a registry host
+const DockerHubHost = "registry-1.docker.io"
+
+// manifestMediaTypes are accepted when resolving a tag, so that the digest of a multi-arch image is that of its index,
+// which is what the kubelet pulls by
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// Credentials returns the username and password for a registry host, or "" if there are none
+type Credentials func(host string) (username, password string)
+
+// Resolver resolves image tags to the digests of their manifests, with the v2 API of the registry serving them
+type Resolver struct {
+	Client *http.Client
+	// Insecure are the registry hosts queried over plain HTTP, in addition to localhost
+	Insecure    []string
+	Credentials Credentials
+}
+
+// Resolver returns a Resolver of the configured registries, authenticating with the given credentials
+func (c Config) Resolver(credentials Credentials) *Resolver {
+	return &Resolver{
+		Client:      &http.Client{Timeout: 30 * time.Second},
+		Insecure:    c.InsecureRegistries,
+		Credentials: credentials,
+	}
+}
+
+// ParseReference splits an image reference into the host of its registry, its repository and its tag, which defaults
+// to "latest". References without a registry host are Docker Hub images.
+func ParseReference(image string) (host, repository, tag string, err error) {
+	ref := image
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	tag = "latest"
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, tag = ref[:i], ref[i+1:]
+	}
+
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		host, repository = parts[0], parts[1]
+	} else {
+		host, repository = DockerHubHost, ref
+		if len(parts) == 1 {
+			repository = "library/" + ref
+		}
+	}
+	if repository == "" || tag == "" {
+		return "", "", "", fmt.Errorf("invalid image reference %q", image)
+	}
+	return host, repository, tag, nil
+}
+
+// Digest returns the digest of the manifest an image reference points to. A reference already pinned to a digest
+// returns it without querying the registry.
+func (r *Resolver) Digest(ctx context.Context, image string) (string, error) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:], nil
+	}
+	host, repository, tag, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	manifest := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", r.scheme(host), host, repository, tag)
+
+	// Some registries only return Docker-Content-Digest to GET, in which case the digest is that of the manifest body
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		resp, err := r.request(ctx, method, manifest, host, repository)
+		if err != nil {
+			return "", fmt.Errorf("resolving %s: %v", image, err)
+		}
+		digest := resp.Header.Get("Docker-Content-Digest")
+		if digest == "" && method == http.MethodGet {
+			h := sha256.New()
+			if _, err := io.Copy(h, resp.Body); err != nil {
+				resp.Body.Close()
+				return "", fmt.Errorf("resolving %s: %v", image, err)
+			}
+			digest = fmt.Sprintf("sha256:%x", h.Sum(nil))
+		}
+		resp.Body.Close()
+		if digest != "" {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("resolving %s: the registry returned no digest", image)
+}
+
+func (r *Resolver) scheme(host string) string {
+	name := host
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "localhost" || name == "127.0.0.1" {
+		return "http"
+	}
+	for _, insecure := range r.Insecure {
+		if host == insecure {
+			return "http"
+		}
+	}
+	return "https"
+}
+
+// request sends a request to the registry, answering its authentication challenge if it has one. The response
+// has a 200 status.
+func (r *Resolver) request(ctx context.Context, method, target, host, repository string) (*http.Response, error) {
+	var username, password string
+	if r.Credentials != nil {
+		username, password = r.Credentials(host)
+	}
+
+	authorization := ""
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := r.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, fmt.Errorf("%s %s: %s", method, target, resp.Status)
+		}
+
+		scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+		switch scheme {
+		case "basic":
+			if username == "" {
+				return nil, fmt.Errorf("%s %s: %s, and there are no credentials for %s", method, target, resp.Status, host)
+			}
+			authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		case "bearer":
+			token, err := r.token(ctx, params, repository, username, password)
+			if err != nil {
+				return nil, err
+			}
+			authorization = "Bearer " + token
+		default:
+			return nil, fmt.Errorf("%s %s: %s", method, target, resp.Status)
+		}
+	}
+}
+
+// token gets a pull token for a repository from the token server of a bearer challenge
+func (r *Resolver) token(ctx context.Context, challenge map[string]string, repository, username, password string) (string, error) {
+	realm, err := url.Parse(challenge["realm"])
+	if err != nil || challenge["realm"] == "" {
+		return "", fmt.Errorf("invalid token realm %q", challenge["realm"])
+	}
+	query := realm.Query()
+	if service := challenge["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := challenge["scope"]
+	if scope == "" {
+		scope = "repository:" + repository + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", realm.String(), resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("GET %s: no token returned", realm.String())
+}
+
+// parseChallenge returns the lowercased scheme and the parameters of a WWW-Authenticate header, such as
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`
+func parseChallenge(header string) (scheme string, params map[string]string) {
+	params = map[string]string{}
+	header = strings.TrimSpace(header)
+	i := strings.IndexByte(header, ' ')
+	if i < 0 {
+		return strings.ToLower(header), params
+	}
+	scheme, rest := strings.ToLower(header[:i]), header[i+1:]
+
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.IndexByte(rest, ','); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return scheme, params
+}
+
+// SecretCredentials returns the credentials of the .dockerconfigjson of image pull Secrets. Secrets of other types
+// are ignored.
+func SecretCredentials(secrets []corev1.Secret) (Credentials, error) {
+	auths := map[string][2]string{}
+	for _, secret := range secrets {
+		if secret.Type != corev1.SecretTypeDockerConfigJson {
+			continue
+		}
+		var config struct {
+			Auths map[string]struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+				Auth     string `json:"auth"`
+			} `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("Secret %s: %v", secret.Name, err)
+		}
+		for key, auth := range config.Auths {
+			username, password := auth.Username, auth.Password
+			if auth.Auth != "" {
+				decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+				if err != nil {
+					return nil, fmt.Errorf("Secret %s: auth of %s: %v", secret.Name, key, err)
+				}
+				parts := strings.SplitN(string(decoded), ":", 2)
+				if len(parts) == 2 {
+					username, password = parts[0], parts[1]
+				}
+			}
+			auths[credentialsHost(key)] = [2]string{username, password}
+		}
+	}
+	return func(host string) (string, string) {
+		auth := auths[host]
+		return auth[0], auth[1]
+	}, nil
+}
+
+// credentialsHost returns the registry host of a key of a Docker config, which may be a URL
+func credentialsHost(key string) string {
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	key = strings.SplitN(key, "/", 2)[0]
+	if key == "index.docker.io" || key == "docker.io" {
+		return DockerHubHost
+	}
+	return key
+}
@@ -0,0 +1,209 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testManifest = `{"schemaVersion":2}`
+
+func TestParseReference(t *testing.T) {
+	for _, tc := range []struct {
+		image                 string
+		host, repository, tag string
+	}{
+		{"drupal", DockerHubHost, "library/drupal", "latest"},
+		{"drupal:8.7", DockerHubHost, "library/drupal", "8.7"},
+		{"acquia/drupal:8.7", DockerHubHost, "acquia/drupal", "8.7"},
+		{"registry.example.com/wlgore/drupal", "registry.example.com", "wlgore/drupal", "latest"},
+		{"localhost:5000/wlgore/drupal:v1", "localhost:5000", "wlgore/drupal", "v1"},
+		{"localhost/drupal@sha256:abc", "localhost", "drupal", "latest"},
+	} {
+		host, repository, tag, err := ParseReference(tc.image)
+		if err != nil {
+			t.Errorf("ParseReference(%q) failed: %v", tc.image, err)
+			continue
+		}
+		if host != tc.host || repository != tc.repository || tag != tc.tag {
+			t.Errorf("ParseReference(%q) = %s, %s, %s, want %s, %s, %s", tc.image, host, repository, tag, tc.host, tc.repository, tc.tag)
+		}
+	}
+
+	for _, image := range []string{"drupal:", "registry.example.com/"} {
+		if _, _, _, err := ParseReference(image); err == nil {
+			t.Errorf("ParseReference(%q) succeeded", image)
+		}
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	for _, tc := range []struct {
+		header string
+		scheme string
+		params map[string]string
+	}{
+		{`Basic`, "basic", map[string]string{}},
+		{`Basic realm="Registry Realm"`, "basic", map[string]string{"realm": "Registry Realm"}},
+		{
+			`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/drupal:pull"`,
+			"bearer",
+			map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:library/drupal:pull"},
+		},
+		{`bearer Realm=https://auth.example.com/token, service=registry`, "bearer", map[string]string{"realm": "https://auth.example.com/token", "service": "registry"}},
+	} {
+		scheme, params := parseChallenge(tc.header)
+		if scheme != tc.scheme || !reflect.DeepEqual(params, tc.params) {
+			t.Errorf("parseChallenge(%q) = %s, %v, want %s, %v", tc.header, scheme, params, tc.scheme, tc.params)
+		}
+	}
+}
+
+func TestSecretCredentials(t *testing.T) {
+	dockerConfig := func(name, json string) corev1.Secret {
+		return corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(json)},
+		}
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("hub-user:hub:pass"))
+	secrets := []corev1.Secret{
+		dockerConfig("hub", `{"auths":{"https://index.docker.io/v1/":{"auth":"`+auth+`"}}}`),
+		dockerConfig("private", `{"auths":{"registry.example.com":{"username":"wlgore","password":"secret"}}}`),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "opaque"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte("not json")},
+		},
+	}
+
+	credentials, err := SecretCredentials(secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, want := range map[string][2]string{
+		DockerHubHost:          {"hub-user", "hub:pass"},
+		"registry.example.com": {"wlgore", "secret"},
+		"other.example.com":    {"", ""},
+	} {
+		if username, password := credentials(host); username != want[0] || password != want[1] {
+			t.Errorf("credentials(%q) = %q, %q, want %q, %q", host, username, password, want[0], want[1])
+		}
+	}
+
+	if _, err := SecretCredentials([]corev1.Secret{dockerConfig("broken", "{")}); err == nil {
+		t.Error("SecretCredentials of an invalid .dockerconfigjson succeeded")
+	}
+}
+
+// newTestResolver returns a Resolver of the registry served by the handler, and the reference of an image on it
+func newTestResolver(t *testing.T, handler http.Handler, credentials Credentials) (*Resolver, string, func()) {
+	server := httptest.NewServer(handler)
+	host := strings.TrimPrefix(server.URL, "http://")
+	return &Resolver{Client: server.Client(), Credentials: credentials}, host + "/wlgore/drupal:v1", server.Close
+}
+
+func TestDigestPinnedReference(t *testing.T) {
+	r := &Resolver{Client: http.DefaultClient}
+	digest, err := r.Digest(context.TODO(), "registry.invalid/wlgore/drupal:v1@sha256:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != "sha256:abc" {
+		t.Errorf("digest is %s, want sha256:abc", digest)
+	}
+}
+
+func TestDigestFallsBackToGet(t *testing.T) {
+	var methods []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		methods = append(methods, req.Method)
+		if req.URL.Path != "/v2/wlgore/drupal/manifests/v1" {
+			http.NotFound(w, req)
+			return
+		}
+		if !strings.Contains(req.Header.Get("Accept"), "application/vnd.docker.distribution.manifest.list.v2+json") {
+			t.Errorf("Accept header %q doesn't accept manifest lists", req.Header.Get("Accept"))
+		}
+		// No Docker-Content-Digest, to either method
+		fmt.Fprint(w, testManifest)
+	})
+	r, image, cleanup := newTestResolver(t, handler, nil)
+	defer cleanup()
+
+	digest, err := r.Digest(context.TODO(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(testManifest))); digest != want {
+		t.Errorf("digest is %s, want %s", digest, want)
+	}
+	if want := []string{http.MethodHead, http.MethodGet}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("requests were %v, want %v", methods, want)
+	}
+}
+
+func TestDigestBearerToken(t *testing.T) {
+	const token = "pull-token"
+	const digest = "sha256:0123456789abcdef"
+
+	mux := http.NewServeMux()
+	var realm string
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		if username, password, ok := req.BasicAuth(); !ok || username != "wlgore" || password != "secret" {
+			t.Errorf("token request authenticated as %q, %q", username, password)
+		}
+		query := req.URL.Query()
+		if query.Get("service") != "registry.test" || query.Get("scope") != "repository:wlgore/drupal:pull" {
+			t.Errorf("token request for service %q and scope %q", query.Get("service"), query.Get("scope"))
+		}
+		fmt.Fprintf(w, `{"access_token":%q}`, token)
+	})
+	mux.HandleFunc("/v2/wlgore/drupal/manifests/v1", func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q,service="registry.test"`, realm))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.Method != http.MethodHead {
+			t.Errorf("manifest requested with %s although HEAD returned its digest", req.Method)
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	})
+
+	credentials := func(host string) (string, string) { return "wlgore", "secret" }
+	r, image, cleanup := newTestResolver(t, mux, credentials)
+	defer cleanup()
+	realm = "http://" + strings.SplitN(image, "/", 2)[0] + "/token"
+
+	got, err := r.Digest(context.TODO(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != digest {
+		t.Errorf("digest is %s, want %s", got, digest)
+	}
+}
+
+func TestDigestUnauthorized(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	r, image, cleanup := newTestResolver(t, handler, nil)
+	defer cleanup()
+
+	if _, err := r.Digest(context.TODO(), image); err == nil || !strings.Contains(err.Error(), "no credentials") {
+		t.Errorf("Digest returned %v, want an error about missing credentials", err)
+	}
+}
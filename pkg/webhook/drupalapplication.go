@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+func validateApplication(ctx context.Context, c client.Client, obj, old runtime.Object) field.ErrorList {
+	app := obj.(*fnv1alpha1.DrupalApplication)
+	if old != nil && old.(*fnv1alpha1.DrupalApplication).Spec == app.Spec {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if app.Spec.GitRepo == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "gitRepo"), ""))
+	}
+	return errs
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+func TestValidateApplication(t *testing.T) {
+	newApplication := func(gitRepo string) *fnv1alpha1.DrupalApplication {
+		return &fnv1alpha1.DrupalApplication{
+			ObjectMeta: metav1.ObjectMeta{Name: "wlgore-app"},
+			Spec:       fnv1alpha1.DrupalApplicationSpec{GitRepo: gitRepo, ImageRepo: "registry.example.com/wlgore"},
+		}
+	}
+
+	cases := []struct {
+		name string
+		app  *fnv1alpha1.DrupalApplication
+		old  *fnv1alpha1.DrupalApplication
+		want []string
+	}{
+		{
+			name: "valid",
+			app:  newApplication("git@example.com:wlgore/drupal.git"),
+		},
+		{
+			name: "no git repository",
+			app:  newApplication(""),
+			want: []string{"spec.gitRepo"},
+		},
+		{
+			name: "git repository removed",
+			app:  newApplication(""),
+			old:  newApplication("git@example.com:wlgore/drupal.git"),
+			want: []string{"spec.gitRepo"},
+		},
+		{
+			// Applications created before the webhook can still be managed
+			name: "unchanged spec",
+			app:  newApplication(""),
+			old:  newApplication(""),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var old runtime.Object
+			if c.old != nil {
+				old = c.old
+			}
+			errs := validateApplication(context.TODO(), nil, c.app, old)
+			if got := errorFields(errs); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("validateApplication returned errors for %v, want %v: %v", got, c.want, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// validateEnvironment checks a DrupalEnvironment's spec. An update that doesn't change the spec is always allowed, so
+// that the operator can manage DrupalEnvironments created before the webhook was installed.
+func validateEnvironment(ctx context.Context, c client.Client, obj, old runtime.Object) field.ErrorList {
+	env := obj.(*fnv1alpha1.DrupalEnvironment)
+	if old != nil && equality.Semantic.DeepEqual(old.(*fnv1alpha1.DrupalEnvironment).Spec, env.Spec) {
+		return nil
+	}
+
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	if env.Spec.Application == "" {
+		errs = append(errs, field.Required(spec.Child("application"), ""))
+	}
+
+	drupal := env.Spec.Drupal
+	drupalPath := spec.Child("drupal")
+	if drupal.Tag == "" {
+		errs = append(errs, field.Required(drupalPath.Child("tag"), ""))
+	}
+	if drupal.MinReplicas < 1 {
+		errs = append(errs, field.Invalid(drupalPath.Child("minReplicas"), drupal.MinReplicas, "must be at least 1"))
+	}
+	if drupal.MaxReplicas < drupal.MinReplicas {
+		errs = append(errs, field.Invalid(drupalPath.Child("maxReplicas"), drupal.MaxReplicas, "must not be less than minReplicas"))
+	}
+	if t := drupal.TargetCPUUtilizationPercentage; t != nil && (*t < 1 || *t > 100) {
+		errs = append(errs, field.Invalid(drupalPath.Child("targetCPUUtilizationPercentage"), *t, "must be between 1 and 100"))
+	}
+
+	errs = append(errs, validateRolloutStrategy(drupalPath.Child("rolloutStrategy"), drupal.RolloutStrategy)...)
+	errs = append(errs, validateAnalysis(drupalPath.Child("analysis"), drupal.Analysis)...)
+
+	if env.Spec.Phpfpm.Procs < 1 {
+		errs = append(errs, field.Invalid(spec.Child("phpfpm", "procs"), env.Spec.Phpfpm.Procs, "must be at least 1"))
+	}
+	if env.Spec.ProxySQL.Replicas < 0 {
+		errs = append(errs, field.Invalid(spec.Child("proxySQL", "replicas"), env.Spec.ProxySQL.Replicas, "must not be negative"))
+	}
+
+	errs = append(errs, validateResources(spec.Child("apache", "cpu"), env.Spec.Apache.Cpu)...)
+	errs = append(errs, validateResources(spec.Child("apache", "memory"), env.Spec.Apache.Memory)...)
+	errs = append(errs, validateResources(spec.Child("phpfpm", "cpu"), env.Spec.Phpfpm.Cpu)...)
+	errs = append(errs, validateResources(spec.Child("proxySQL", "cpu"), env.Spec.ProxySQL.Cpu)...)
+	errs = append(errs, validateResources(spec.Child("proxySQL", "memory"), env.Spec.ProxySQL.Memory)...)
+	errs = append(errs, validateMaintenance(spec.Child("maintenance"), env.Spec.Maintenance)...)
+
+	if limit := env.Spec.ReleaseHistoryLimit; limit != nil && *limit < 1 {
+		errs = append(errs, field.Invalid(spec.Child("releaseHistoryLimit"), *limit, "must be at least 1"))
+	}
+	var releases []fnv1alpha1.Release
+	if old != nil {
+		releases = old.(*fnv1alpha1.DrupalEnvironment).Status.Releases
+	}
+	errs = append(errs, validateRollbackTo(spec.Child("rollbackTo"), env.Spec.RollbackTo, releases)...)
+	return errs
+}
+
+// validateRollbackTo checks that a rollback names a release recorded in the status, either by its revision or by the
+// revision it redeployed
+func validateRollbackTo(path *field.Path, rollbackTo *int64, releases []fnv1alpha1.Release) field.ErrorList {
+	if rollbackTo == nil {
+		return nil
+	}
+	for _, release := range releases {
+		if release.Revision == *rollbackTo || (release.RollbackOf != nil && *release.RollbackOf == *rollbackTo) {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Invalid(path, *rollbackTo, "must be the revision of an entry of status.releases")}
+}
+
+// validateResources checks that a request doesn't exceed its limit, when both are set
+func validateResources(path *field.Path, r fnv1alpha1.Resources) field.ErrorList {
+	if r.Request.IsZero() || r.Limit.IsZero() || r.Request.Cmp(r.Limit) <= 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path.Child("request"), r.Request.String(), "must not be greater than the limit")}
+}
+
+// validateRolloutStrategy checks that a rollout strategy is either blue/green or canary, and that each canary step
+// either sets a weight or pauses
+func validateRolloutStrategy(path *field.Path, strategy *fnv1alpha1.RolloutStrategy) field.ErrorList {
+	if strategy == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if (strategy.BlueGreen == nil) == (strategy.Canary == nil) {
+		errs = append(errs, field.Invalid(path, "", "exactly one of blueGreen and canary must be set"))
+	}
+
+	if blueGreen := strategy.BlueGreen; blueGreen != nil {
+		path := path.Child("blueGreen")
+		if s := blueGreen.AutoPromotionSeconds; s != nil && *s < 0 {
+			errs = append(errs, field.Invalid(path.Child("autoPromotionSeconds"), *s, "must not be negative"))
+		}
+		if s := blueGreen.ScaleDownDelaySeconds; s != nil && *s < 0 {
+			errs = append(errs, field.Invalid(path.Child("scaleDownDelaySeconds"), *s, "must not be negative"))
+		}
+	}
+
+	if canary := strategy.Canary; canary != nil {
+		path := path.Child("canary")
+		if len(canary.Steps) == 0 {
+			errs = append(errs, field.Required(path.Child("steps"), ""))
+		}
+		for i, step := range canary.Steps {
+			stepPath := path.Child("steps").Index(i)
+			if (step.SetWeight == nil) == (step.Pause == nil) {
+				errs = append(errs, field.Invalid(stepPath, "", "exactly one of setWeight and pause must be set"))
+			}
+			if w := step.SetWeight; w != nil && (*w < 0 || *w > 100) {
+				errs = append(errs, field.Invalid(stepPath.Child("setWeight"), *w, "must be between 0 and 100"))
+			}
+			if step.Pause != nil && step.Pause.DurationSeconds != nil && *step.Pause.DurationSeconds < 0 {
+				errs = append(errs, field.Invalid(stepPath.Child("pause", "durationSeconds"), *step.Pause.DurationSeconds, "must not be negative"))
+			}
+		}
+		if isZero(canary.MaxSurge) && isZero(canary.MaxUnavailable) {
+			errs = append(errs, field.Invalid(path.Child("maxSurge"), canary.MaxSurge.String(), "must not be 0 when maxUnavailable is 0"))
+		}
+	}
+	return errs
+}
+
+// validateAnalysis checks that an analysis has HTTP checks or an error rate, and that the error rate can be queried
+func validateAnalysis(path *field.Path, analysis *fnv1alpha1.AnalysisSpec) field.ErrorList {
+	if analysis == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if analysis.HTTP == nil && analysis.ErrorRate == nil {
+		errs = append(errs, field.Invalid(path, "", "at least one of http and errorRate must be set"))
+	}
+	if analysis.IntervalSeconds < 0 {
+		errs = append(errs, field.Invalid(path.Child("intervalSeconds"), analysis.IntervalSeconds, "must not be negative"))
+	}
+	if analysis.Count < 0 {
+		errs = append(errs, field.Invalid(path.Child("count"), analysis.Count, "must not be negative"))
+	}
+	if analysis.FailureLimit < 0 {
+		errs = append(errs, field.Invalid(path.Child("failureLimit"), analysis.FailureLimit, "must not be negative"))
+	}
+
+	if check := analysis.HTTP; check != nil {
+		path := path.Child("http")
+		if check.Path != "" && (!strings.HasPrefix(check.Path, "/") || strings.ContainsAny(check.Path, " ?#")) {
+			errs = append(errs, field.Invalid(path.Child("path"), check.Path, "must start with / and contain no spaces, ? or #"))
+		}
+		if s := check.ExpectedStatus; s != 0 && (s < 100 || s > 599) {
+			errs = append(errs, field.Invalid(path.Child("expectedStatus"), s, "must be an HTTP status code"))
+		}
+		if check.TimeoutSeconds < 0 {
+			errs = append(errs, field.Invalid(path.Child("timeoutSeconds"), check.TimeoutSeconds, "must not be negative"))
+		}
+	}
+
+	if errorRate := analysis.ErrorRate; errorRate != nil {
+		path := path.Child("errorRate")
+		if u, err := url.Parse(errorRate.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("address"), errorRate.Address, "must be an http or https URL"))
+		}
+		if errorRate.Query == "" {
+			errs = append(errs, field.Required(path.Child("query"), ""))
+		}
+		if rate, err := strconv.ParseFloat(errorRate.MaxErrorRate, 64); err != nil || rate < 0 || rate > 1 {
+			errs = append(errs, field.Invalid(path.Child("maxErrorRate"), errorRate.MaxErrorRate, "must be a decimal between 0 and 1"))
+		}
+	}
+	return errs
+}
+
+// isZero returns true if an optional count or percentage is set to 0 or 0%
+func isZero(v *intstr.IntOrString) bool {
+	if v == nil {
+		return false
+	}
+	if v.Type == intstr.Int {
+		return v.IntVal == 0
+	}
+	return v.StrVal == "0%" || v.StrVal == "0"
+}
+
+// validateMaintenance checks that the allowed IPs of a maintenance mode are IP addresses or CIDR ranges, which are
+// written to the configuration of the maintenance responder
+func validateMaintenance(path *field.Path, m *fnv1alpha1.MaintenanceSpec) field.ErrorList {
+	if m == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, ip := range m.AllowedIPs {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			errs = append(errs, field.Invalid(path.Child("allowedIPs").Index(i), ip, "must be an IP address or a CIDR range"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+// newValidEnvironment returns a DrupalEnvironment that passes validation
+func newValidEnvironment() *fnv1alpha1.DrupalEnvironment {
+	return &fnv1alpha1.DrupalEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+		Spec: fnv1alpha1.DrupalEnvironmentSpec{
+			Application: "wlgore-app",
+			Drupal:      fnv1alpha1.SpecDrupal{Tag: "v1", MinReplicas: 1, MaxReplicas: 2},
+			Phpfpm:      fnv1alpha1.SpecPhpFpm{Procs: 4},
+		},
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	canary := func(steps ...fnv1alpha1.CanaryStep) *fnv1alpha1.RolloutStrategy {
+		return &fnv1alpha1.RolloutStrategy{Canary: &fnv1alpha1.CanaryRolloutStrategy{Steps: steps}}
+	}
+	zero := intstr.FromInt(0)
+	zeroPercent := intstr.FromString("0%")
+	onePercent := intstr.FromString("1%")
+
+	cases := []struct {
+		name   string
+		mutate func(env *fnv1alpha1.DrupalEnvironment)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {},
+		},
+		{
+			name: "required fields",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Application = ""
+				env.Spec.Drupal.Tag = ""
+			},
+			want: []string{"spec.application", "spec.drupal.tag"},
+		},
+		{
+			name:   "no replicas",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) { env.Spec.Drupal.MinReplicas = 0 },
+			want:   []string{"spec.drupal.minReplicas"},
+		},
+		{
+			name:   "fewer maximum than minimum replicas",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) { env.Spec.Drupal.MaxReplicas = 0 },
+			want:   []string{"spec.drupal.maxReplicas"},
+		},
+		{
+			name: "CPU utilization above 100",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.TargetCPUUtilizationPercentage = int32Ptr(101)
+			},
+			want: []string{"spec.drupal.targetCPUUtilizationPercentage"},
+		},
+		{
+			name:   "no CPU utilization",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) { env.Spec.Drupal.TargetCPUUtilizationPercentage = int32Ptr(0) },
+			want:   []string{"spec.drupal.targetCPUUtilizationPercentage"},
+		},
+		{
+			name: "blue/green",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = &fnv1alpha1.RolloutStrategy{BlueGreen: &fnv1alpha1.BlueGreenRolloutStrategy{
+					AutoPromotionSeconds: int32Ptr(0), ScaleDownDelaySeconds: int32Ptr(60),
+				}}
+			},
+		},
+		{
+			name: "negative blue/green delays",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = &fnv1alpha1.RolloutStrategy{BlueGreen: &fnv1alpha1.BlueGreenRolloutStrategy{
+					AutoPromotionSeconds: int32Ptr(-1), ScaleDownDelaySeconds: int32Ptr(-1),
+				}}
+			},
+			want: []string{"spec.drupal.rolloutStrategy.blueGreen.autoPromotionSeconds", "spec.drupal.rolloutStrategy.blueGreen.scaleDownDelaySeconds"},
+		},
+		{
+			name: "no strategy",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = &fnv1alpha1.RolloutStrategy{}
+			},
+			want: []string{"spec.drupal.rolloutStrategy"},
+		},
+		{
+			name: "both strategies",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = canary(fnv1alpha1.CanaryStep{SetWeight: int32Ptr(50)})
+				env.Spec.Drupal.RolloutStrategy.BlueGreen = &fnv1alpha1.BlueGreenRolloutStrategy{}
+			},
+			want: []string{"spec.drupal.rolloutStrategy"},
+		},
+		{
+			name: "canary",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = canary(
+					fnv1alpha1.CanaryStep{SetWeight: int32Ptr(0)},
+					fnv1alpha1.CanaryStep{Pause: &fnv1alpha1.CanaryPause{DurationSeconds: int32Ptr(60)}},
+					fnv1alpha1.CanaryStep{SetWeight: int32Ptr(100)},
+					fnv1alpha1.CanaryStep{Pause: &fnv1alpha1.CanaryPause{}},
+				)
+				env.Spec.Drupal.RolloutStrategy.Canary.MaxSurge = &onePercent
+				env.Spec.Drupal.RolloutStrategy.Canary.MaxUnavailable = &zero
+			},
+		},
+		{
+			name:   "canary without steps",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) { env.Spec.Drupal.RolloutStrategy = canary() },
+			want:   []string{"spec.drupal.rolloutStrategy.canary.steps"},
+		},
+		{
+			name: "invalid canary steps",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = canary(
+					fnv1alpha1.CanaryStep{},
+					fnv1alpha1.CanaryStep{SetWeight: int32Ptr(10), Pause: &fnv1alpha1.CanaryPause{}},
+					fnv1alpha1.CanaryStep{SetWeight: int32Ptr(101)},
+					fnv1alpha1.CanaryStep{Pause: &fnv1alpha1.CanaryPause{DurationSeconds: int32Ptr(-1)}},
+				)
+			},
+			want: []string{
+				"spec.drupal.rolloutStrategy.canary.steps[0]",
+				"spec.drupal.rolloutStrategy.canary.steps[1]",
+				"spec.drupal.rolloutStrategy.canary.steps[2].setWeight",
+				"spec.drupal.rolloutStrategy.canary.steps[3].pause.durationSeconds",
+			},
+		},
+		{
+			name: "canary that can't replace pods",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.RolloutStrategy = canary(fnv1alpha1.CanaryStep{SetWeight: int32Ptr(50)})
+				env.Spec.Drupal.RolloutStrategy.Canary.MaxSurge = &zeroPercent
+				env.Spec.Drupal.RolloutStrategy.Canary.MaxUnavailable = &zero
+			},
+			want: []string{"spec.drupal.rolloutStrategy.canary.maxSurge"},
+		},
+		{
+			name: "analysis",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{
+					HTTP: &fnv1alpha1.HTTPAnalysis{Path: "/user/login", ExpectedStatus: 200, TimeoutSeconds: 5},
+					ErrorRate: &fnv1alpha1.ErrorRateAnalysis{
+						Address:      "http://prometheus.monitoring:9090",
+						Query:        `sum(rate(http_requests_total{code=~"5.."}[1m])) / sum(rate(http_requests_total[1m]))`,
+						MaxErrorRate: "0.05",
+					},
+					IntervalSeconds: 60,
+					Count:           3,
+					FailureLimit:    1,
+				}
+			},
+		},
+		{
+			name: "analysis without checks",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{IntervalSeconds: -1, Count: -1, FailureLimit: -1}
+			},
+			want: []string{
+				"spec.drupal.analysis",
+				"spec.drupal.analysis.intervalSeconds",
+				"spec.drupal.analysis.count",
+				"spec.drupal.analysis.failureLimit",
+			},
+		},
+		{
+			name: "invalid HTTP check",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{
+					HTTP: &fnv1alpha1.HTTPAnalysis{Path: "/user login", ExpectedStatus: 700, TimeoutSeconds: -1},
+				}
+			},
+			want: []string{
+				"spec.drupal.analysis.http.path",
+				"spec.drupal.analysis.http.expectedStatus",
+				"spec.drupal.analysis.http.timeoutSeconds",
+			},
+		},
+		{
+			name: "relative HTTP check path",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{HTTP: &fnv1alpha1.HTTPAnalysis{Path: "user"}}
+			},
+			want: []string{"spec.drupal.analysis.http.path"},
+		},
+		{
+			name: "invalid error rate",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{
+					ErrorRate: &fnv1alpha1.ErrorRateAnalysis{Address: "prometheus:9090", MaxErrorRate: "5%"},
+				}
+			},
+			want: []string{
+				"spec.drupal.analysis.errorRate.address",
+				"spec.drupal.analysis.errorRate.query",
+				"spec.drupal.analysis.errorRate.maxErrorRate",
+			},
+		},
+		{
+			name: "error rate above 1",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Drupal.Analysis = &fnv1alpha1.AnalysisSpec{
+					ErrorRate: &fnv1alpha1.ErrorRateAnalysis{Address: "https://prometheus.example.com", Query: "up", MaxErrorRate: "1.5"},
+				}
+			},
+			want: []string{"spec.drupal.analysis.errorRate.maxErrorRate"},
+		},
+		{
+			name: "invalid counts",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Phpfpm.Procs = 0
+				env.Spec.ProxySQL.Replicas = -1
+				env.Spec.ReleaseHistoryLimit = int32Ptr(0)
+			},
+			want: []string{"spec.phpfpm.procs", "spec.proxySQL.replicas", "spec.releaseHistoryLimit"},
+		},
+		{
+			name: "resources",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Apache.Cpu = fnv1alpha1.Resources{Request: resource.MustParse("500m"), Limit: resource.MustParse("1")}
+				env.Spec.ProxySQL.Memory = fnv1alpha1.Resources{Request: resource.MustParse("2Gi")}
+			},
+		},
+		{
+			name: "requests above limits",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Apache.Memory = fnv1alpha1.Resources{Request: resource.MustParse("2Gi"), Limit: resource.MustParse("1Gi")}
+				env.Spec.Phpfpm.Cpu = fnv1alpha1.Resources{Request: resource.MustParse("2"), Limit: resource.MustParse("1500m")}
+			},
+			want: []string{"spec.apache.memory.request", "spec.phpfpm.cpu.request"},
+		},
+		{
+			name: "maintenance",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) {
+				env.Spec.Maintenance = &fnv1alpha1.MaintenanceSpec{AllowedIPs: []string{"10.0.0.0/33", "192.0.2.1"}}
+			},
+			want: []string{"spec.maintenance.allowedIPs[0]"},
+		},
+		{
+			name:   "rollback of a new environment",
+			mutate: func(env *fnv1alpha1.DrupalEnvironment) { env.Spec.RollbackTo = int64Ptr(1) },
+			want:   []string{"spec.rollbackTo"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			env := newValidEnvironment()
+			c.mutate(env)
+			errs := validateEnvironment(context.TODO(), nil, env, nil)
+			if got := errorFields(errs); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("validateEnvironment returned errors for %v, want %v: %v", got, c.want, errs)
+			}
+
+			// Updates that don't change the spec are allowed, so that environments created before the webhook can be
+			// managed
+			if errs := validateEnvironment(context.TODO(), nil, env, env.DeepCopy()); len(errs) > 0 {
+				t.Errorf("validateEnvironment rejected an update that doesn't change the spec: %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateEnvironmentRollbackTo(t *testing.T) {
+	old := newValidEnvironment()
+	old.Status.Releases = []fnv1alpha1.Release{
+		{Revision: 1, DrupalTag: "v1"},
+		{Revision: 2, DrupalTag: "v2"},
+		{Revision: 3, DrupalTag: "v1", RollbackOf: int64Ptr(1)},
+	}
+
+	cases := []struct {
+		name       string
+		rollbackTo int64
+		valid      bool
+	}{
+		{"recorded revision", 2, true},
+		{"revision redeployed by a rollback", 1, true},
+		{"revision of a rollback", 3, true},
+		{"unknown revision", 4, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			env := old.DeepCopy()
+			env.Spec.RollbackTo = int64Ptr(c.rollbackTo)
+			errs := validateEnvironment(context.TODO(), nil, env, old)
+			if c.valid && len(errs) > 0 {
+				t.Errorf("validateEnvironment rejected a rollback to revision %d: %v", c.rollbackTo, errs)
+			}
+			if !c.valid && !reflect.DeepEqual(errorFields(errs), []string{"spec.rollbackTo"}) {
+				t.Errorf("validateEnvironment returned %v for a rollback to revision %d, want spec.rollbackTo", errs, c.rollbackTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+	"github.com/acquia/fn-drupal-operator/pkg/common"
+)
+
+// maxCronJobNameLength leaves room for the 11 characters the CronJob controller appends to the names of its Jobs
+const maxCronJobNameLength = 52
+
+// validateSite checks a Site's spec, and that it doesn't claim the domains or CronJob names of other Sites. Checks
+// against other objects only apply to the values an update adds, so that a Site doesn't become impossible to update
+// because of changes elsewhere. An update that doesn't change the spec is always allowed.
+func validateSite(ctx context.Context, c client.Client, obj, old runtime.Object) field.ErrorList {
+	site := obj.(*fnv1alpha1.Site)
+	oldSite := &fnv1alpha1.Site{}
+	if old != nil {
+		oldSite = old.(*fnv1alpha1.Site)
+		if equality.Semantic.DeepEqual(oldSite.Spec, site.Spec) {
+			return nil
+		}
+	}
+
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+
+	if site.Spec.Environment == "" {
+		errs = append(errs, field.Required(spec.Child("environment"), ""))
+	} else if site.Spec.Environment != oldSite.Spec.Environment {
+		env := &fnv1alpha1.DrupalEnvironment{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: site.Namespace, Name: site.Spec.Environment}, env)
+		if err != nil && errors.IsNotFound(err) {
+			errs = append(errs, field.NotFound(spec.Child("environment"), site.Spec.Environment))
+		} else if err != nil {
+			errs = append(errs, field.InternalError(spec.Child("environment"), err))
+		}
+	}
+
+	errs = append(errs, validateSiteSpec(spec, site)...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Only list the other Sites once the spec is otherwise valid
+	sites := &fnv1alpha1.SiteList{}
+	if err := c.List(ctx, &client.ListOptions{}, sites); err != nil {
+		return field.ErrorList{field.InternalError(spec, err)}
+	}
+	var others []fnv1alpha1.Site
+	for _, s := range sites.Items {
+		if s.Namespace != site.Namespace || s.Name != site.Name {
+			others = append(others, s)
+		}
+	}
+
+	errs = append(errs, validateDomainConflicts(spec, site, oldSite, others)...)
+	errs = append(errs, validateCronJobConflicts(spec, site, oldSite, others)...)
+	return errs
+}
+
+// validateSiteSpec checks the fields of a Site's spec that don't depend on other objects
+func validateSiteSpec(spec *field.Path, site *fnv1alpha1.Site) field.ErrorList {
+	var errs field.ErrorList
+
+	domainsPath := spec.Child("domains")
+	if len(site.Spec.Domains) == 0 {
+		errs = append(errs, field.Required(domainsPath, ""))
+	}
+	domains := map[string]bool{}
+	for i, d := range site.Spec.Domains {
+		if domains[d] {
+			errs = append(errs, field.Duplicate(domainsPath.Index(i), d))
+		}
+		domains[d] = true
+		for _, msg := range validation.IsDNS1123Subdomain(strings.TrimPrefix(d, "*.")) {
+			errs = append(errs, field.Invalid(domainsPath.Index(i), d, msg))
+		}
+	}
+
+	tlsDomains := map[string]bool{}
+	for i, t := range site.Spec.DomainTLS {
+		path := spec.Child("domainTLS").Index(i)
+		if !domains[t.Domain] {
+			errs = append(errs, field.Invalid(path.Child("domain"), t.Domain, "must be one of spec.domains"))
+		}
+		if tlsDomains[t.Domain] {
+			errs = append(errs, field.Duplicate(path.Child("domain"), t.Domain))
+		}
+		tlsDomains[t.Domain] = true
+		if t.SecretName != "" {
+			if t.Disabled {
+				errs = append(errs, field.Invalid(path.Child("secretName"), t.SecretName, "must not be set when TLS is disabled"))
+			}
+			for _, msg := range validation.IsDNS1123Subdomain(t.SecretName) {
+				errs = append(errs, field.Invalid(path.Child("secretName"), t.SecretName, msg))
+			}
+		}
+	}
+	if site.Spec.HSTS != nil && site.Spec.HSTS.MaxAge < 0 {
+		errs = append(errs, field.Invalid(spec.Child("hsts", "maxAge"), site.Spec.HSTS.MaxAge, "must not be negative"))
+	}
+
+	routePaths := map[string]bool{}
+	drupalRoutes := 0
+	for i, route := range site.Spec.Routes {
+		path := spec.Child("routes").Index(i)
+		if !strings.HasPrefix(route.Path, "/") {
+			errs = append(errs, field.Invalid(path.Child("path"), route.Path, "must start with /"))
+		} else if strings.ContainsAny(route.Path, "?#") {
+			errs = append(errs, field.Invalid(path.Child("path"), route.Path, "must not contain a query or fragment"))
+		}
+		cleanPath := strings.TrimRight(route.Path, "/")
+		if routePaths[cleanPath] {
+			errs = append(errs, field.Duplicate(path.Child("path"), route.Path))
+		}
+		routePaths[cleanPath] = true
+		switch route.PathType {
+		case "", fnv1alpha1.PathTypePrefix, fnv1alpha1.PathTypeExact:
+		default:
+			errs = append(errs, field.NotSupported(path.Child("pathType"), route.PathType,
+				[]string{string(fnv1alpha1.PathTypePrefix), string(fnv1alpha1.PathTypeExact)}))
+		}
+
+		target := route.Target
+		portSet := target.ServicePort.String() != "0" && target.ServicePort.String() != ""
+		switch {
+		case target.ServiceName == "" && portSet:
+			errs = append(errs, field.Required(path.Child("target", "serviceName"), "when servicePort is set"))
+		case target.ServiceName == "" || target.ServiceName == fnv1alpha1.DrupalServiceName:
+			drupalRoutes++
+		}
+		if target.ServiceName != "" {
+			for _, msg := range validation.IsDNS1035Label(target.ServiceName) {
+				errs = append(errs, field.Invalid(path.Child("target", "serviceName"), target.ServiceName, msg))
+			}
+			if !portSet {
+				errs = append(errs, field.Required(path.Child("target", "servicePort"), ""))
+			}
+		}
+	}
+	if len(site.Spec.Routes) > 0 && drupalRoutes == 0 {
+		errs = append(errs, field.Invalid(spec.Child("routes"), "", "at least one route must target Drupal"))
+	}
+
+	redirectHosts := map[string]bool{}
+	for i, redirect := range site.Spec.Redirects {
+		path := spec.Child("redirects").Index(i)
+		if redirectHosts[redirect.FromHost] {
+			errs = append(errs, field.Duplicate(path.Child("fromHost"), redirect.FromHost))
+		}
+		redirectHosts[redirect.FromHost] = true
+		if domains[redirect.FromHost] {
+			errs = append(errs, field.Invalid(path.Child("fromHost"), redirect.FromHost, "must not be one of spec.domains"))
+		}
+		for _, msg := range validation.IsDNS1123Subdomain(redirect.FromHost) {
+			errs = append(errs, field.Invalid(path.Child("fromHost"), redirect.FromHost, msg))
+		}
+		if err := validateRedirectURL(redirect.ToURL); err != nil {
+			errs = append(errs, field.Invalid(path.Child("toURL"), redirect.ToURL, err.Error()))
+		}
+		switch redirect.Code {
+		case 0, 301, 302:
+		default:
+			errs = append(errs, field.NotSupported(path.Child("code"), redirect.Code, []string{"301", "302"}))
+		}
+	}
+	// Redirecting to another redirect host could loop, so redirects can't be chained
+	for i, redirect := range site.Spec.Redirects {
+		if host := redirectTargetHost(redirect); redirectHosts[host] {
+			errs = append(errs, field.Invalid(spec.Child("redirects").Index(i).Child("toURL"), redirect.ToURL,
+				fmt.Sprintf("redirects to %s, which is itself redirected", host)))
+		}
+	}
+
+	cronNames := map[string]bool{}
+	for i, cron := range site.Spec.Crons {
+		path := spec.Child("crons").Index(i)
+		if cronNames[cron.Name] || (site.Spec.Backups != nil && cron.Name == site.BackupCronJobName()) {
+			errs = append(errs, field.Duplicate(path.Child("name"), cron.Name))
+		}
+		cronNames[cron.Name] = true
+		for _, msg := range validation.IsDNS1123Subdomain(cron.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), cron.Name, msg))
+		}
+		if len(cron.Name) > maxCronJobNameLength {
+			errs = append(errs, field.TooLong(path.Child("name"), cron.Name, maxCronJobNameLength))
+		}
+		if len(cron.Command) == 0 {
+			errs = append(errs, field.Required(path.Child("command"), ""))
+		}
+		if err := common.ValidateSchedule(cron.Schedule); err != nil {
+			errs = append(errs, field.Invalid(path.Child("schedule"), cron.Schedule, err.Error()))
+		}
+		switch cron.ConcurrencyPolicy {
+		case "", batchv1b1.AllowConcurrent, batchv1b1.ForbidConcurrent, batchv1b1.ReplaceConcurrent:
+		default:
+			errs = append(errs, field.NotSupported(path.Child("concurrencyPolicy"), cron.ConcurrencyPolicy,
+				[]string{string(batchv1b1.AllowConcurrent), string(batchv1b1.ForbidConcurrent), string(batchv1b1.ReplaceConcurrent)}))
+		}
+	}
+
+	if backups := site.Spec.Backups; backups != nil {
+		path := spec.Child("backups")
+		if err := common.ValidateSchedule(backups.Schedule); err != nil {
+			errs = append(errs, field.Invalid(path.Child("schedule"), backups.Schedule, err.Error()))
+		}
+		switch backups.Method {
+		case "", fnv1alpha1.BackupMethodDrush, fnv1alpha1.BackupMethodMysqldump:
+		default:
+			errs = append(errs, field.NotSupported(path.Child("method"), backups.Method,
+				[]string{string(fnv1alpha1.BackupMethodDrush), string(fnv1alpha1.BackupMethodMysqldump)}))
+		}
+		if err := backups.Target.Validate(); err != nil {
+			errs = append(errs, field.Invalid(path.Child("target"), "", err.Error()))
+		}
+		if backups.RetentionCount < 0 {
+			errs = append(errs, field.Invalid(path.Child("retentionCount"), backups.RetentionCount, "must not be negative"))
+		}
+	}
+
+	errs = append(errs, validateMaintenance(spec.Child("maintenance"), site.Spec.Maintenance)...)
+
+	switch site.Spec.DeletionPolicy {
+	case "", fnv1alpha1.DeletionPolicyDelete, fnv1alpha1.DeletionPolicyRetain:
+	case fnv1alpha1.DeletionPolicySnapshot:
+		if site.Spec.Backups == nil {
+			errs = append(errs, field.Invalid(spec.Child("deletionPolicy"), site.Spec.DeletionPolicy, "requires spec.backups"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(spec.Child("deletionPolicy"), site.Spec.DeletionPolicy, []string{
+			string(fnv1alpha1.DeletionPolicyDelete), string(fnv1alpha1.DeletionPolicySnapshot), string(fnv1alpha1.DeletionPolicyRetain),
+		}))
+	}
+
+	return errs
+}
+
+// validateDomainConflicts rejects domains and redirect hosts added to a Site, or routes added to its domains, when
+// another Site in any namespace already routes the same path of the host or redirects it. Redirects to the redirect
+// hosts of other Sites, and redirect hosts other Sites redirect to, are rejected as they would chain redirects.
+func validateDomainConflicts(spec *field.Path, site, oldSite *fnv1alpha1.Site, others []fnv1alpha1.Site) field.ErrorList {
+	existing := map[string]bool{}
+	if equality.Semantic.DeepEqual(oldSite.Routes(), site.Routes()) {
+		for _, d := range oldSite.Spec.Domains {
+			existing[d] = true
+		}
+	}
+	oldRedirects := map[fnv1alpha1.SiteRedirect]bool{}
+	for _, redirect := range oldSite.Spec.Redirects {
+		oldRedirects[redirect] = true
+	}
+
+	conflict := func(host string) *fnv1alpha1.Site {
+		for i := range others {
+			if site.Conflicts(&others[i], host) {
+				return &others[i]
+			}
+		}
+		return nil
+	}
+
+	var errs field.ErrorList
+	for i, d := range site.Spec.Domains {
+		if existing[d] {
+			continue
+		}
+		if other := conflict(d); other != nil {
+			errs = append(errs, field.Forbidden(spec.Child("domains").Index(i),
+				fmt.Sprintf("%s is a domain of Site %s/%s, which routes the same paths or redirects it", d, other.Namespace, other.Name)))
+		}
+	}
+
+	for i, redirect := range site.Spec.Redirects {
+		if oldRedirects[redirect] {
+			continue
+		}
+		path := spec.Child("redirects").Index(i)
+		if other := conflict(redirect.FromHost); other != nil {
+			errs = append(errs, field.Forbidden(path.Child("fromHost"),
+				fmt.Sprintf("%s is a host of Site %s/%s", redirect.FromHost, other.Namespace, other.Name)))
+		}
+		target := redirectTargetHost(redirect)
+		for _, other := range others {
+			if other.Redirect(target) != nil {
+				errs = append(errs, field.Forbidden(path.Child("toURL"),
+					fmt.Sprintf("%s is redirected by Site %s/%s", target, other.Namespace, other.Name)))
+				break
+			}
+		}
+		for _, other := range others {
+			for _, r := range other.Spec.Redirects {
+				if redirectTargetHost(r) == redirect.FromHost {
+					errs = append(errs, field.Forbidden(path.Child("fromHost"),
+						fmt.Sprintf("Site %s/%s redirects to %s", other.Namespace, other.Name, redirect.FromHost)))
+					break
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validateRedirectURL checks that the URL of a redirect is an absolute HTTP or HTTPS URL, without a query or fragment
+// that preserving the path would have to be merged with
+func validateRedirectURL(toURL string) error {
+	u, err := url.Parse(toURL)
+	switch {
+	case err != nil:
+		return err
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("must be an http or https URL")
+	case u.Hostname() == "":
+		return fmt.Errorf("must have a host")
+	case u.RawQuery != "" || u.Fragment != "" || strings.HasSuffix(toURL, "?") || strings.HasSuffix(toURL, "#"):
+		return fmt.Errorf("must not have a query or fragment")
+	case u.User != nil:
+		return fmt.Errorf("must not have user information")
+	}
+	return nil
+}
+
+// redirectTargetHost returns the host a redirect sends requests to, or "" if its URL is invalid
+func redirectTargetHost(redirect fnv1alpha1.SiteRedirect) string {
+	u, err := url.Parse(redirect.ToURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// validateCronJobConflicts rejects crons added to a Site whose CronJob would have the name of a CronJob of another
+// Site in the same namespace, including their backup CronJobs
+func validateCronJobConflicts(spec *field.Path, site, oldSite *fnv1alpha1.Site, others []fnv1alpha1.Site) field.ErrorList {
+	owners := map[string]string{}
+	for _, other := range others {
+		if other.Namespace != site.Namespace {
+			continue
+		}
+		for _, cron := range other.Spec.Crons {
+			owners[cron.Name] = other.Name
+		}
+		if other.Spec.Backups != nil {
+			owners[other.BackupCronJobName()] = other.Name
+		}
+	}
+
+	existing := map[string]bool{}
+	for _, cron := range oldSite.Spec.Crons {
+		existing[cron.Name] = true
+	}
+
+	var errs field.ErrorList
+	for i, cron := range site.Spec.Crons {
+		if owner, ok := owners[cron.Name]; ok && !existing[cron.Name] {
+			errs = append(errs, field.Forbidden(spec.Child("crons").Index(i).Child("name"),
+				fmt.Sprintf("CronJob %s belongs to Site %s", cron.Name, owner)))
+		}
+	}
+	if site.Spec.Backups != nil && oldSite.Spec.Backups == nil {
+		if owner, ok := owners[site.BackupCronJobName()]; ok {
+			errs = append(errs, field.Forbidden(spec.Child("backups"),
+				fmt.Sprintf("the backup CronJob %s would replace a CronJob of Site %s", site.BackupCronJobName(), owner)))
+		}
+	}
+	return errs
+}
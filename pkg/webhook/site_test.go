@@ -0,0 +1,468 @@
+package webhook
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/acquia/fn-drupal-operator/pkg/apis"
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+const testNamespace = "wlgore"
+
+// newTestClient returns a fake client holding the given objects and the DrupalEnvironment Sites are validated against
+func newTestClient(t *testing.T, objs ...runtime.Object) client.Client {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	objs = append(objs, &fnv1alpha1.DrupalEnvironment{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: testNamespace},
+		Spec:       fnv1alpha1.DrupalEnvironmentSpec{Application: "wlgore-app"},
+	})
+	return fake.NewFakeClient(objs...)
+}
+
+// errorFields returns the fields of the errors, in order
+func errorFields(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+// newValidSite returns a Site that passes validation
+func newValidSite(namespace, name string, domains ...string) *fnv1alpha1.Site {
+	return &fnv1alpha1.Site{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       fnv1alpha1.SiteSpec{Environment: "prod", Domains: domains},
+	}
+}
+
+func TestValidateSite(t *testing.T) {
+	pvcTarget := fnv1alpha1.BackupTarget{PVC: &fnv1alpha1.PVCBackupTarget{ClaimName: "backups"}}
+
+	cases := []struct {
+		name   string
+		mutate func(s *fnv1alpha1.Site)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(s *fnv1alpha1.Site) {},
+		},
+		{
+			name:   "wildcard domain",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Domains = []string{"*.wlgore.com"} },
+		},
+		{
+			name:   "no environment",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Environment = "" },
+			want:   []string{"spec.environment"},
+		},
+		{
+			name:   "unknown environment",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Environment = "stage" },
+			want:   []string{"spec.environment"},
+		},
+		{
+			name:   "no domains",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Domains = nil },
+			want:   []string{"spec.domains"},
+		},
+		{
+			name:   "duplicate domain",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Domains = []string{"wlgore.com", "wlgore.com"} },
+			want:   []string{"spec.domains[1]"},
+		},
+		{
+			name:   "invalid domain",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.Domains = []string{"wl_gore.com"} },
+			want:   []string{"spec.domains[0]"},
+		},
+		{
+			name: "TLS of another domain",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.DomainTLS = []fnv1alpha1.DomainTLS{{Domain: "gore.com", Disabled: true}}
+			},
+			want: []string{"spec.domainTLS[0].domain"},
+		},
+		{
+			name: "duplicate TLS",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.DomainTLS = []fnv1alpha1.DomainTLS{{Domain: "wlgore.com", Disabled: true}, {Domain: "wlgore.com"}}
+			},
+			want: []string{"spec.domainTLS[1].domain"},
+		},
+		{
+			name: "Secret of a domain without TLS",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.DomainTLS = []fnv1alpha1.DomainTLS{{Domain: "wlgore.com", Disabled: true, SecretName: "wlgore-cert"}}
+			},
+			want: []string{"spec.domainTLS[0].secretName"},
+		},
+		{
+			name:   "negative HSTS max age",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.HSTS = &fnv1alpha1.HSTSSpec{MaxAge: -1} },
+			want:   []string{"spec.hsts.maxAge"},
+		},
+		{
+			name: "routes",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{
+					{Path: "/"},
+					{Path: "/files", PathType: fnv1alpha1.PathTypeExact, Target: fnv1alpha1.RouteTarget{ServiceName: "static", ServicePort: intstr.FromInt(8080)}},
+				}
+			},
+		},
+		{
+			name: "relative route path",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "blog"}}
+			},
+			want: []string{"spec.routes[0].path"},
+		},
+		{
+			name: "route path with a query",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/blog?page=1"}}
+			},
+			want: []string{"spec.routes[0].path"},
+		},
+		{
+			name: "duplicate route path",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/blog"}, {Path: "/blog/"}}
+			},
+			want: []string{"spec.routes[1].path"},
+		},
+		{
+			name: "unsupported path type",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/", PathType: "Regex"}}
+			},
+			want: []string{"spec.routes[0].pathType"},
+		},
+		{
+			name: "port without a Service",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/", Target: fnv1alpha1.RouteTarget{ServicePort: intstr.FromInt(8080)}}}
+			},
+			want: []string{"spec.routes[0].target.serviceName", "spec.routes"},
+		},
+		{
+			name: "Service without a port",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/"}, {Path: "/files", Target: fnv1alpha1.RouteTarget{ServiceName: "static"}}}
+			},
+			want: []string{"spec.routes[1].target.servicePort"},
+		},
+		{
+			name: "no route to Drupal",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Routes = []fnv1alpha1.SiteRoute{{Path: "/", Target: fnv1alpha1.RouteTarget{ServiceName: "static", ServicePort: intstr.FromInt(8080)}}}
+			},
+			want: []string{"spec.routes"},
+		},
+		{
+			name: "redirects",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{
+					{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/"},
+					{FromHost: "sale.wlgore.com", ToURL: "https://wlgore.com/sale", Code: 302, PreservePath: true},
+				}
+			},
+		},
+		{
+			name: "duplicate redirect",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{
+					{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/"},
+					{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/www"},
+				}
+			},
+			want: []string{"spec.redirects[1].fromHost"},
+		},
+		{
+			name: "redirect of a domain",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "wlgore.com", ToURL: "https://gore.com/"}}
+			},
+			want: []string{"spec.redirects[0].fromHost"},
+		},
+		{
+			name: "invalid redirect host",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "*.wlgore.com", ToURL: "https://wlgore.com/"}}
+			},
+			want: []string{"spec.redirects[0].fromHost"},
+		},
+		{
+			name: "redirect to another scheme",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "ftp://wlgore.com/"}}
+			},
+			want: []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name: "redirect to a relative URL",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "/home"}}
+			},
+			want: []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name: "redirect to a URL with a query",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/?"}}
+			},
+			want: []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name: "redirect to a URL with user information",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://admin@wlgore.com/"}}
+			},
+			want: []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name: "unsupported redirect code",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/", Code: 307}}
+			},
+			want: []string{"spec.redirects[0].code"},
+		},
+		{
+			name: "chained redirects",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Redirects = []fnv1alpha1.SiteRedirect{
+					{FromHost: "wl-gore.com", ToURL: "https://www.wlgore.com/"},
+					{FromHost: "www.wlgore.com", ToURL: "https://wlgore.com/"},
+				}
+			},
+			want: []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name: "crons",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Crons = []fnv1alpha1.CronSpec{
+					{Name: "wlgore-cron", Command: []string{"drush", "cron"}, Schedule: "*/15 * * * *", ConcurrencyPolicy: batchv1b1.ForbidConcurrent},
+				}
+			},
+		},
+		{
+			name: "duplicate cron",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Crons = []fnv1alpha1.CronSpec{
+					{Name: "wlgore-cron", Command: []string{"drush", "cron"}, Schedule: "@hourly"},
+					{Name: "wlgore-cron", Command: []string{"drush", "cron"}, Schedule: "@daily"},
+				}
+			},
+			want: []string{"spec.crons[1].name"},
+		},
+		{
+			name: "cron named like the backup CronJob",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Crons = []fnv1alpha1.CronSpec{{Name: "wlgore-backups", Command: []string{"drush", "cron"}, Schedule: "@hourly"}}
+				s.Spec.Backups = &fnv1alpha1.BackupsSpec{Schedule: "@daily", Target: pvcTarget}
+			},
+			want: []string{"spec.crons[0].name"},
+		},
+		{
+			name: "invalid cron",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Crons = []fnv1alpha1.CronSpec{{
+					Name:              "wlgore-cron-with-a-name-too-long-for-the-names-of-its-jobs",
+					Schedule:          "every hour",
+					ConcurrencyPolicy: "Queue",
+				}}
+			},
+			want: []string{"spec.crons[0].name", "spec.crons[0].command", "spec.crons[0].schedule", "spec.crons[0].concurrencyPolicy"},
+		},
+		{
+			name: "backups",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Backups = &fnv1alpha1.BackupsSpec{Schedule: "0 3 * * *", Method: fnv1alpha1.BackupMethodMysqldump, Target: pvcTarget, RetentionCount: 7}
+				s.Spec.DeletionPolicy = fnv1alpha1.DeletionPolicySnapshot
+			},
+		},
+		{
+			name: "invalid backups",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Backups = &fnv1alpha1.BackupsSpec{Schedule: "daily", Method: "rsync", RetentionCount: -1}
+			},
+			want: []string{"spec.backups.schedule", "spec.backups.method", "spec.backups.target", "spec.backups.retentionCount"},
+		},
+		{
+			name: "maintenance",
+			mutate: func(s *fnv1alpha1.Site) {
+				s.Spec.Maintenance = &fnv1alpha1.MaintenanceSpec{AllowedIPs: []string{"203.0.113.7", "10.0.0.0/8", "2001:db8::/32", "office"}}
+			},
+			want: []string{"spec.maintenance.allowedIPs[3]"},
+		},
+		{
+			name:   "snapshot without backups",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.DeletionPolicy = fnv1alpha1.DeletionPolicySnapshot },
+			want:   []string{"spec.deletionPolicy"},
+		},
+		{
+			name:   "unsupported deletion policy",
+			mutate: func(s *fnv1alpha1.Site) { s.Spec.DeletionPolicy = "Archive" },
+			want:   []string{"spec.deletionPolicy"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			site := newValidSite(testNamespace, "wlgore", "wlgore.com")
+			c.mutate(site)
+			errs := validateSite(context.TODO(), newTestClient(t), site, nil)
+			if got := errorFields(errs); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("validateSite returned errors for %v, want %v: %v", got, c.want, errs)
+			}
+
+			// Updates that don't change the spec are allowed, so that Sites created before the webhook can be managed
+			if errs := validateSite(context.TODO(), newTestClient(t), site, site.DeepCopy()); len(errs) > 0 {
+				t.Errorf("validateSite rejected an update that doesn't change the spec: %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateSiteConflicts(t *testing.T) {
+	withRoutes := func(s *fnv1alpha1.Site, paths ...string) *fnv1alpha1.Site {
+		for _, path := range paths {
+			s.Spec.Routes = append(s.Spec.Routes, fnv1alpha1.SiteRoute{Path: path})
+		}
+		return s
+	}
+	withRedirect := func(s *fnv1alpha1.Site, fromHost, toURL string) *fnv1alpha1.Site {
+		s.Spec.Redirects = append(s.Spec.Redirects, fnv1alpha1.SiteRedirect{FromHost: fromHost, ToURL: toURL})
+		return s
+	}
+	withCron := func(s *fnv1alpha1.Site, name string) *fnv1alpha1.Site {
+		s.Spec.Crons = append(s.Spec.Crons, fnv1alpha1.CronSpec{Name: name, Command: []string{"drush", "cron"}, Schedule: "@hourly"})
+		return s
+	}
+	withBackups := func(s *fnv1alpha1.Site) *fnv1alpha1.Site {
+		s.Spec.Backups = &fnv1alpha1.BackupsSpec{
+			Schedule: "@daily",
+			Target:   fnv1alpha1.BackupTarget{PVC: &fnv1alpha1.PVCBackupTarget{ClaimName: "backups"}},
+		}
+		return s
+	}
+
+	cases := []struct {
+		name  string
+		site  *fnv1alpha1.Site
+		old   *fnv1alpha1.Site
+		other *fnv1alpha1.Site
+		want  []string
+	}{
+		{
+			name:  "domain of a Site in another namespace",
+			site:  newValidSite(testNamespace, "wlgore", "wlgore.com"),
+			other: newValidSite("gore", "gore", "wlgore.com"),
+			want:  []string{"spec.domains[0]"},
+		},
+		{
+			name:  "another path of a domain",
+			site:  withRoutes(newValidSite(testNamespace, "wlgore", "wlgore.com"), "/"),
+			other: withRoutes(newValidSite("gore", "gore", "wlgore.com"), "/blog"),
+		},
+		{
+			name:  "the same path of a domain",
+			site:  withRoutes(newValidSite(testNamespace, "wlgore", "wlgore.com"), "/", "/blog/"),
+			other: withRoutes(newValidSite("gore", "gore", "wlgore.com"), "/blog"),
+			want:  []string{"spec.domains[0]"},
+		},
+		{
+			// Conflicts that arose elsewhere don't keep a Site from being updated
+			name:  "domain the Site already had",
+			site:  newValidSite(testNamespace, "wlgore", "wlgore.com", "www.wlgore.com"),
+			old:   newValidSite(testNamespace, "wlgore", "wlgore.com"),
+			other: newValidSite("gore", "gore", "wlgore.com"),
+		},
+		{
+			name:  "route added to a domain the Site already had",
+			site:  withRoutes(newValidSite(testNamespace, "wlgore", "wlgore.com"), "/", "/blog"),
+			old:   newValidSite(testNamespace, "wlgore", "wlgore.com"),
+			other: withRoutes(newValidSite("gore", "gore", "wlgore.com"), "/blog"),
+			want:  []string{"spec.domains[0]"},
+		},
+		{
+			name:  "domain redirected by another Site",
+			site:  newValidSite(testNamespace, "wlgore", "www.wlgore.com"),
+			other: withRedirect(newValidSite("gore", "gore", "gore.com"), "www.wlgore.com", "https://gore.com/"),
+			want:  []string{"spec.domains[0]"},
+		},
+		{
+			name:  "redirect of a domain of another Site",
+			site:  withRedirect(newValidSite(testNamespace, "wlgore", "wlgore.com"), "www.wlgore.com", "https://wlgore.com/"),
+			other: withRoutes(newValidSite("gore", "gore", "www.wlgore.com"), "/blog"),
+			want:  []string{"spec.redirects[0].fromHost"},
+		},
+		{
+			name:  "redirect to a redirect host of another Site",
+			site:  withRedirect(newValidSite(testNamespace, "wlgore", "wlgore.com"), "www.wlgore.com", "https://gore.wlgore.com/"),
+			other: withRedirect(newValidSite("gore", "gore", "gore.com"), "gore.wlgore.com", "https://gore.com/"),
+			want:  []string{"spec.redirects[0].toURL"},
+		},
+		{
+			name:  "redirect host another Site redirects to",
+			site:  withRedirect(newValidSite(testNamespace, "wlgore", "wlgore.com"), "www.wlgore.com", "https://wlgore.com/"),
+			other: withRedirect(newValidSite("gore", "gore", "gore.com"), "gore.wlgore.com", "https://www.wlgore.com/"),
+			want:  []string{"spec.redirects[0].fromHost"},
+		},
+		{
+			name:  "cron of another Site",
+			site:  withCron(newValidSite(testNamespace, "wlgore", "wlgore.com"), "cron"),
+			other: withCron(newValidSite(testNamespace, "gore", "gore.com"), "cron"),
+			want:  []string{"spec.crons[0].name"},
+		},
+		{
+			name:  "cron of a Site in another namespace",
+			site:  withCron(newValidSite(testNamespace, "wlgore", "wlgore.com"), "cron"),
+			other: withCron(newValidSite("gore", "gore", "gore.com"), "cron"),
+		},
+		{
+			name:  "cron the Site already had",
+			site:  withCron(newValidSite(testNamespace, "wlgore", "wlgore.com", "www.wlgore.com"), "cron"),
+			old:   withCron(newValidSite(testNamespace, "wlgore", "wlgore.com"), "cron"),
+			other: withCron(newValidSite(testNamespace, "gore", "gore.com"), "cron"),
+		},
+		{
+			name:  "cron named like the backup CronJob of another Site",
+			site:  withCron(newValidSite(testNamespace, "wlgore", "wlgore.com"), "gore-backups"),
+			other: withBackups(newValidSite(testNamespace, "gore", "gore.com")),
+			want:  []string{"spec.crons[0].name"},
+		},
+		{
+			name:  "backups replacing a cron of another Site",
+			site:  withBackups(newValidSite(testNamespace, "wlgore", "wlgore.com")),
+			other: withCron(newValidSite(testNamespace, "gore", "gore.com"), "wlgore-backups"),
+			want:  []string{"spec.backups"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var old runtime.Object
+			if c.old != nil {
+				old = c.old
+			}
+			errs := validateSite(context.TODO(), newTestClient(t, c.other), c.site, old)
+			if got := errorFields(errs); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("validateSite returned errors for %v, want %v: %v", got, c.want, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	fnv1alpha1 "github.com/acquia/fn-drupal-operator/pkg/apis/fnresources/v1alpha1"
+)
+
+// The webhook server bootstraps itself: it generates a self-signed CA and serving certificate, and installs the
+// MutatingWebhookConfiguration and ValidatingWebhookConfiguration with that CA. In a cluster, the certificate is kept
+// in a Secret in the operator's namespace and the API server reaches the operator through a Service it creates. When
+// the operator runs locally, WEBHOOK_HOST must be set to an address of this machine that the API server can reach;
+// without it, no webhooks are served.
+const (
+	serverName                  = "fn-drupal-operator-webhooks"
+	serverPort                  = 9876
+	serviceName                 = "fn-drupal-operator-webhooks"
+	certSecretName              = "fn-drupal-operator-webhook-cert"
+	mutatingWebhookConfigName   = "fn-drupal-operator-mutating"
+	validatingWebhookConfigName = "fn-drupal-operator-validating"
+	webhookNameSuffix           = ".fnresources.acquia.io"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// defaulters applies the generated SetObjectDefaults_* functions
+var defaulters = runtime.NewScheme()
+
+func init() {
+	if err := fnv1alpha1.RegisterDefaults(defaulters); err != nil {
+		panic(err)
+	}
+}
+
+// AddToManager adds the webhook server, with the defaulting and validating webhooks of the fnresources kinds, to the
+// Manager. It does nothing if ENABLE_WEBHOOKS is "false".
+func AddToManager(mgr manager.Manager) error {
+	if os.Getenv("ENABLE_WEBHOOKS") == "false" {
+		log.Info("Webhooks are disabled")
+		return nil
+	}
+
+	bootstrap, certDir, err := bootstrapOptions()
+	if err != nil {
+		return err
+	}
+	if bootstrap == nil {
+		log.Info("Not serving webhooks: running outside the cluster and WEBHOOK_HOST isn't set")
+		return nil
+	}
+
+	server, err := webhook.NewServer(serverName, mgr, webhook.ServerOptions{
+		Port:             serverPort,
+		CertDir:          certDir,
+		BootstrapOptions: bootstrap,
+	})
+	if err != nil {
+		return err
+	}
+
+	webhooks := []struct {
+		name     string
+		object   runtime.Object
+		mutating bool
+		handler  admission.Handler
+	}{
+		{"default-drupalenvironment", &fnv1alpha1.DrupalEnvironment{}, true, defaultingHandler(&fnv1alpha1.DrupalEnvironment{})},
+		{"default-site", &fnv1alpha1.Site{}, true, defaultingHandler(&fnv1alpha1.Site{})},
+		{"validate-drupalapplication", &fnv1alpha1.DrupalApplication{}, false, validatingHandler(&fnv1alpha1.DrupalApplication{}, validateApplication)},
+		{"validate-drupalenvironment", &fnv1alpha1.DrupalEnvironment{}, false, validatingHandler(&fnv1alpha1.DrupalEnvironment{}, validateEnvironment)},
+		{"validate-site", &fnv1alpha1.Site{}, false, validatingHandler(&fnv1alpha1.Site{}, validateSite)},
+	}
+
+	var built []webhook.Webhook
+	for _, w := range webhooks {
+		b := builder.NewWebhookBuilder().
+			Name(w.name+webhookNameSuffix).
+			Path("/"+w.name).
+			Operations(admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update).
+			WithManager(mgr).
+			ForType(w.object).
+			Handlers(w.handler)
+		if w.mutating {
+			// Controllers handle unset fields, so a missing default doesn't need to block the request
+			b = b.Mutating().FailurePolicy(admissionregistrationv1beta1.Ignore)
+		} else {
+			b = b.Validating().FailurePolicy(admissionregistrationv1beta1.Fail)
+		}
+
+		wh, err := b.Build()
+		if err != nil {
+			return err
+		}
+		built = append(built, wh)
+	}
+
+	log.Info("Serving webhooks", "Port", serverPort)
+	return server.Register(built...)
+}
+
+// bootstrapOptions returns how the webhook server's certificate and configuration are set up, and the directory the
+// certificate is written to. It returns nil options if webhooks can't be served.
+func bootstrapOptions() (*webhook.BootstrapOptions, string, error) {
+	options := &webhook.BootstrapOptions{
+		MutatingWebhookConfigName:   mutatingWebhookConfigName,
+		ValidatingWebhookConfigName: validatingWebhookConfigName,
+	}
+
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err == k8sutil.ErrNoNamespace || err == k8sutil.ErrRunLocal {
+		host := os.Getenv("WEBHOOK_HOST")
+		if host == "" {
+			return nil, "", nil
+		}
+		options.Host = &host
+		return options, filepath.Join(os.TempDir(), serverName), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	operatorName, err := k8sutil.GetOperatorName()
+	if err != nil {
+		return nil, "", err
+	}
+	options.Secret = &apitypes.NamespacedName{Namespace: namespace, Name: certSecretName}
+	options.Service = &webhook.Service{
+		Namespace: namespace,
+		Name:      serviceName,
+		Selectors: map[string]string{"name": operatorName},
+	}
+	return options, "/tmp/cert", nil
+}
+
+// defaulter sets defaults on an fnresources object, through the patch it returns
+type defaulter struct {
+	object  runtime.Object
+	decoder types.Decoder
+}
+
+func defaultingHandler(object runtime.Object) *defaulter {
+	return &defaulter{object: object}
+}
+
+var _ inject.Decoder = &defaulter{}
+
+// InjectDecoder is called by the webhook server
+func (d *defaulter) InjectDecoder(decoder types.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle returns a patch setting the defaults of the object in the request
+func (d *defaulter) Handle(ctx context.Context, req types.Request) types.Response {
+	obj := d.object.DeepCopyObject()
+	if err := d.decoder.Decode(req, obj); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	defaulted := obj.DeepCopyObject()
+	defaulters.Default(defaulted)
+	return admission.PatchResponse(obj, defaulted)
+}
+
+// validateFunc returns the problems with a new or updated object. old is nil when the object is being created.
+type validateFunc func(ctx context.Context, c client.Client, obj, old runtime.Object) field.ErrorList
+
+// validator rejects fnresources objects with invalid specs
+type validator struct {
+	object   runtime.Object
+	validate validateFunc
+	client   client.Client
+	decoder  types.Decoder
+}
+
+func validatingHandler(object runtime.Object, validate validateFunc) *validator {
+	return &validator{object: object, validate: validate}
+}
+
+var _ inject.Client = &validator{}
+var _ inject.Decoder = &validator{}
+
+// InjectClient is called by the webhook server
+func (v *validator) InjectClient(c client.Client) error {
+	v.client = c
+	return nil
+}
+
+// InjectDecoder is called by the webhook server
+func (v *validator) InjectDecoder(decoder types.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle allows the request unless the object in it is invalid. Objects being deleted are always allowed, so that
+// their finalizers can be removed.
+func (v *validator) Handle(ctx context.Context, req types.Request) types.Response {
+	obj := v.object.DeepCopyObject()
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	if accessor.GetDeletionTimestamp() != nil {
+		return admission.ValidationResponse(true, "")
+	}
+
+	var old runtime.Object
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		old = v.object.DeepCopyObject()
+		if err := json.Unmarshal(req.AdmissionRequest.OldObject.Raw, old); err != nil {
+			return admission.ErrorResponse(http.StatusBadRequest, err)
+		}
+	}
+
+	if errs := v.validate(ctx, v.client, obj, old); len(errs) > 0 {
+		return admission.ValidationResponse(false, errs.ToAggregate().Error())
+	}
+	return admission.ValidationResponse(true, "")
+}